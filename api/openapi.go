@@ -19,9 +19,124 @@ import (
 )
 
 const (
+	ApiKeyAuthScopes = "apiKeyAuth.Scopes"
 	BearerAuthScopes = "bearerAuth.Scopes"
 )
 
+// Defines values for ApiKeyScope.
+const (
+	ClassesRead   ApiKeyScope = "classes:read"
+	ClassesWrite  ApiKeyScope = "classes:write"
+	GradesRead    ApiKeyScope = "grades:read"
+	GradesWrite   ApiKeyScope = "grades:write"
+	StudentsRead  ApiKeyScope = "students:read"
+	StudentsWrite ApiKeyScope = "students:write"
+	TeachersRead  ApiKeyScope = "teachers:read"
+	TeachersWrite ApiKeyScope = "teachers:write"
+)
+
+// Defines values for Role.
+const (
+	RoleAdmin    Role = "admin"
+	RoleGuardian Role = "guardian"
+	RoleStudent  Role = "student"
+	RoleTeacher  Role = "teacher"
+)
+
+// Defines values for TeacherStatus.
+const (
+	Active   TeacherStatus = "active"
+	Disabled TeacherStatus = "disabled"
+	Pending  TeacherStatus = "pending"
+)
+
+// ApiKey defines model for ApiKey.
+type ApiKey struct {
+	// CreatedAt An RFC3339 date/time string
+	CreatedAt DateTime `json:"createdAt"`
+
+	// Id A cuid
+	Id Cuid `json:"id"`
+
+	// Name What the key is used for.
+	Name string `json:"name"`
+
+	// Prefix The start of the key, to tell keys apart.
+	Prefix string `json:"prefix"`
+
+	// RevokedAt An RFC3339 date/time string
+	RevokedAt *DateTime     `json:"revokedAt,omitempty"`
+	Scopes    []ApiKeyScope `json:"scopes"`
+}
+
+// ApiKeyScope An area of the API a key may read or change.
+type ApiKeyScope string
+
+// ApiKeysCreateRequest defines model for ApiKeysCreateRequest.
+type ApiKeysCreateRequest struct {
+	Name   string        `json:"name"`
+	Scopes []ApiKeyScope `json:"scopes"`
+}
+
+// ApiKeysCreateResponse defines model for ApiKeysCreateResponse.
+type ApiKeysCreateResponse struct {
+	ApiKey ApiKey `json:"apiKey"`
+
+	// Key The key to send in the `Authorization: ApiKey <key>` header. It is not stored, so it can't be shown again.
+	Key string `json:"key"`
+}
+
+// ApiKeysListResponse defines model for ApiKeysListResponse.
+type ApiKeysListResponse struct {
+	ApiKeys []ApiKey `json:"apiKeys"`
+}
+
+// AuditListResponse defines model for AuditListResponse.
+type AuditListResponse struct {
+	Pagination PaginationData `json:"pagination"`
+	Records    []AuditRecord  `json:"records"`
+}
+
+// AuditRecord defines model for AuditRecord.
+type AuditRecord struct {
+	// ActorId A cuid
+	ActorId Cuid `json:"actorId"`
+
+	// After The record after the change, missing for deletes.
+	After *map[string]interface{} `json:"after,omitempty"`
+
+	// At An RFC3339 date/time string
+	At DateTime `json:"at"`
+
+	// Before The record before the change, missing for creates.
+	Before   *map[string]interface{} `json:"before,omitempty"`
+	ClientIp string                  `json:"clientIp"`
+
+	// Operation The operationId of the operation that made the change.
+	Operation string `json:"operation"`
+
+	// ResourceId A cuid
+	ResourceId Cuid `json:"resourceId"`
+
+	// Sequence Orders records in the order they were made.
+	Sequence int64 `json:"sequence"`
+}
+
+// AuthActivateRequest defines model for AuthActivateRequest.
+type AuthActivateRequest struct {
+	Password Password `json:"password"`
+	Token    string   `json:"token"`
+}
+
+// AuthLoginChallenge defines model for AuthLoginChallenge.
+type AuthLoginChallenge struct {
+	// ChallengeToken The token to complete the login with.
+	ChallengeToken string `json:"challengeToken"`
+
+	// ExpiresIn The number of seconds until the challenge token expires.
+	ExpiresIn int `json:"expiresIn"`
+}
+
 // AuthLoginRequest defines model for AuthLoginRequest.
 type AuthLoginRequest struct {
 	Email    string `json:"email"`
@@ -30,9 +145,67 @@ type AuthLoginRequest struct {
 
 // AuthLoginResponse defines model for AuthLoginResponse.
 type AuthLoginResponse struct {
+	// ExpiresIn The number of seconds until the access token expires.
+	ExpiresIn int `json:"expiresIn"`
+
+	// RefreshToken A single-use token to get a new access token with.
+	RefreshToken string `json:"refreshToken"`
+
+	// Token The access token, to be sent as a bearer token.
 	Token string `json:"token"`
 }
 
+// AuthLoginVerifyRequest defines model for AuthLoginVerifyRequest.
+type AuthLoginVerifyRequest struct {
+	ChallengeToken string `json:"challengeToken"`
+
+	// Code A code from the authenticator app, or a recovery code.
+	Code string `json:"code"`
+}
+
+// AuthPasswordChangeRequest defines model for AuthPasswordChangeRequest.
+type AuthPasswordChangeRequest struct {
+	CurrentPassword string   `json:"currentPassword"`
+	NewPassword     Password `json:"newPassword"`
+}
+
+// AuthPasswordResetConfirmRequest defines model for AuthPasswordResetConfirmRequest.
+type AuthPasswordResetConfirmRequest struct {
+	NewPassword Password `json:"newPassword"`
+	Token       string   `json:"token"`
+}
+
+// AuthPasswordResetRequest defines model for AuthPasswordResetRequest.
+type AuthPasswordResetRequest struct {
+	Email string `json:"email"`
+}
+
+// AuthRefreshRequest defines model for AuthRefreshRequest.
+type AuthRefreshRequest struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
+// AuthTotpCodeRequest defines model for AuthTotpCodeRequest.
+type AuthTotpCodeRequest struct {
+	// Code A code from the authenticator app, or a recovery code.
+	Code string `json:"code"`
+}
+
+// AuthTotpConfirmResponse defines model for AuthTotpConfirmResponse.
+type AuthTotpConfirmResponse struct {
+	// RecoveryCodes Single-use codes to log in with when the app is lost.
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+// AuthTotpEnrollResponse defines model for AuthTotpEnrollResponse.
+type AuthTotpEnrollResponse struct {
+	// Secret The base32-encoded secret, for apps that can't scan the URI.
+	Secret string `json:"secret"`
+
+	// Uri The otpauth URI, usually shown as a QR code.
+	Uri string `json:"uri"`
+}
+
 // Class defines model for Class.
 type Class struct {
 	// CreatedAt An RFC3339 date/time string
@@ -51,10 +224,19 @@ type Class struct {
 	StartDate  DateTime `json:"startDate"`
 	StudentIds *[]Cuid  `json:"studentIds,omitempty"`
 
+	// TeacherIds The teachers assigned to the class.
+	TeacherIds *[]Cuid `json:"teacherIds,omitempty"`
+
 	// UpdatedAt An RFC3339 date/time string
 	UpdatedAt DateTime `json:"updatedAt"`
 }
 
+// ClassEnrollmentRequest Enrolls a student in a class.
+type ClassEnrollmentRequest struct {
+	// StudentId A cuid
+	StudentId Cuid `json:"studentId"`
+}
+
 // ClassList An array of Classes
 type ClassList = []Class
 
@@ -77,6 +259,9 @@ type ClassesCreateRequest struct {
 
 	// StudentIds A list of student IDs.
 	StudentIds *[]Cuid `json:"studentIds,omitempty"`
+
+	// TeacherIds A list of teacher IDs. Only admins may set it.
+	TeacherIds *[]Cuid `json:"teacherIds,omitempty"`
 }
 
 // ClassesCreateResponse defines model for ClassesCreateResponse.
@@ -110,6 +295,9 @@ type ClassesUpdateRequest struct {
 
 	// StudentIds An array of Student IDs.
 	StudentIds *[]Cuid `json:"studentIds,omitempty"`
+
+	// TeacherIds An array of Teacher IDs. Only admins may set it.
+	TeacherIds *[]Cuid `json:"teacherIds,omitempty"`
 }
 
 // ClassesUpdateResponse defines model for ClassesUpdateResponse.
@@ -130,6 +318,9 @@ type Error struct {
 
 	// Message A human readable error message
 	Message string `json:"message"`
+
+	// Reason A machine readable reason, for errors clients handle specially.
+	Reason *string `json:"reason,omitempty"`
 }
 
 // Grade defines model for Grade.
@@ -151,6 +342,23 @@ type Grade struct {
 // GradeList An array of Grades
 type GradeList = []Grade
 
+// GradeVersion defines model for GradeVersion.
+type GradeVersion struct {
+	// ChangedAt An RFC3339 date/time string
+	ChangedAt DateTime `json:"changedAt"`
+
+	// ChangedBy A cuid
+	ChangedBy *Cuid   `json:"changedBy,omitempty"`
+	Reason    *string `json:"reason,omitempty"`
+
+	// RevertOf The version whose value this version restored, if it is a revert.
+	RevertOf *int `json:"revertOf,omitempty"`
+	Value    int  `json:"value"`
+
+	// Version Counts the values of the grade, starting at 1.
+	Version int `json:"version"`
+}
+
 // GradesCreateRequest defines model for GradesCreateRequest.
 type GradesCreateRequest struct {
 	// StudentId A cuid
@@ -165,6 +373,11 @@ type GradesCreateResponse struct {
 	Grade Grade `json:"grade"`
 }
 
+// GradesHistoryResponse defines model for GradesHistoryResponse.
+type GradesHistoryResponse struct {
+	Versions []GradeVersion `json:"versions"`
+}
+
 // GradesListResponse The response for the /v1/classes/{id}/grades endpoint
 type GradesListResponse struct {
 	// Grades An array of Grades
@@ -172,8 +385,25 @@ type GradesListResponse struct {
 	Pagination PaginationData `json:"pagination"`
 }
 
+// GradesRevertRequest defines model for GradesRevertRequest.
+type GradesRevertRequest struct {
+	// Reason Why the grade is reverted. Required once the term of the class has closed.
+	Reason *string `json:"reason,omitempty"`
+
+	// Version The version whose value to restore.
+	Version int `json:"version"`
+}
+
+// GradesRevertResponse defines model for GradesRevertResponse.
+type GradesRevertResponse struct {
+	Grade Grade `json:"grade"`
+}
+
 // GradesUpdateRequest defines model for GradesUpdateRequest.
 type GradesUpdateRequest struct {
+	// Reason Why the grade is changed. Required once the term of the class has closed.
+	Reason *string `json:"reason,omitempty"`
+
 	// Value The grade for the provided student.
 	Value *int `json:"value,omitempty"`
 }
@@ -183,6 +413,43 @@ type GradesUpdateResponse struct {
 	Grade Grade `json:"grade"`
 }
 
+// Jwk A public key in the JSON Web Key format (RFC 7517).
+type Jwk struct {
+	// Alg The signing algorithm, RS256 or EdDSA.
+	Alg string `json:"alg"`
+
+	// Crv The curve of OKP keys.
+	Crv *string `json:"crv,omitempty"`
+
+	// E The base64url-encoded exponent of RSA keys.
+	E *string `json:"e,omitempty"`
+
+	// Kid The key id, matching the kid header of tokens.
+	Kid string `json:"kid"`
+
+	// Kty The key type, RSA or OKP.
+	Kty string `json:"kty"`
+
+	// N The base64url-encoded modulus of RSA keys.
+	N   *string `json:"n,omitempty"`
+	Use string  `json:"use"`
+
+	// X The base64url-encoded public key of OKP keys.
+	X *string `json:"x,omitempty"`
+}
+
+// JwksResponse A JSON Web Key Set (RFC 7517).
+type JwksResponse struct {
+	Keys []Jwk `json:"keys"`
+}
+
+// MultiError defines model for MultiError.
+type MultiError struct {
+	// Code The HTTP error code
+	Code     int      `json:"code"`
+	Messages []string `json:"messages"`
+}
+
 // PaginationData defines model for PaginationData.
 type PaginationData struct {
 	FirstUrl string `json:"firstUrl"`
@@ -194,6 +461,12 @@ type PaginationData struct {
 	Total    int    `json:"total"`
 }
 
+// Password defines model for Password.
+type Password = string
+
+// Role What a user is allowed to do.
+type Role string
+
 // Student defines model for Student.
 type Student struct {
 	// ClassId A cuid
@@ -251,6 +524,12 @@ type Teacher struct {
 	// Id A cuid
 	Id Cuid `json:"id"`
 
+	// Role What a user is allowed to do.
+	Role Role `json:"role"`
+
+	// Status Whether a teacher may log in. Teachers are pending until they activate their account with the invitation they were sent.
+	Status TeacherStatus `json:"status"`
+
 	// UpdatedAt An RFC3339 date/time string
 	UpdatedAt DateTime `json:"updatedAt"`
 }
@@ -258,10 +537,16 @@ type Teacher struct {
 // TeacherList An array of Teachers
 type TeacherList = []Teacher
 
+// TeacherStatus Whether a teacher may log in. Teachers are pending until they activate their account with the invitation they were sent.
+type TeacherStatus string
+
 // TeachersCreateRequest defines model for TeachersCreateRequest.
 type TeachersCreateRequest struct {
 	Email    string `json:"email"`
 	FullName string `json:"fullName"`
+
+	// Role What a user is allowed to do.
+	Role *Role `json:"role,omitempty"`
 }
 
 // TeachersCreateResponse defines model for TeachersCreateResponse.
@@ -281,6 +566,12 @@ type TeachersListResponse struct {
 type TeachersUpdateRequest struct {
 	Email    string `json:"email"`
 	FullName string `json:"fullName"`
+
+	// Role What a user is allowed to do.
+	Role *Role `json:"role,omitempty"`
+
+	// Status Whether a teacher may log in. Teachers are pending until they activate their account with the invitation they were sent.
+	Status *TeacherStatus `json:"status,omitempty"`
 }
 
 // TeachersUpdateResponse defines model for TeachersUpdateResponse.
@@ -288,6 +579,43 @@ type TeachersUpdateResponse struct {
 	Teacher Teacher `json:"teacher"`
 }
 
+// AuditListParams defines parameters for AuditList.
+type AuditListParams struct {
+	// PerPage The number of results to retrieve in each page.
+	PerPage *int `form:"perPage,omitempty" json:"perPage,omitempty"`
+
+	// Page The page to load.
+	Page *int `form:"page,omitempty" json:"page,omitempty"`
+
+	// ActorId Only list changes made by this user.
+	ActorId *Cuid `form:"actorId,omitempty" json:"actorId,omitempty"`
+
+	// Operation Only list changes made by this operation, such as `gradesUpdate`.
+	Operation *string `form:"operation,omitempty" json:"operation,omitempty"`
+
+	// ResourceId Only list changes of this record.
+	ResourceId *Cuid `form:"resourceId,omitempty" json:"resourceId,omitempty"`
+
+	// Since Only list changes made at or after this time.
+	Since *DateTime `form:"since,omitempty" json:"since,omitempty"`
+
+	// Until Only list changes made at or before this time.
+	Until *DateTime `form:"until,omitempty" json:"until,omitempty"`
+}
+
+// AuthOidcCallbackParams defines parameters for AuthOidcCallback.
+type AuthOidcCallbackParams struct {
+	// Code The authorization code issued by the identity provider.
+	Code *string `form:"code,omitempty" json:"code,omitempty"`
+
+	// State The state sent to the identity provider.
+	State *string `form:"state,omitempty" json:"state,omitempty"`
+
+	// Error Why the identity provider didn't issue a code.
+	Error            *string `form:"error,omitempty" json:"error,omitempty"`
+	ErrorDescription *string `form:"error_description,omitempty" json:"error_description,omitempty"`
+}
+
 // ClassesListParams defines parameters for ClassesList.
 type ClassesListParams struct {
 	// PerPage The number of results to retrieve in each page.
@@ -324,9 +652,36 @@ type TeachersListParams struct {
 	Page *int `form:"page,omitempty" json:"page,omitempty"`
 }
 
+// ApiKeysCreateJSONRequestBody defines body for ApiKeysCreate for application/json ContentType.
+type ApiKeysCreateJSONRequestBody = ApiKeysCreateRequest
+
+// AuthActivateJSONRequestBody defines body for AuthActivate for application/json ContentType.
+type AuthActivateJSONRequestBody = AuthActivateRequest
+
 // AuthLoginJSONRequestBody defines body for AuthLogin for application/json ContentType.
 type AuthLoginJSONRequestBody = AuthLoginRequest
 
+// AuthLoginVerifyJSONRequestBody defines body for AuthLoginVerify for application/json ContentType.
+type AuthLoginVerifyJSONRequestBody = AuthLoginVerifyRequest
+
+// AuthPasswordChangeJSONRequestBody defines body for AuthPasswordChange for application/json ContentType.
+type AuthPasswordChangeJSONRequestBody = AuthPasswordChangeRequest
+
+// AuthPasswordResetJSONRequestBody defines body for AuthPasswordReset for application/json ContentType.
+type AuthPasswordResetJSONRequestBody = AuthPasswordResetRequest
+
+// AuthPasswordResetConfirmJSONRequestBody defines body for AuthPasswordResetConfirm for application/json ContentType.
+type AuthPasswordResetConfirmJSONRequestBody = AuthPasswordResetConfirmRequest
+
+// AuthRefreshJSONRequestBody defines body for AuthRefresh for application/json ContentType.
+type AuthRefreshJSONRequestBody = AuthRefreshRequest
+
+// AuthTotpConfirmJSONRequestBody defines body for AuthTotpConfirm for application/json ContentType.
+type AuthTotpConfirmJSONRequestBody = AuthTotpCodeRequest
+
+// AuthTotpDisableJSONRequestBody defines body for AuthTotpDisable for application/json ContentType.
+type AuthTotpDisableJSONRequestBody = AuthTotpCodeRequest
+
 // ClassesCreateJSONRequestBody defines body for ClassesCreate for application/json ContentType.
 type ClassesCreateJSONRequestBody = ClassesCreateRequest
 
@@ -339,6 +694,9 @@ type GradesCreateJSONRequestBody = GradesCreateRequest
 // GradesUpdateJSONRequestBody defines body for GradesUpdate for application/json ContentType.
 type GradesUpdateJSONRequestBody = GradesUpdateRequest
 
+// GradesRevertJSONRequestBody defines body for GradesRevert for application/json ContentType.
+type GradesRevertJSONRequestBody = GradesRevertRequest
+
 // StudentsCreateJSONRequestBody defines body for StudentsCreate for application/json ContentType.
 type StudentsCreateJSONRequestBody = StudentsCreateRequest
 
@@ -353,12 +711,63 @@ type TeachersUpdateJSONRequestBody = TeachersUpdateRequest
 
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
+	// List the public keys tokens are signed with, so other services can check them.
+	// (GET /.well-known/jwks.json)
+	AuthJwks(c *gin.Context)
+	// List the API keys of the current user.
+	// (GET /v1/api-keys)
+	ApiKeysList(c *gin.Context)
+	// Create an API key for the current user.
+	// (POST /v1/api-keys)
+	ApiKeysCreate(c *gin.Context)
+	// Revoke an API key.
+	// (DELETE /v1/api-keys/{id})
+	ApiKeysRevoke(c *gin.Context, id Cuid)
+	// List the audit log, newest first.
+	// (GET /v1/audit)
+	AuditList(c *gin.Context, params AuditListParams)
+	// Activate an invited account by choosing its password.
+	// (POST /v1/auth/activate)
+	AuthActivate(c *gin.Context)
 	// Generate a JWT to use as a bearer token for authentication.
 	// (POST /v1/auth/login)
 	AuthLogin(c *gin.Context)
+	// Complete a login with two-factor authentication.
+	// (POST /v1/auth/login/verify)
+	AuthLoginVerify(c *gin.Context)
+	// Revoke the access token and every refresh token of the current session.
+	// (POST /v1/auth/logout)
+	AuthLogout(c *gin.Context)
 	// Use a JWT to get the currently-authenticated user.
 	// (GET /v1/auth/me)
 	AuthCurrentUser(c *gin.Context)
+	// Complete a login with the school's identity provider.
+	// (GET /v1/auth/oidc/callback)
+	AuthOidcCallback(c *gin.Context, params AuthOidcCallbackParams)
+	// Log in with the school's identity provider.
+	// (GET /v1/auth/oidc/start)
+	AuthOidcStart(c *gin.Context)
+	// Change the password of the current user.
+	// (POST /v1/auth/password/change)
+	AuthPasswordChange(c *gin.Context)
+	// Send a password reset token to a teacher.
+	// (POST /v1/auth/password/reset)
+	AuthPasswordReset(c *gin.Context)
+	// Choose a new password with a password reset token.
+	// (POST /v1/auth/password/reset/confirm)
+	AuthPasswordResetConfirm(c *gin.Context)
+	// Exchange a refresh token for a new access token and refresh token.
+	// (POST /v1/auth/refresh)
+	AuthRefresh(c *gin.Context)
+	// Enable two-factor authentication with a first code from the app.
+	// (POST /v1/auth/totp/confirm)
+	AuthTotpConfirm(c *gin.Context)
+	// Disable two-factor authentication for the current user.
+	// (POST /v1/auth/totp/disable)
+	AuthTotpDisable(c *gin.Context)
+	// Start setting up two-factor authentication for the current user.
+	// (POST /v1/auth/totp/enroll)
+	AuthTotpEnroll(c *gin.Context)
 	// List all classes
 	// (GET /v1/classes)
 	ClassesList(c *gin.Context, params ClassesListParams)
@@ -389,6 +798,12 @@ type ServerInterface interface {
 	// Update a grade by its CUID
 	// (PATCH /v1/classes/{id}/grades/{grade})
 	GradesUpdate(c *gin.Context, id Cuid, grade Cuid)
+	// List every value a grade has held, oldest first.
+	// (GET /v1/classes/{id}/grades/{grade}/history)
+	GradesHistory(c *gin.Context, id Cuid, grade Cuid)
+	// Restore the value of a previous version of a grade.
+	// (POST /v1/classes/{id}/grades/{grade}/revert)
+	GradesRevert(c *gin.Context, id Cuid, grade Cuid)
 	// List all students
 	// (GET /v1/students)
 	StudentsList(c *gin.Context, params StudentsListParams)
@@ -419,6 +834,9 @@ type ServerInterface interface {
 	// Update a teacher by its CUID
 	// (PATCH /v1/teachers/{id})
 	TeachersUpdate(c *gin.Context, id Cuid)
+	// Lift the login lockout of a teacher's account.
+	// (POST /v1/teachers/{id}/unlock)
+	TeachersUnlock(c *gin.Context, id Cuid)
 }
 
 // ServerInterfaceWrapper converts contexts to parameters.
@@ -430,6 +848,146 @@ type ServerInterfaceWrapper struct {
 
 type MiddlewareFunc func(c *gin.Context)
 
+// AuthJwks operation middleware
+func (siw *ServerInterfaceWrapper) AuthJwks(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+	}
+
+	siw.Handler.AuthJwks(c)
+}
+
+// ApiKeysList operation middleware
+func (siw *ServerInterfaceWrapper) ApiKeysList(c *gin.Context) {
+
+	c.Set(BearerAuthScopes, []string{""})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+	}
+
+	siw.Handler.ApiKeysList(c)
+}
+
+// ApiKeysCreate operation middleware
+func (siw *ServerInterfaceWrapper) ApiKeysCreate(c *gin.Context) {
+
+	c.Set(BearerAuthScopes, []string{""})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+	}
+
+	siw.Handler.ApiKeysCreate(c)
+}
+
+// ApiKeysRevoke operation middleware
+func (siw *ServerInterfaceWrapper) ApiKeysRevoke(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id Cuid
+
+	err = runtime.BindStyledParameter("simple", false, "id", c.Param("id"), &id)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter id: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{""})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+	}
+
+	siw.Handler.ApiKeysRevoke(c, id)
+}
+
+// AuditList operation middleware
+func (siw *ServerInterfaceWrapper) AuditList(c *gin.Context) {
+
+	var err error
+
+	c.Set(BearerAuthScopes, []string{""})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params AuditListParams
+
+	// ------------- Optional query parameter "perPage" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "perPage", c.Request.URL.Query(), &params.PerPage)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter perPage: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "page" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page", c.Request.URL.Query(), &params.Page)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter page: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "actorId" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "actorId", c.Request.URL.Query(), &params.ActorId)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter actorId: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "operation" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "operation", c.Request.URL.Query(), &params.Operation)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter operation: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "resourceId" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "resourceId", c.Request.URL.Query(), &params.ResourceId)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter resourceId: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "since" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "since", c.Request.URL.Query(), &params.Since)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter since: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "until" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "until", c.Request.URL.Query(), &params.Until)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter until: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+	}
+
+	siw.Handler.AuditList(c, params)
+}
+
+// AuthActivate operation middleware
+func (siw *ServerInterfaceWrapper) AuthActivate(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+	}
+
+	siw.Handler.AuthActivate(c)
+}
+
 // AuthLogin operation middleware
 func (siw *ServerInterfaceWrapper) AuthLogin(c *gin.Context) {
 
@@ -440,6 +998,28 @@ func (siw *ServerInterfaceWrapper) AuthLogin(c *gin.Context) {
 	siw.Handler.AuthLogin(c)
 }
 
+// AuthLoginVerify operation middleware
+func (siw *ServerInterfaceWrapper) AuthLoginVerify(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+	}
+
+	siw.Handler.AuthLoginVerify(c)
+}
+
+// AuthLogout operation middleware
+func (siw *ServerInterfaceWrapper) AuthLogout(c *gin.Context) {
+
+	c.Set(BearerAuthScopes, []string{""})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+	}
+
+	siw.Handler.AuthLogout(c)
+}
+
 // AuthCurrentUser operation middleware
 func (siw *ServerInterfaceWrapper) AuthCurrentUser(c *gin.Context) {
 
@@ -452,6 +1032,141 @@ func (siw *ServerInterfaceWrapper) AuthCurrentUser(c *gin.Context) {
 	siw.Handler.AuthCurrentUser(c)
 }
 
+// AuthOidcCallback operation middleware
+func (siw *ServerInterfaceWrapper) AuthOidcCallback(c *gin.Context) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params AuthOidcCallbackParams
+
+	// ------------- Optional query parameter "code" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "code", c.Request.URL.Query(), &params.Code)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter code: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "state" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "state", c.Request.URL.Query(), &params.State)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter state: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "error" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "error", c.Request.URL.Query(), &params.Error)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter error: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "error_description" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "error_description", c.Request.URL.Query(), &params.ErrorDescription)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter error_description: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+	}
+
+	siw.Handler.AuthOidcCallback(c, params)
+}
+
+// AuthOidcStart operation middleware
+func (siw *ServerInterfaceWrapper) AuthOidcStart(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+	}
+
+	siw.Handler.AuthOidcStart(c)
+}
+
+// AuthPasswordChange operation middleware
+func (siw *ServerInterfaceWrapper) AuthPasswordChange(c *gin.Context) {
+
+	c.Set(BearerAuthScopes, []string{""})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+	}
+
+	siw.Handler.AuthPasswordChange(c)
+}
+
+// AuthPasswordReset operation middleware
+func (siw *ServerInterfaceWrapper) AuthPasswordReset(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+	}
+
+	siw.Handler.AuthPasswordReset(c)
+}
+
+// AuthPasswordResetConfirm operation middleware
+func (siw *ServerInterfaceWrapper) AuthPasswordResetConfirm(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+	}
+
+	siw.Handler.AuthPasswordResetConfirm(c)
+}
+
+// AuthRefresh operation middleware
+func (siw *ServerInterfaceWrapper) AuthRefresh(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+	}
+
+	siw.Handler.AuthRefresh(c)
+}
+
+// AuthTotpConfirm operation middleware
+func (siw *ServerInterfaceWrapper) AuthTotpConfirm(c *gin.Context) {
+
+	c.Set(BearerAuthScopes, []string{""})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+	}
+
+	siw.Handler.AuthTotpConfirm(c)
+}
+
+// AuthTotpDisable operation middleware
+func (siw *ServerInterfaceWrapper) AuthTotpDisable(c *gin.Context) {
+
+	c.Set(BearerAuthScopes, []string{""})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+	}
+
+	siw.Handler.AuthTotpDisable(c)
+}
+
+// AuthTotpEnroll operation middleware
+func (siw *ServerInterfaceWrapper) AuthTotpEnroll(c *gin.Context) {
+
+	c.Set(BearerAuthScopes, []string{""})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+	}
+
+	siw.Handler.AuthTotpEnroll(c)
+}
+
 // ClassesList operation middleware
 func (siw *ServerInterfaceWrapper) ClassesList(c *gin.Context) {
 
@@ -459,6 +1174,8 @@ func (siw *ServerInterfaceWrapper) ClassesList(c *gin.Context) {
 
 	c.Set(BearerAuthScopes, []string{""})
 
+	c.Set(ApiKeyAuthScopes, []string{""})
+
 	// Parameter object where we will unmarshal all parameters from the context
 	var params ClassesListParams
 
@@ -490,6 +1207,8 @@ func (siw *ServerInterfaceWrapper) ClassesCreate(c *gin.Context) {
 
 	c.Set(BearerAuthScopes, []string{""})
 
+	c.Set(ApiKeyAuthScopes, []string{""})
+
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
 	}
@@ -513,6 +1232,8 @@ func (siw *ServerInterfaceWrapper) ClassesDelete(c *gin.Context) {
 
 	c.Set(BearerAuthScopes, []string{""})
 
+	c.Set(ApiKeyAuthScopes, []string{""})
+
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
 	}
@@ -536,6 +1257,8 @@ func (siw *ServerInterfaceWrapper) ClassesGet(c *gin.Context) {
 
 	c.Set(BearerAuthScopes, []string{""})
 
+	c.Set(ApiKeyAuthScopes, []string{""})
+
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
 	}
@@ -559,6 +1282,8 @@ func (siw *ServerInterfaceWrapper) ClassesUpdate(c *gin.Context) {
 
 	c.Set(BearerAuthScopes, []string{""})
 
+	c.Set(ApiKeyAuthScopes, []string{""})
+
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
 	}
@@ -582,6 +1307,8 @@ func (siw *ServerInterfaceWrapper) GradesList(c *gin.Context) {
 
 	c.Set(BearerAuthScopes, []string{""})
 
+	c.Set(ApiKeyAuthScopes, []string{""})
+
 	// Parameter object where we will unmarshal all parameters from the context
 	var params GradesListParams
 
@@ -624,6 +1351,8 @@ func (siw *ServerInterfaceWrapper) GradesCreate(c *gin.Context) {
 
 	c.Set(BearerAuthScopes, []string{""})
 
+	c.Set(ApiKeyAuthScopes, []string{""})
+
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
 	}
@@ -656,6 +1385,8 @@ func (siw *ServerInterfaceWrapper) GradesDelete(c *gin.Context) {
 
 	c.Set(BearerAuthScopes, []string{""})
 
+	c.Set(ApiKeyAuthScopes, []string{""})
+
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
 	}
@@ -688,6 +1419,8 @@ func (siw *ServerInterfaceWrapper) GradesGet(c *gin.Context) {
 
 	c.Set(BearerAuthScopes, []string{""})
 
+	c.Set(ApiKeyAuthScopes, []string{""})
+
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
 	}
@@ -720,6 +1453,8 @@ func (siw *ServerInterfaceWrapper) GradesUpdate(c *gin.Context) {
 
 	c.Set(BearerAuthScopes, []string{""})
 
+	c.Set(ApiKeyAuthScopes, []string{""})
+
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
 	}
@@ -727,6 +1462,72 @@ func (siw *ServerInterfaceWrapper) GradesUpdate(c *gin.Context) {
 	siw.Handler.GradesUpdate(c, id, grade)
 }
 
+// GradesHistory operation middleware
+func (siw *ServerInterfaceWrapper) GradesHistory(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id Cuid
+
+	err = runtime.BindStyledParameter("simple", false, "id", c.Param("id"), &id)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter id: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Path parameter "grade" -------------
+	var grade Cuid
+
+	err = runtime.BindStyledParameter("simple", false, "grade", c.Param("grade"), &grade)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter grade: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{""})
+
+	c.Set(ApiKeyAuthScopes, []string{""})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+	}
+
+	siw.Handler.GradesHistory(c, id, grade)
+}
+
+// GradesRevert operation middleware
+func (siw *ServerInterfaceWrapper) GradesRevert(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id Cuid
+
+	err = runtime.BindStyledParameter("simple", false, "id", c.Param("id"), &id)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter id: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Path parameter "grade" -------------
+	var grade Cuid
+
+	err = runtime.BindStyledParameter("simple", false, "grade", c.Param("grade"), &grade)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter grade: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{""})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+	}
+
+	siw.Handler.GradesRevert(c, id, grade)
+}
+
 // StudentsList operation middleware
 func (siw *ServerInterfaceWrapper) StudentsList(c *gin.Context) {
 
@@ -734,6 +1535,8 @@ func (siw *ServerInterfaceWrapper) StudentsList(c *gin.Context) {
 
 	c.Set(BearerAuthScopes, []string{""})
 
+	c.Set(ApiKeyAuthScopes, []string{""})
+
 	// Parameter object where we will unmarshal all parameters from the context
 	var params StudentsListParams
 
@@ -765,6 +1568,8 @@ func (siw *ServerInterfaceWrapper) StudentsCreate(c *gin.Context) {
 
 	c.Set(BearerAuthScopes, []string{""})
 
+	c.Set(ApiKeyAuthScopes, []string{""})
+
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
 	}
@@ -788,6 +1593,8 @@ func (siw *ServerInterfaceWrapper) StudentsDelete(c *gin.Context) {
 
 	c.Set(BearerAuthScopes, []string{""})
 
+	c.Set(ApiKeyAuthScopes, []string{""})
+
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
 	}
@@ -811,6 +1618,8 @@ func (siw *ServerInterfaceWrapper) StudentsGet(c *gin.Context) {
 
 	c.Set(BearerAuthScopes, []string{""})
 
+	c.Set(ApiKeyAuthScopes, []string{""})
+
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
 	}
@@ -834,6 +1643,8 @@ func (siw *ServerInterfaceWrapper) StudentsUpdate(c *gin.Context) {
 
 	c.Set(BearerAuthScopes, []string{""})
 
+	c.Set(ApiKeyAuthScopes, []string{""})
+
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
 	}
@@ -848,6 +1659,8 @@ func (siw *ServerInterfaceWrapper) TeachersList(c *gin.Context) {
 
 	c.Set(BearerAuthScopes, []string{""})
 
+	c.Set(ApiKeyAuthScopes, []string{""})
+
 	// Parameter object where we will unmarshal all parameters from the context
 	var params TeachersListParams
 
@@ -879,6 +1692,8 @@ func (siw *ServerInterfaceWrapper) TeachersCreate(c *gin.Context) {
 
 	c.Set(BearerAuthScopes, []string{""})
 
+	c.Set(ApiKeyAuthScopes, []string{""})
+
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
 	}
@@ -902,6 +1717,8 @@ func (siw *ServerInterfaceWrapper) TeachersDelete(c *gin.Context) {
 
 	c.Set(BearerAuthScopes, []string{""})
 
+	c.Set(ApiKeyAuthScopes, []string{""})
+
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
 	}
@@ -925,6 +1742,8 @@ func (siw *ServerInterfaceWrapper) TeachersGet(c *gin.Context) {
 
 	c.Set(BearerAuthScopes, []string{""})
 
+	c.Set(ApiKeyAuthScopes, []string{""})
+
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
 	}
@@ -948,6 +1767,8 @@ func (siw *ServerInterfaceWrapper) TeachersUpdate(c *gin.Context) {
 
 	c.Set(BearerAuthScopes, []string{""})
 
+	c.Set(ApiKeyAuthScopes, []string{""})
+
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
 	}
@@ -955,6 +1776,31 @@ func (siw *ServerInterfaceWrapper) TeachersUpdate(c *gin.Context) {
 	siw.Handler.TeachersUpdate(c, id)
 }
 
+// TeachersUnlock operation middleware
+func (siw *ServerInterfaceWrapper) TeachersUnlock(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id Cuid
+
+	err = runtime.BindStyledParameter("simple", false, "id", c.Param("id"), &id)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter id: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{""})
+
+	c.Set(ApiKeyAuthScopes, []string{""})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+	}
+
+	siw.Handler.TeachersUnlock(c, id)
+}
+
 // GinServerOptions provides options for the Gin server.
 type GinServerOptions struct {
 	BaseURL      string
@@ -984,10 +1830,44 @@ func RegisterHandlersWithOptions(router *gin.Engine, si ServerInterface, options
 		ErrorHandler:       errorHandler,
 	}
 
+	router.GET(options.BaseURL+"/.well-known/jwks.json", wrapper.AuthJwks)
+
+	router.GET(options.BaseURL+"/v1/api-keys", wrapper.ApiKeysList)
+
+	router.POST(options.BaseURL+"/v1/api-keys", wrapper.ApiKeysCreate)
+
+	router.DELETE(options.BaseURL+"/v1/api-keys/:id", wrapper.ApiKeysRevoke)
+
+	router.GET(options.BaseURL+"/v1/audit", wrapper.AuditList)
+
+	router.POST(options.BaseURL+"/v1/auth/activate", wrapper.AuthActivate)
+
 	router.POST(options.BaseURL+"/v1/auth/login", wrapper.AuthLogin)
 
+	router.POST(options.BaseURL+"/v1/auth/login/verify", wrapper.AuthLoginVerify)
+
+	router.POST(options.BaseURL+"/v1/auth/logout", wrapper.AuthLogout)
+
 	router.GET(options.BaseURL+"/v1/auth/me", wrapper.AuthCurrentUser)
 
+	router.GET(options.BaseURL+"/v1/auth/oidc/callback", wrapper.AuthOidcCallback)
+
+	router.GET(options.BaseURL+"/v1/auth/oidc/start", wrapper.AuthOidcStart)
+
+	router.POST(options.BaseURL+"/v1/auth/password/change", wrapper.AuthPasswordChange)
+
+	router.POST(options.BaseURL+"/v1/auth/password/reset", wrapper.AuthPasswordReset)
+
+	router.POST(options.BaseURL+"/v1/auth/password/reset/confirm", wrapper.AuthPasswordResetConfirm)
+
+	router.POST(options.BaseURL+"/v1/auth/refresh", wrapper.AuthRefresh)
+
+	router.POST(options.BaseURL+"/v1/auth/totp/confirm", wrapper.AuthTotpConfirm)
+
+	router.POST(options.BaseURL+"/v1/auth/totp/disable", wrapper.AuthTotpDisable)
+
+	router.POST(options.BaseURL+"/v1/auth/totp/enroll", wrapper.AuthTotpEnroll)
+
 	router.GET(options.BaseURL+"/v1/classes", wrapper.ClassesList)
 
 	router.POST(options.BaseURL+"/v1/classes", wrapper.ClassesCreate)
@@ -1008,6 +1888,10 @@ func RegisterHandlersWithOptions(router *gin.Engine, si ServerInterface, options
 
 	router.PATCH(options.BaseURL+"/v1/classes/:id/grades/:grade", wrapper.GradesUpdate)
 
+	router.GET(options.BaseURL+"/v1/classes/:id/grades/:grade/history", wrapper.GradesHistory)
+
+	router.POST(options.BaseURL+"/v1/classes/:id/grades/:grade/revert", wrapper.GradesRevert)
+
 	router.GET(options.BaseURL+"/v1/students", wrapper.StudentsList)
 
 	router.POST(options.BaseURL+"/v1/students", wrapper.StudentsCreate)
@@ -1028,53 +1912,130 @@ func RegisterHandlersWithOptions(router *gin.Engine, si ServerInterface, options
 
 	router.PATCH(options.BaseURL+"/v1/teachers/:id", wrapper.TeachersUpdate)
 
+	router.POST(options.BaseURL+"/v1/teachers/:id/unlock", wrapper.TeachersUnlock)
+
 	return router
 }
 
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+xcW2/bOBb+K4R2gX3xxHIuaMfAYjeT7HQzmG2LabIDbJEHRjqxmEqkSlJpPEX++4Kk",
-	"qItFXZzEmsTxUxubl8NzvnP5ePF3L2BJyihQKbz5d08EESRY//c4k9GvbEHob/A1AyHVZylnKXBJQLeA",
-	"BJNY/UcuU/DmnpCc0IV3P/FSLMQ3xkPHl/cTj8PXjHAIvfnnfIxKj8uJ7cGubiCQariKKCJlVEBTFsm+",
-	"AO2fzjRzzXESYyGa4wYcsITwWC//rxyuvbn3l2mptGmusekplnBOElBjhSACTlJJmBYJ7nCSxmq6n7Ag",
-	"AUqwjAQK9ISTpvJCItIYL9/jBOq9/6P7zfyZqxfQUImwjpgk7Gt9kpFQtaQNWfQaPlD4EH2g4JJHSMzl",
-	"uhIJmYVA5VmodU8kJGKoiLkAmHO8VH9nabiu4VawQkIvX3ndJnX7VldaWmFSAU5Vllbk/UqMh9Wg4x1T",
-	"pBeE2DXSzUAhZpheNLwcisnHOdHytfr2CoTrYp1HgCqfKOlkBEZCpYRH4d0xlWmAlC3a53pq76Ct8nTL",
-	"MYZnrKAExURIJVHeCp2dir3BQHE60IovVG3UiuESVG1ROrBBdgByVyQwXTvmVh5UnblpN55/i64Z18ab",
-	"3s6mgemNgIYpI1S5q0NmGCa19mKd/xaEYus8Xd0+Fi1PscSNVVcGmhSSdCjhQkeanVdvg1dXYv+np/Tr",
-	"PuyM67yZqUJWI1qQ6fRbGiC4icP94OYu8n3f//oHT+jbgxl5w6nLGIWOXVr97eeTg4ODH5Fa7VSSBFDe",
-	"sTrd7Me3Rz/4hz/M9s/3D+b7/vzI3zva/59rsn9xzrhDXSxsQdq/z88/IlC9kG5UmffQPyxmIFTCAria",
-	"IgEh8MK1HBRlCaaIAw7xVQz5sLZ9dUXvmUTXLKNhcw2r1jJC2UFcZnvHcQhPVC0PL0MLdxna4QFF4MS7",
-	"xXFWr3bfNG3iqhVL8ewg65SBWqX9ZaBuNrgKNHZyVIFmmJ4icG19F6prgn6hJiwyb8rZLQkhtBXLXhWq",
-	"/epuarpVo701ycJCeYAeV8QwXdunflxJMv1OwvupnqOjPDHfD5J/s9VJLki7Nnpqk01ip0eiUaGxotPG",
-	"pNeEC3nB4zrfruDiHylewN+dBVGMh3Q9cHWlcPfQrmmemop++xMvIZQkWeLNZ658lgL/2Oh0NPESfJf3",
-	"8v3eMTjcPlBNkklc7/jmqDKd3xt/UpNc7TLsiKUWS/EmpUFL+7hwkdd4LXXX8Bj8oBx8ncVxc8PpFxZR",
-	"dMqc9S7ZZBJ2ZddCxHWSaq7U/rSaNxycWK21HKnVDtWTXNdV+YpOiu4dy+7NfaLE3KDFutNwpwgPzIES",
-	"cBAB70h8D89iRaU21MomcXYlv2LALlX0JMAxANGX8TYIiHNj0SciDcXZQ6mpGxbRvZDBP5OlCCLG4j0I",
-	"M1foelnBzp6QDA96uab7g17ecHDQsyZ0BD07VE/QG9Nsbf5hVdqhut7AKUswD1LY6jFU/nmXCM8ycNrR",
-	"By68P3AWA3apoidwdoHq+UCqL/RuDFIq30GQcSKXn9QgZrorwBz4cSaj8q+fGU+wVBr4/VwlNd3am+ff",
-	"luqIpEy9ezUwodesCc4zKoHjQKJvREZoyTKOgojEIQcq/oYKhGIaKtgSjvKSXTE5SaQ2wocU6CdtPHT8",
-	"8UzxfODCDD/b8/d8pVqWAsUp8ebegf5IUREZ6eUpV8CZjKYxWxAN9JQZ7Cida/Spkro8WvaMOkHIn1i4",
-	"NBt4VOa5EKdpTALda3ojjOMYg/SZq3GKfl83nOQZ6A8MLLTs+76/iflz4GkBVncRf/n9HEmGrgBlAkId",
-	"UJT2gMp82j2l78MnFMzsmjqE+S+OSahHNJuZauKjMSa+oHCXQiAhtBNXPMebf76ceCJLEsyX3tx7B1TB",
-	"CBC2ussEICwQRsZbkL5q4NTkxJN4IZTTqm+8SzVPgVcTjBbQgtWTjHOg8kJof9wYbMoo8wiwzMawmZqZ",
-	"cfIHhH8yUApoXIgKKhYgdWoOjN3i5Q8VVUGoNMjbEVE5e3QionL0qYMfxwlInZ4/O4+4suQKuKr8OIgs",
-	"lkJJyEFyAreACEXK6ijFC9CnTKrb1wz40t6DmFd2PEodNvdKXHOrUdV0McNh6+i9Q19uEPOuc2Qn/u1p",
-	"uz06VnmsLGpQCBKTWDybuFXP9Z8v72uBTC0X4Ti2q6lgsThvvp+0ZM/asf+GMqjzvsqgLDrblAzt4FBI",
-	"z4maUegEpSzNYv2BroYSkDjEEu8Sag8wjbIRRhS+ldcMGtCsR0p9ZmIK0hjMWbwTsafm60bM1FFJFZFl",
-	"UNJ8vA61yVDY6E2BRwetOk9gX2psxciTB8orxmLAtEEO2BcXL3CjVynIHBQXvPLk4uy0OHbJgXu4efy8",
-	"Z7k037CVSLuQjLBEZ6d7LwTIBmsI54u5WiIihdZpS6ztSvXvQL5I1G7g8sgOv+Pg9x3INcCbYhlErfA1",
-	"myBjInhjBUl9U2pkWu++svVIlxgBiecRcND+gNXsVzEklU0iQtNM7vxzTf80IBjsoo6CaVpeInHmnvI2",
-	"y+tgmZNnmlJ7r6G8WvK6sLfiVvFe3ktqJbHVa2IvOzO5LvWNzJSdd+56iLK20I4oP9QJPknGLU821846",
-	"3aA9/k+/6387+bOx76j02R2N7UpfDzO3tyCfB7Mx0mwJMzeL6a6capmko0waj6G/GMcYeLS93k3anZOM",
-	"Sf8bHqILR8M4hjhMy7ZA9R72lnjNpsq6P3W/wXlf/pEu+Aq3G7YhHhTbDWsmzbzurF69dWbR6sXh3aH2",
-	"Y0HgvIbduTFgDbQlOwOivNhvwVle1m7dE6hfoN/Qybb7ocDIhL3lqUAPZc9VuCPtT3K6ba/NOyG6Ejd7",
-	"T7itQXdH3G0gtr+S8VxYgpVnS8i0XY67MqgF384C4KUedQ9ku+u/odmheWzWuwaUWwhu/eHVyz5ecL9j",
-	"G5mJtrxke7RzvEI2mgeqbeGjg501r6iqb5mciaj6EmvHRJ/oScGrPaKW5WNLi8ny8VsrEa0/SNwQEXU/",
-	"3hyZiLY8vewhorkKd0T0SYioLV2dEF0Jm71E1Bp0R0TbQGwT8HMp3bejICiIqF2OuyCoBd/O/L/lRLTt",
-	"RfF69653WN4cDV0DyC00tP4I/WXTUPevAoxMQ1te9T/aOXY09KXT0MHOOmR44LfWRTMe5z+9MJ9OYxbg",
-	"OGJCzt/6b33v/vL+/wEAAP//6tTAmMpfAAA=",
+	"H4sIAAAAAAAC/+w9a3MbN5J/BcW7q2yqaIqS7DxUtbWnyHaixBvrJDnZu8gVQzNNDqIhMAEwkrkp//cr",
+	"POeFedAyKVniJ1skCDQa/e5G469RxBYZo0ClGB38NRJRAgus/3uYkZ9gqf6XcZYBlwT05xEHLCE+lOqP",
+	"/+QwGx2M/mOnmGbHzrHzHEs4JwsYfRiPSNw3+ignsRpJ8QLU2BhExEkmCaOjg9GvCZZIJoCuYImIQLmA",
+	"GM0Yn4zGI3iPF1kKo4PR9xzHgMgiY1yOxiO5zNSnQnJC52rujMOMvG/Ofp4AEhJzidjMrTJGkiEJaar+",
+	"EAhnmMvqakxc/f6vP/evz78NrcXhml2tiiYRscxgmUhYiL6fmiM6Uz8affAwYM7xcvRBw/BnTjjEo4Pf",
+	"1AlY5Ho8+PXGpUN96+dhl39AJNXE5XUayDukCHPADnWHJ8cI62Na4CXigGPEOIoSTOeg8UfzhYInSrEQ",
+	"IA7UiNHY/3nDiVQgztVR+m/tX+5LIfNYIcJ97f92AyTgKAHuB/i/zYC35WOsAdI4SLN5caQxdAp/5iBk",
+	"kysc2bYS4wK/fwV0LpPRwe50Oh4tCPV/B1a9HSUsCD02P9vtIQtLEXa59sP3+xcZowKaCMBeXPTDqaa9",
+	"gmWTls4th0uGBNAYEapp6t1hLhPGyb+xGneAzCzoIp9O96MrWOr/wDuUAI6BT9CxVDKCMomEZBziMRIM",
+	"EYkiTL+Q6BKQSNgNRXiOCZ00z7yGIrszA3IHhl4RIfvws+qJ9rK1mzYIVx4T2Q1VhueEYoP9bnhO/Mjn",
+	"WGIj4SLG4xV2pMA51T/q3VYJrmKh1j3aSZs4jyTjx4NVD55J4GGiNCAgPUKTpBFoY7QgQhA6V7oIxZCC",
+	"BDEZBeDEK+mBS5gxDp2gmCGtsBiBHoYlSglQeZxV5dXut3uT6WRvshsSggqrnkyaMPmvj2OnB/xHSCrd",
+	"vVDCsAC2qkmNeH+TxVhCaHUOguU8guFHKZScplEAha95DFxYLAonYZj6VP1viW6AgwZXwThjfKGObkSo",
+	"/OppARuhEubAG3Tr1x174isjr7KV0kFo+giTt0wOI0muu3RPhoW4YbwXOSdunOI+dgX6MLtlnxk2LpZo",
+	"A/IVmxN6lOA0BToPCJrIfXXuFm5SkV5MyX4Fu2IlfTSpmhndEJlMQrQB7zPCQRy3zEnzxSVwRZQCIkZj",
+	"gXIqSepI0cBkV7ZTTfqPubabMhSdCGo9QlhgkgaOY1w53O6zMnMMPat2nXALjOIoAiH60akAn3EQSQs1",
+	"HCIlyFJ4kosSWcxBIowo3FTXaSUN2U5q5Qm0pa+MAqASYYEwugTMgZtv++0D2aCB2vY6z+EX4GS2bCWM",
+	"Jt80thmxOGSWI/U5mnG2MEeTywSoJBGWjCOcZWNll2MtCK+BL/Xw/s02KF+v3rZDJ3GOtNBv32TOOVB5",
+	"0k7q4xGFm5OV5Vwd+No61Vn7NnEKAuQRozPCF+1+wMeAubI4XhnslQVPULa0LXVqqL11kTqzd681iHfO",
+	"mcyOWNxBUxvlii4mMKBasmkTum5BtSfRhPusEIdqLSW5lGJEVjOimwSMIYOzTPk+KRM6WuEN86Zw7DK/",
+	"q9B0bewF5SxN2/clIOIgw0L4EgvY33sCVG0pRmboWJuvOMuEMRyNzyYibPb35vQ4KOtzTsKLMJmpQ1Y/",
+	"HKNc5DhNl87/U7L+f079GZdCO+ZHBzs7ksls53UG9CxKGEsP/mAJncQM/uvpVOhPJhDn/zCg//3H785+",
+	"/d/95ycvfjj5af/kXyfKL937igiRA/97MUkvOVmkmV2FsH+kYhafKDRXQVnZIfgOCxKhBZaJQDpIEsJ7",
+	"TESW4uXPjfDHP/XvdqdBdwJorEBYbwSxgEXv4TWF18lrGnQwdPBvVYhs1Ol4BRfYgVhlPh+fslMFLGPz",
+	"vUBYCDKnEOvgZALmXCqM/jHL51m8Kt10RBfLJFElrzKiCyIoRx/LsLQSvpE5C6BltVZFmhmi+NuekpKU",
+	"uEBXTUi5kxyGwDq3+l+3QqyCMC2RU46XypbWw0AMPkk1PHSUdp6eaGWN55sEV/rEOfRHVgTcTkAEljID",
+	"kKKe9rU+tTihrfB0w7EJUVI3WVIidGbCkfLx89uzfJfEKVa0o/SK6DVNlwjHC0KFju0LkIjIW0JS46Uy",
+	"tbRyE/QGoyOnHwfwUN2U0592rF0PqIYidOZbbckoMtq53t2xaQYENM4YobIhhOyAQVArGExw4GPDt13x",
+	"VgdJBxJMrG4rXx6CfClpobNNSZjSmuebkDF9dLxZQZKTOIATFOUkrhBD9Eca70V/vE+m0+n0z3/zBf1m",
+	"f5d8zWmIMPx5h7B9+vJof3//W6R2uyPJApD94biSAfjm2ZPp0ye7e+d7+wd704Nn08mzvf8LLfaCc8aH",
+	"Ot2K6n84Pz9BoH6l3a3yuk+nT0MRwgUIgedBHz7JF5jq3DK+TMFO68aXd/Qzk2jGchqHswpYsGDwcYGj",
+	"hFAoVjBDjWuqVxPIxO4FSjCNU0Aig4go37LqSOIoYjmVv2dAY4PuAYGEYu8h6tF55Y2XZaxoIn+UTzEe",
+	"XeM0r/puX/fG4kmpAkBnVcwkq3gVGqX9NroeNthE16NDglF/8QtwYbVjI+BL56vizf7ou+XQ0ylov6QC",
+	"idDECrHWTdpKEU5TmVg/gvfAIyJg0lLyAly+noUlwLXZMbpJmACkDwnJhAj/BQeXrSczla0nQgfi1JwV",
+	"ptoNSYtBlDMeXRdor0J4pHYu9Fb1VH7jOjc5NuVBKrmKJdqtgLPXS6Fu0RJp+kNuJcc+F25lhvQYah6N",
+	"3qO3VjPOrokOyJklKrvt58cmK/bvsU39zp2sG8BoNTDMT9uX/oEoalu2r21PbXhwp8LXfY6On70dwts5",
+	"Gjt/kfjDjsZCh9Nhvh+0s/X6HBaQdmycakHQkWIIK/Nfk2XBxkqkGIEC8QSdWlgQo5FJNUvgC8f3Go0o",
+	"wUrTMwFxVa+f+0oGdIOFqW5wh3DDGZ2XmachKFulUKucZE469knCFtHTj9c7YMEeJ3LwkVppersTvbX2",
+	"W6d87UHgRs/ux5urkM2c5ZcpiUx9rknV/Hj2+mf0K1wiVatnCmnQ305fHqGvn+1+/WUzCovTeRh9KuSt",
+	"VW86Z5zIZDFGp2d7z75CjKMX8fOzw+pJ6o9CJxTx6/ACUc6vtU/++qcTtQNRn3Dv2bPdYJUvtGe2vnqa",
+	"89Qnt+C9wbta5fTs0K/SmPGKxOE5NWbjsYqHKO9kbgqVSWyrHjWpqpRpDfi96d7TJ9OvgkvJrhLMZQZj",
+	"DSrjCi/VWV//dBKakQ5Fx4LFeZqLXmzkopbKEWQeGvd+6MIlMq2dd7djpnBlDscANdbk2sIfol1tH1bZ",
+	"4gy6eeJqlZJRxZl9dsdVW7HoP/NUkk/u1i/we7JQBd/Pvv1WlyWbv55Opx6Epstf3fGKzv9qie6qvx3G",
+	"TM2MaWBnRriQb3haJdSSKfaPDM/h78HIYoqH/HQ/yGvw/mN/mtm4StmH8WcTdK8y4CeNHz0rna+rae+a",
+	"g8P1R6JJMomrP/z6WWm5aa8dlBnicNtwMxZYLMAbFwdanE+YLoo6n1J1/9d7leL+bwLbOWVp2x0XjHIB",
+	"XPu+acpuTKY3ZuWbEzo8WlxqUMZzjnlMMC0CIdUrDsXIBig24tsS+RzuXX5U+GmWp2mzcuBHllD0nAWZ",
+	"mawz/hQKLHkQV4knWaT2R5TswMExJTs+FFVyU/WEDVZFeQ0n/ucd2+716kVBc4M2Gw4wdILwkb6zr7No",
+	"dZhvc2FCuNMetnHjcHc5zX7CLlT0eFmbIIg+P2WNBGGzS58oXu6rJQtMuYKw/14si4KwkOhap7DjVqF0",
+	"jdVKxyQQZd5LgxZvZ2bwWuSpq1fXwHu4VpGzFsh+OWsHDpazdnxIzlYRE1DiIBPgCPvaCZXGNKWaEw8H",
+	"whyQzQkV5fNLhO1VD/UX4chmj0yJp5JRhF4T6W7VuLsqwsUQrH1Q5Jr0dLYkSxnLcdUs8F83KM/B2aNM",
+	"NssOw4m8TUaNO+qY61tuk1WyECiDKKgGi/t5Fwj3Unm52QduvF95+Qm7UNGjvLoI8O7I7yNl7G2otk/D",
+	"ro1q1WYhyjmRyzM1Sfmyq6oRD8pldUdcX7F3V24GXvDVdSBqDhPyciWnB6PKz4vDw/4OrbnR4wAyf710",
+	"d/t+/PVcX4FW4I8O7LfFLImU2eiD2imhM9bc0DGVwHFkxfSS5eq6O0ljDlR8USrdpbEV69bfVbuRRJp4",
+	"mi8OV8gZlVIEo93JdDK1dzApzsjoYLSvP1J+vEw0vncmN5CmT64ou6E7f9xcickfNnQ+N8X3pQuaFl0q",
+	"TGXuI2qS0dPsTacjHe6h0ppjOMtSEulf7rgpDbEMCEYVYTCNvaYYK4JxYmyq8evBsYna+LNPCJaJcAXg",
+	"eUPhfQaRhNiElCqkPTr47e14JPLFAvPl6GCkpBuS1R3Y6KtW7bZIWxGEvnvOtE0ggF+TCIS604CiBKIr",
+	"NcdC0wGeC+3bKwp9q5ZWEh1n5ImLAM5DtyhOTZMJs75aOCVC6pgBGxdWg673KewF25liMhrXyaK4x75O",
+	"yghdl28hECsofB7GXuDSMRJNGk+nu5sgDWzlC8Rm0f31L/qS8UsSx0DvDQf8VZGiv739EGaJ7jMrkbrr",
+	"XKBCg0zIjqyHQEzVBXKQOac2vWeyfSCkzb5qaidSmdBKlKiV1YrjC5qSBZHFlQlP8wIRKZDpfGGibZML",
+	"2sYUxiwcGbUIQn7H4uWn5oiqtf2hqoQlz+FDgyt31wVDN19aF82dtWXFDdDoLzglsZ7R0ehWBtwfGWCI",
+	"B2Fv4Xn/pF8I1FSeLl8xIiEFGXCD3gjTxkHpMmtXqTt9OpuHDosK4mKEl0qYLhmFDmY3alUbWBwvQGq3",
+	"57e/jO2pjK7C8tRBjSqXjgcegr1M9LbB1E/bRaEqNvH6+/HQ/tPp0/Wv+DPzZKvQrOuGnRGFVU385DNh",
+	"Q0O+JTbs5rg8JrLVwnxhbkFrxh4jE47TrozhS9fXhbN8nnj1T1x/FYjHF9ReUGZmLJFjc/36JgEOY+cW",
+	"1draYGqb7ZgrARcUlzkax64ziPk9ZZcsNg3HbFEWkUH2dg2Rmqzd1eeCg8hTKUwVluQErkHVuCjHDqnE",
+	"ondL/8yBLwvZUGQbC3Jo5ilDa6tZzRVvHLfOvvrUCpXmbpdBkzWdLpemENcJ59BqRTeblYTbqiD48xoj",
+	"kUeJjg2UuwO962wcFAK83HyngasiqdEPpzZnPWW34anS32ctqMJStyiwnaiI0O5dGziCmGZEwyAph+5X",
+	"gsY3o+oBR4e6Pwact+v0Rxtt0gIS+dDwpLIe1HDfP0qJnyKsiWKQmKRiaxBvDeKKU2yIRissCjcgJNJF",
+	"JtXQj9LDJbUskx2XD1Lgh33k41JGqBR/Aiq1v+uCj64/yFJ/bd23CXqBo+SCujSTCktpR/sSfCbKOttB",
+	"ZVq0JluXXxzofjbILZ5+UhCq/bJaXGKfq1NxTIc8a96Yg9F9Tcy5IwFCEEbvTk7oPjf1lKKCUxlvhF6r",
+	"4WqIaWrlnI1v1w9oGZeqVhunHHCsaBJoGbGMa1S7zOY9DRQ76lXGuMY0xH53l8paZUy3bCRSINe3rSMe",
+	"rGSC7odXFghNttQEu0aerPSyu48MeYh+/PXctnVzraLLvZ4c6+1N9z49YEUXxLa0hz1ofXeBcQ6RHKPL",
+	"XLoWep725Q17MtOWdw14BNRQPToKt0pE2DS6whJVCWfnWvebewQGisK0TpwqUVFGOaEW6ZON2S1loUaE",
+	"r/yw4kzh5m/mtgt6V7sx/O5LBb6Tcs1h7pt3X5rd7G1CRDPly9MlmmGigMJSwiKTwkf73FYZLzqtmfvS",
+	"CMcxByGKjaQsuoL4d5bLd19O0DlfmrbMpVa7Maj2DXNyDdT4iYBOQfLlk0M9xDZ9Ho1tVlhLnNKAoV0s",
+	"JUM3mEjvy/ClPiLXI7rDz/5wL1XP90CBa9XjhGEuoNnfMiga+xWQlSPthumL985PDLVa9amcy2VNQJnQ",
+	"j5ZeF7SvTx9FOdXyvdKvz9zTt5dhLuhLQ6WR7ptnCFOyG6y8t0JoKjJkuWwzdEutOtetV6sNQT9n7frw",
+	"9UudrIO2sze1tUZuKqA7FdlhObwVpjVh6s0sXDayWu2zfgnKctlrw6sxQ5ND1qF8rAmiO6SYetql0Qdb",
+	"KTTQysl2trWf1+ojfEigk3QWUErVNKnmyEymkqPrLOEp6gRvoRy2hLkhwnwjSkbgHGSZ7NLlk9LhQNzM",
+	"0DdpkJE42olwml7i6Ko1c6ikEonVxHLp7rxzxCEmHCIpUAIcJqjw0ohEWAjgUqBFLpTqSBmdX1DJimL6",
+	"sc4jKnDVPnzJXUquwLm93tPTcrrNnntN4ujI7WBAMhCXC0ydIhe5MWBlaKdtKRB743SFVJR9nUqWwrqr",
+	"LKh/utqKrsNC8/hiEqt2zHrv1kpvW9dQYs+6rT/8vdY3tnWSt9tYU1es6aGFk84LuLB74glz6RiRCHTJ",
+	"2Y1QokJtuW6CGz6KGQhFxrqVwmSjHkNIIs402XiMG9dWi0JlqdPiN6WG6JeAJM+F9Gbe/kYKZNylpsSW",
+	"WBrR3YhK5VSXor/70iN+M9GvTZQJvWLzuQLfswYNHKp9fixSbf/nOb/b/IRaeO+uyDtieRprZFwC4pp8",
+	"tHO8IOISEnytUbO656V4Wd+X+EKEVWGf/aLFRkdhvTNT1EpWpLSqXmWWkCjRb9YJZcgoswJJdkHDNpMx",
+	"e4wssl6A2RsR6Aoy26xcJIzLJym5hviCRoxdEdCXCdRwN5MzlTxmrTki8MKD3WUDnWks1FTo/nSviZFD",
+	"b7p1WiClsMErFnU8GVa1qNyVNYeO4NztRsCHz4L57xUPdnPdq9LbIrfiNWeO75hocEe8WHvILoxhycD1",
+	"wrARDVvtBzflWgcfSp4xfkExMi6286ZbaL/6MNEaQ7rhF5DuaxGDd56UceXaqm1rmdYXP7axn/b05H2I",
+	"HxjaNffdHKB9l3s6JAEHAbJdEFTuNxNRqLMbe7WecS3KcMMY1SnCC6ptUq8pvWbxdrOxthmaERojlksd",
+	"U1BzYOrM1D6xod/U2oDUqLzdNUhotGhuAbJIUegwApkFMKhRd7cMf/9i/2egM5Ke9MvYLAenVqL/nci8",
+	"CNbOB6fFMqJSmpcLV5WHgkrTHSoRF9SlAobQs32jbFNkXXtJbxB1tyQ/7pfa8j5vje1sZnDsghJqmKtu",
+	"y8W9LWE7ShjTAWRldxWYrkVbS3vt4QObBOki/FKWpJX0T9SK1LTkriVWVJjkgjaKB/WPDUMIm5DxiRh9",
+	"E7TbZLRgrZE9as8o3s/Uf+P5VWWSV/D/SHL/VZoL8PfYuS2uVvUzYHZXtdPgKVO8POTwO5lfP6c4QPUp",
+	"l074e2G+ske4SAvmYISCecnRiASrUYjKGpkiMvW5C24jDlmKIwhN28bzpac718j39bdM74DxQ2+Uhui+",
+	"NYNAhE8i3L3ibSm0eSx3ZTdRT9RKCIlNy2iVK0CiPLs3juwLTaEdeTBr1Zg7GrWXgrNsiGyzGYnu2h7F",
+	"bc/twHsmVkIWdhfTV+5fbLn+sXK9DXmXVcB9YHjLZB0c39ugooXPQT8w227CuOprYa0m86SzjhfEOvqE",
+	"aRkUU9Q8QQWGL2gTxdriATpjPHKPXFhbCuKm7Ooyasz7uKMNWBW1B8JbjGn39rfrBvDm9FghyeiPIK70",
+	"frccvkkOd/7LfeNyncRUtKKjAXn2ydi99CRrsOKy9CLs4+gosc5iq9DzusEIhHuc2J5OyxX87Z304k76",
+	"uNqRM3hLHaepw2iJH9wn5SZtQR5Ya3+04KPmG+6PFn55uqc/mkbfGGUsy1P9gVbSC5A4xhLfdaBsyxwD",
+	"mMP1NNNWnH+FusEeVY0R6F4W5Jrn5uu7ajS22nFUOwqzq0rrZAOPVRiXjKWAaaONMLsKdRAOc5BCkO3B",
+	"5ZT20Zvj5/71tMnD7kRm9v/Z9SHrYSZD7wjb7V0udeJFnWuLzukyu74H+VlyzhreFN/y0OPhoe9BrsBA",
+	"mSpxb2Uh3zltY1y0NuOw+lLAhlMoNRh6jMOBbLmZ2xTcvGCL1eqXKSxKXewJzXK5lRGfoYx4Y/t0DhQT",
+	"AeN1p3gZOqiDiyeqH00vzXtpWvS+7LsNqNxhQMUyUZPnigfPWwMr5RfyP28NXX3r/06iN1UQhgVv9Alt",
+	"gzefMyOeScZd7EYfZzcrtuvBnb/0v50xHUNjGw3phLWS2+njiRa5B/Yfq6dr9v9go0Vme91WbEWjdpis",
+	"m4safTbMOfBxOI2/Bpea/WwZ9TEzqglJNbhUOxLGAx3CtC2hqu+rPf4fAueuy8S+0xhYFYRuE3uoGNiG",
+	"wLYy6bYhsBWNh34fYCchQjK+7ImN/WBHPXpjo19oWFT1SQ37Jqp/WVDvbvsM1lYirBCVM/f+rnGaF7JB",
+	"XVlIII3HiKVx8G2Oj5MTXC0mu645qe/1DcY4doXClshRwlLdJUkRugGXzYpb7ohR3QzmgqqPrECq8IXr",
+	"JTNBr1R3PPNyl9B30oBLgShArG95YcHoBdXFxFLfHuYLN5Gx3RR6opSJ8DViw8FmK1vzrFPMGSTdqXnm",
+	"QOgWtIZGXAj0XtlipjmaJVtF4wsi1AsaWzWwJjUwNv02vFzSl6XHn/0LiUKaB8tKwlXRHFwTlovyZrFj",
+	"giHaQMg8VvC3moZndsC2ZPxTUEcZm8NSnO6AtjnOT5bj9DRfMIj/qD276Y5urXXj1UXuKPVYB2JY8tGi",
+	"cJt+fDC14/ZEw2xS0x+99eOOqLYF5G2MZFH5iHMNDgMPNi3oNhiO7VWUUKcx9rkWktsdDlRADWZyP99y",
+	"1GPnKJO/W4GdWlJ1jqEeQll5dS93FLSpA9FziX4wg27zahvslf9gM2uDBYa1bi1C2qMj9lmhbXTkUz7S",
+	"tC0Av8PgiCf5gi/8R+3BEXdyaw2OVBe5o+BIHYhhwRH/GtQ2OPJAgiP2RMNsUlMfvcERR1Tb4EgbIznT",
+	"5PG6cg/VOPPBEbfBsHFWUUKdttjnGhwZWNRcvGR5m5v2W356qPxkQiMrMFNLaMSx00MIjVT3ckehkToQ",
+	"3ZbjcAbdhka20uL2oZHBAiNk2+7kVL3G3l44+JJx8/xtAu5Bd/N+nH/W3Rbwueds0Mvqs+/qPbmcqm3r",
+	"PulCoiglQKUqRORgYgAcTP1goObP856B867Mg5bXQOymNXEZTD6ut9C3THzb4M1Mlt5kVATEcmlqweye",
+	"vxCetVrYekA1GvBrxzA5T0cHo0TK7GBnJ2URThMm5ME302+mow9vP/z/AP9E316m5QAA",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
@@ -0,0 +1,79 @@
+// Package audit keeps an append-only log of the changes made through the API,
+// to answer who changed a record and when.
+//
+// Records can only be appended and read: neither the [Store] interface nor the
+// database schema allow changing or removing them.
+package audit
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/h4n-openschool/api/utils"
+)
+
+// Record is a single change in the audit log.
+type Record struct {
+	// Sequence orders records in the order they were appended. It is assigned
+	// by the store.
+	Sequence int64
+
+	// ActorId is the id of the user who made the change.
+	ActorId string
+
+	// Operation is the operationId of the API operation that made the change.
+	Operation string
+
+	// ResourceId is the id of the changed record.
+	ResourceId string
+
+	// Before is the JSON representation of the record before the change, or
+	// nil if it was created.
+	Before json.RawMessage
+
+	// After is the JSON representation of the record after the change, or nil
+	// if it was deleted.
+	After json.RawMessage
+
+	// ClientIp is the address the change was requested from.
+	ClientIp string
+
+	// At is the time the change was made.
+	At time.Time
+}
+
+// Filter selects records. Empty fields match every record.
+type Filter struct {
+	ActorId    string
+	Operation  string
+	ResourceId string
+
+	// Since and Until bound the time of the records, inclusively.
+	Since *time.Time
+	Until *time.Time
+}
+
+// Matches reports whether r is selected by f.
+func (f Filter) Matches(r Record) bool {
+	return (f.ActorId == "" || r.ActorId == f.ActorId) &&
+		(f.Operation == "" || r.Operation == f.Operation) &&
+		(f.ResourceId == "" || r.ResourceId == f.ResourceId) &&
+		(f.Since == nil || !r.At.Before(*f.Since)) &&
+		(f.Until == nil || !r.At.After(*f.Until))
+}
+
+// Store persists audit records.
+type Store interface {
+	// Append adds a record to the log, assigning its sequence.
+	Append(r Record) error
+
+	// List returns a page of the records selected by the filter, newest
+	// first.
+	List(f Filter, pq utils.PaginationQuery) ([]Record, error)
+
+	// Count returns the number of records selected by the filter.
+	Count(f Filter) (int, error)
+
+	// All returns every record, ordered by sequence.
+	All() ([]Record, error)
+}
@@ -0,0 +1,83 @@
+package audit
+
+import (
+	"sync"
+
+	"github.com/h4n-openschool/api/utils"
+)
+
+// InMemoryStore implements the [Store] interface using an in-memory slice of
+// [Record] items. It is safe for concurrent use.
+type InMemoryStore struct {
+	mu sync.Mutex
+
+	// items are the records, ordered by sequence.
+	items []Record
+}
+
+// NewInMemoryStore creates a new instance of [InMemoryStore]
+func NewInMemoryStore() *InMemoryStore {
+	return &InMemoryStore{}
+}
+
+// NewInMemoryStoreFrom creates a new instance of [InMemoryStore] holding the
+// given records, for example ones restored from a snapshot.
+func NewInMemoryStoreFrom(records []Record) *InMemoryStore {
+	return &InMemoryStore{items: append([]Record(nil), records...)}
+}
+
+func (s *InMemoryStore) All() ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Record(nil), s.items...), nil
+}
+
+func (s *InMemoryStore) Append(r Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r.Sequence = 1
+	if len(s.items) > 0 {
+		r.Sequence = s.items[len(s.items)-1].Sequence + 1
+	}
+	s.items = append(s.items, r)
+
+	return nil
+}
+
+func (s *InMemoryStore) List(f Filter, pq utils.PaginationQuery) ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var items []Record
+
+	offset := pq.Offset()
+	seen := 0
+	for k := len(s.items) - 1; k >= 0 && len(items) < pq.PerPage; k-- {
+		if !f.Matches(s.items[k]) {
+			continue
+		}
+
+		if seen >= offset {
+			items = append(items, s.items[k])
+		}
+		seen++
+	}
+
+	return items, nil
+}
+
+func (s *InMemoryStore) Count(f Filter) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	count := 0
+	for _, r := range s.items {
+		if f.Matches(r) {
+			count++
+		}
+	}
+
+	return count, nil
+}
@@ -0,0 +1,129 @@
+package audit
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/h4n-openschool/api/utils"
+)
+
+// SqlStore implements the [Store] interface on top of the `audit_log` table.
+// Queries are written to run on both PostgreSQL and SQLite.
+type SqlStore struct {
+	// DB is the database connection, or the transaction, used for every query.
+	DB utils.SqlConn
+}
+
+// NewSqlStore creates a new instance of [SqlStore]
+func NewSqlStore(db utils.SqlConn) *SqlStore {
+	return &SqlStore{DB: db}
+}
+
+func (s *SqlStore) Append(r Record) error {
+	// Times are stored in UTC, so SQLite compares them correctly as text.
+	_, err := s.DB.Exec(
+		`INSERT INTO audit_log (actor_id, operation, resource_id, before_json, after_json, client_ip, at) VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		r.ActorId, r.Operation, r.ResourceId, nullString(r.Before), nullString(r.After), r.ClientIp, r.At.UTC(),
+	)
+
+	return err
+}
+
+func (s *SqlStore) List(f Filter, pq utils.PaginationQuery) ([]Record, error) {
+	where, args := f.where()
+	args = append(args, pq.PerPage, pq.Offset())
+
+	rows, err := s.DB.Query(
+		fmt.Sprintf(
+			`SELECT sequence, actor_id, operation, resource_id, before_json, after_json, client_ip, at FROM audit_log %v ORDER BY sequence DESC LIMIT $%v OFFSET $%v`,
+			where, len(args)-1, len(args),
+		),
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanRecords(rows)
+}
+
+func (s *SqlStore) All() ([]Record, error) {
+	rows, err := s.DB.Query(`SELECT sequence, actor_id, operation, resource_id, before_json, after_json, client_ip, at FROM audit_log ORDER BY sequence`)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanRecords(rows)
+}
+
+// scanRecords reads every record out of rows, and closes them.
+func scanRecords(rows *sql.Rows) ([]Record, error) {
+	defer rows.Close()
+
+	var items []Record
+	for rows.Next() {
+		var r Record
+		var before, after sql.NullString
+		if err := rows.Scan(&r.Sequence, &r.ActorId, &r.Operation, &r.ResourceId, &before, &after, &r.ClientIp, &r.At); err != nil {
+			return nil, err
+		}
+		if before.Valid {
+			r.Before = []byte(before.String)
+		}
+		if after.Valid {
+			r.After = []byte(after.String)
+		}
+		items = append(items, r)
+	}
+
+	return items, rows.Err()
+}
+
+func (s *SqlStore) Count(f Filter) (int, error) {
+	where, args := f.where()
+
+	var count int
+	err := s.DB.QueryRow(`SELECT COUNT(*) FROM audit_log `+where, args...).Scan(&count)
+
+	return count, err
+}
+
+// where builds the WHERE clause selecting the records matched by f, and its
+// arguments.
+func (f Filter) where() (string, []any) {
+	var conditions []string
+	var args []any
+
+	add := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if f.ActorId != "" {
+		add("actor_id = $%v", f.ActorId)
+	}
+	if f.Operation != "" {
+		add("operation = $%v", f.Operation)
+	}
+	if f.ResourceId != "" {
+		add("resource_id = $%v", f.ResourceId)
+	}
+	if f.Since != nil {
+		add("at >= $%v", f.Since.UTC())
+	}
+	if f.Until != nil {
+		add("at <= $%v", f.Until.UTC())
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// nullString stores empty snapshots as NULL.
+func nullString(b []byte) sql.NullString {
+	return sql.NullString{String: string(b), Valid: b != nil}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 
 	"github.com/gin-gonic/gin"
+	"github.com/h4n-openschool/api/models"
 	"github.com/h4n-openschool/api/repos/teachers"
 )
 
@@ -29,6 +30,11 @@ func MustAuthenticate(c *gin.Context, tr teachers.TeacherRepository) bool {
 		return true
 	}
 
+	if t.Status != models.TeacherActive {
+		_ = c.AbortWithError(401, errors.New("your account is not active"))
+		return true
+	}
+
 	c.Set("user", t)
 
 	return false
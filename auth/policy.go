@@ -0,0 +1,177 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/h4n-openschool/api/models"
+	"github.com/h4n-openschool/api/repos/classes"
+	"github.com/h4n-openschool/api/repos/teachers"
+)
+
+var (
+	everyone   = []models.Role{models.RoleAdmin, models.RoleTeacher, models.RoleGuardian, models.RoleStudent}
+	staff      = []models.Role{models.RoleAdmin, models.RoleTeacher}
+	adminsOnly = []models.Role{models.RoleAdmin}
+)
+
+// Policies maps every authenticated operation of the OpenAPI specification,
+// by operationId, to the roles allowed to call it. Operations missing from
+// the map are denied to everyone.
+var Policies = map[string][]models.Role{
+	"authCurrentUser":    everyone,
+	"authLogout":         everyone,
+	"authPasswordChange": everyone,
+	"authTotpEnroll":     everyone,
+	"authTotpConfirm":    everyone,
+	"authTotpDisable":    everyone,
+
+	"apiKeysList":   everyone,
+	"apiKeysCreate": everyone,
+	"apiKeysRevoke": everyone,
+
+	"classesList":   everyone,
+	"classesGet":    everyone,
+	"classesCreate": adminsOnly,
+	"classesUpdate": staff,
+	"classesDelete": adminsOnly,
+
+	"gradesList":    staff,
+	"gradesGet":     staff,
+	"gradesCreate":  staff,
+	"gradesUpdate":  staff,
+	"gradesDelete":  staff,
+	"gradesHistory": staff,
+	"gradesRevert":  adminsOnly,
+
+	"teachersList":   everyone,
+	"teachersGet":    everyone,
+	"teachersCreate": adminsOnly,
+	"teachersUpdate": adminsOnly,
+	"teachersDelete": adminsOnly,
+	"teachersUnlock": adminsOnly,
+
+	"studentsList":   staff,
+	"studentsGet":    staff,
+	"studentsCreate": staff,
+	"studentsUpdate": staff,
+	"studentsDelete": adminsOnly,
+
+	"auditList": adminsOnly,
+}
+
+// Scopes maps the operations requests made with an API key may call, by
+// operationId, to the scope the key needs. Operations missing from the map,
+// such as managing passwords or API keys, can't be called with an API key at
+// all. Scopes only narrow what the key's owner may do: the owner's role must
+// still allow the operation.
+var Scopes = map[string]models.Scope{
+	"classesList":   models.ScopeClassesRead,
+	"classesGet":    models.ScopeClassesRead,
+	"classesCreate": models.ScopeClassesWrite,
+	"classesUpdate": models.ScopeClassesWrite,
+	"classesDelete": models.ScopeClassesWrite,
+
+	"gradesList":    models.ScopeGradesRead,
+	"gradesGet":     models.ScopeGradesRead,
+	"gradesCreate":  models.ScopeGradesWrite,
+	"gradesUpdate":  models.ScopeGradesWrite,
+	"gradesDelete":  models.ScopeGradesWrite,
+	"gradesHistory": models.ScopeGradesRead,
+
+	"teachersList":   models.ScopeTeachersRead,
+	"teachersGet":    models.ScopeTeachersRead,
+	"teachersCreate": models.ScopeTeachersWrite,
+	"teachersUpdate": models.ScopeTeachersWrite,
+	"teachersDelete": models.ScopeTeachersWrite,
+	"teachersUnlock": models.ScopeTeachersWrite,
+
+	"studentsList":   models.ScopeStudentsRead,
+	"studentsGet":    models.ScopeStudentsRead,
+	"studentsCreate": models.ScopeStudentsWrite,
+	"studentsUpdate": models.ScopeStudentsWrite,
+	"studentsDelete": models.ScopeStudentsWrite,
+}
+
+// Allowed reports whether a user with the given role may call an operation.
+func Allowed(operation string, role models.Role) bool {
+	for _, r := range Policies[operation] {
+		if r == role {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ScopeAllowed reports whether an API key granted scopes may call an
+// operation.
+func ScopeAllowed(operation string, scopes []string) bool {
+	required, ok := Scopes[operation]
+	if !ok {
+		return false
+	}
+
+	for _, s := range scopes {
+		if models.Scope(s) == required {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MustAuthorize authenticates the request like [MustAuthenticate], then checks
+// the user's current role against the policy of the operation, and the scopes
+// of the API key the request was made with, if any. It responds with 403 if
+// either is not allowed. Like [MustAuthenticate], it returns true when the
+// request has been aborted.
+func MustAuthorize(c *gin.Context, tr teachers.TeacherRepository, operation string) bool {
+	if aborted := MustAuthenticate(c, tr); aborted {
+		return true
+	}
+
+	t := c.MustGet("user").(*models.Teacher)
+	if !Allowed(operation, t.Role) {
+		_ = c.AbortWithError(403, fmt.Errorf("the %v role may not perform %v", t.Role, operation))
+		return true
+	}
+
+	if scopes, ok := c.Get("auth.scopes"); ok && !ScopeAllowed(operation, scopes.([]string)) {
+		_ = c.AbortWithError(403, fmt.Errorf("this API key may not perform %v", operation))
+		return true
+	}
+
+	return false
+}
+
+// MustTeachClass checks that the user authorized by [MustAuthorize] may manage
+// the class with the given id: admins may manage every class, everyone else
+// only the classes they are assigned to. It responds with 404 if the class
+// does not exist and 403 if the user is not assigned to it, and returns true
+// when the request has been aborted.
+func MustTeachClass(c *gin.Context, cr classes.ClassRepository, classId string) bool {
+	t := c.MustGet("user").(*models.Teacher)
+	if t.Role == models.RoleAdmin {
+		return false
+	}
+
+	class, err := cr.Get(classId)
+	if err != nil {
+		_ = c.AbortWithError(500, err)
+		return true
+	}
+
+	if class == nil {
+		_ = c.AbortWithError(404, errors.New("Class not found"))
+		return true
+	}
+
+	if !class.HasTeacher(t.Id) {
+		_ = c.AbortWithError(403, errors.New("you are not assigned to this class"))
+		return true
+	}
+
+	return false
+}
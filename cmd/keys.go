@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/h4n-openschool/api/utils"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// loadKeySet loads the keys tokens are signed and checked with from the
+// `jwt.keys` list of the config file, falling back to a single HS256 key made
+// from `jwt.secret`. Without either, a random key is generated, so tokens stop
+// working on restart.
+func loadKeySet(logger *zap.Logger) (*utils.KeySet, error) {
+	var cfgs []utils.KeyConfig
+	if err := viper.UnmarshalKey("jwt.keys", &cfgs); err != nil {
+		return nil, fmt.Errorf("invalid jwt.keys: %w", err)
+	}
+
+	if len(cfgs) == 0 {
+		secret := viper.GetString("jwt.secret")
+		if secret == "" {
+			logger.Warn("no token signing key configured, generating a random one: tokens will not survive a restart")
+			return utils.NewRandomKeySet()
+		}
+
+		cfgs = append(cfgs, utils.KeyConfig{Id: "default", Algorithm: utils.AlgorithmHS256, Secret: secret})
+	}
+
+	var keys []*utils.Key
+	for _, cfg := range cfgs {
+		key, err := utils.LoadKey(cfg)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return utils.NewKeySet(keys...)
+}
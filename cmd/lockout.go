@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"github.com/h4n-openschool/api/lockout"
+	"github.com/spf13/viper"
+)
+
+// newLockoutGuard creates the guard against password guessing from the
+// `lockout.*` configuration keys. Failed attempts are counted in memory, so
+// each instance counts its own.
+func newLockoutGuard() *lockout.Guard {
+	window := viper.GetDuration("lockout.window")
+
+	policy := func(threshold int) lockout.Policy {
+		return lockout.Policy{
+			Window:    window,
+			Threshold: threshold,
+			BaseDelay: viper.GetDuration("lockout.base-delay"),
+			MaxDelay:  viper.GetDuration("lockout.max-delay"),
+		}
+	}
+
+	return lockout.NewGuard(
+		lockout.NewInMemoryStore(window),
+		policy(viper.GetInt("lockout.account-threshold")),
+		policy(viper.GetInt("lockout.client-threshold")),
+	)
+}
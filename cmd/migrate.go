@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/h4n-openschool/api/migrations"
+	"github.com/h4n-openschool/api/storage"
+	"github.com/spf13/cobra"
+)
+
+// migrateCmd represents the migrate command
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Manage the database schema of SQL storage drivers",
+}
+
+// migrateUpCmd represents the migrate up command
+var migrateUpCmd = &cobra.Command{
+	Use:   "up",
+	Short: "Apply every pending migration",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withMigrator(func(m *migrations.Migrator) error {
+			done, err := m.Up()
+			for _, migration := range done {
+				fmt.Printf("applied %04d_%v\n", migration.Version, migration.Name)
+			}
+			if err != nil {
+				return err
+			}
+
+			if len(done) == 0 {
+				fmt.Println("the schema is already up to date")
+			}
+			return nil
+		})
+	},
+}
+
+// migrateDownCmd represents the migrate down command
+var migrateDownCmd = &cobra.Command{
+	Use:   "down N",
+	Short: "Revert the last N applied migrations",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n < 1 {
+			return fmt.Errorf("N must be a positive number, got %q", args[0])
+		}
+
+		return withMigrator(func(m *migrations.Migrator) error {
+			done, err := m.Down(n)
+			for _, migration := range done {
+				fmt.Printf("reverted %04d_%v\n", migration.Version, migration.Name)
+			}
+			return err
+		})
+	},
+}
+
+// migrateStatusCmd represents the migrate status command
+var migrateStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "List every migration and whether it has been applied",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withMigrator(func(m *migrations.Migrator) error {
+			statuses, err := m.Status()
+			if err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+			for _, s := range statuses {
+				appliedAt := "pending"
+				if s.AppliedAt != nil {
+					appliedAt = s.AppliedAt.Format(time.RFC3339)
+				}
+				fmt.Fprintf(w, "%04d\t%v\t%v\n", s.Version, s.Name, appliedAt)
+			}
+
+			return w.Flush()
+		})
+	},
+}
+
+// migrateCreateCmd represents the migrate create command
+var migrateCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create empty up/down migration files for every SQL driver",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, _ := cmd.Flags().GetString("dir")
+
+		created, err := migrations.Create(dir, args[0])
+		for _, p := range created {
+			fmt.Printf("created %v\n", p)
+		}
+		return err
+	},
+}
+
+// withMigrator opens the configured database and hands a migrator for it to
+// fn, closing the connection afterwards.
+func withMigrator(fn func(m *migrations.Migrator) error) error {
+	cfg := storageConfig()
+	if cfg.Driver == "" || cfg.Driver == storage.DriverMemory {
+		return fmt.Errorf("the %v storage driver has no schema to migrate", storage.DriverMemory)
+	}
+
+	db, err := storage.OpenDB(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	m, err := migrations.NewMigrator(db, cfg.Driver)
+	if err != nil {
+		return err
+	}
+
+	return fn(m)
+}
+
+func init() {
+	rootCmd.AddCommand(migrateCmd)
+	migrateCmd.AddCommand(migrateUpCmd, migrateDownCmd, migrateStatusCmd, migrateCreateCmd)
+
+	// Create a flag to choose where new migration files are written
+	migrateCreateCmd.Flags().String("dir", "migrations", "The directory holding one sub-directory of migrations per driver.")
+}
@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/h4n-openschool/api/notify"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// newNotifier creates the notifier selected by `notify.driver`: `log` writes
+// messages to the log, and `file` appends them to `notify.path`.
+func newNotifier(logger *zap.Logger) (notify.Notifier, error) {
+	switch driver := viper.GetString("notify.driver"); driver {
+	case "", "log":
+		return notify.NewLogNotifier(logger), nil
+	case "file":
+		path := viper.GetString("notify.path")
+		if path == "" {
+			return nil, errors.New("the file notifier needs --notify.path")
+		}
+		return notify.NewFileNotifier(path), nil
+	default:
+		return nil, fmt.Errorf("unsupported notify driver %q", driver)
+	}
+}
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"errors"
+
+	"github.com/h4n-openschool/api/oidc"
+	"github.com/spf13/viper"
+)
+
+// newOidcProvider creates the identity provider users may log in with from
+// the `oidc.*` configuration keys. It returns nil when no issuer is
+// configured, which turns the OIDC endpoints off.
+func newOidcProvider() (*oidc.Provider, error) {
+	cfg := oidc.Config{
+		Issuer:       viper.GetString("oidc.issuer"),
+		ClientId:     viper.GetString("oidc.client-id"),
+		ClientSecret: viper.GetString("oidc.client-secret"),
+		RedirectUrl:  viper.GetString("oidc.redirect-url"),
+		Scopes:       viper.GetStringSlice("oidc.scopes"),
+	}
+
+	if cfg.Issuer == "" {
+		return nil, nil
+	}
+
+	if cfg.ClientId == "" || cfg.RedirectUrl == "" {
+		return nil, errors.New("oidc.issuer needs oidc.client-id and oidc.redirect-url")
+	}
+
+	return oidc.NewProvider(cfg), nil
+}
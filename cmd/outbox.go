@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/h4n-openschool/api/storage"
+	"github.com/spf13/cobra"
+)
+
+// outboxCmd represents the outbox command
+var outboxCmd = &cobra.Command{
+	Use:   "outbox",
+	Short: "Inspect the events waiting to be delivered to the message bus",
+}
+
+// outboxStatusCmd represents the outbox status command
+var outboxStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show how many events are waiting in the outbox",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := storageConfig()
+		if cfg.Driver == "" || cfg.Driver == storage.DriverMemory {
+			return fmt.Errorf("the outbox of the %v storage driver only lives inside the running server", storage.DriverMemory)
+		}
+
+		repos, err := storage.Open(cfg)
+		if err != nil {
+			return err
+		}
+		defer repos.Close()
+
+		stats, err := repos.Outbox.Stats()
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("pending: %v\n", stats.Pending)
+		fmt.Printf("failing: %v\n", stats.Failing)
+		if stats.Oldest != nil {
+			fmt.Printf("oldest:  %v (%v ago)\n", stats.Oldest.Format(time.RFC3339), time.Since(*stats.Oldest).Round(time.Second))
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(outboxCmd)
+	outboxCmd.AddCommand(outboxStatusCmd)
+}
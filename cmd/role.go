@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/h4n-openschool/api/models"
+	"github.com/h4n-openschool/api/storage"
+	"github.com/spf13/cobra"
+)
+
+// roleCmd represents the role command
+var roleCmd = &cobra.Command{
+	Use:   "role",
+	Short: "Manage the roles of users",
+}
+
+// roleSetCmd represents the role set command
+var roleSetCmd = &cobra.Command{
+	Use:   "set <email> <role>",
+	Short: "Change the role of a user, for example to create the first admin",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		email, role := args[0], models.Role(args[1])
+		if !role.Valid() {
+			return fmt.Errorf("unknown role %q, expected one of %v", role, models.Roles)
+		}
+
+		cfg := storageConfig()
+		if cfg.Driver == "" || cfg.Driver == storage.DriverMemory {
+			return fmt.Errorf("the %v storage driver only lives inside the running server", storage.DriverMemory)
+		}
+
+		repos, err := storage.Open(cfg)
+		if err != nil {
+			return err
+		}
+		defer repos.Close()
+
+		t, err := repos.Teachers.GetByEmail(email)
+		if err != nil {
+			return err
+		}
+		if t == nil {
+			return fmt.Errorf("no user found for %v", email)
+		}
+
+		t.Role = role
+		if _, err := repos.Teachers.Update(t); err != nil {
+			return err
+		}
+
+		fmt.Printf("%v is now %v\n", email, role)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(roleCmd)
+	roleCmd.AddCommand(roleSetCmd)
+}
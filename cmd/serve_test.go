@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/h4n-openschool/api/migrations"
+	"github.com/h4n-openschool/api/storage"
+	"go.uber.org/zap"
+)
+
+func TestCheckSchemaRefusesPendingMigrations(t *testing.T) {
+	repos, err := storage.Open(storage.Config{Driver: storage.DriverSqlite, Path: filepath.Join(t.TempDir(), "openschool.db")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer repos.Close()
+
+	m, err := migrations.NewMigrator(repos.DB, storage.DriverSqlite)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := m.Up(); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkSchema(repos, false, zap.NewNop()); err != nil {
+		t.Errorf("checkSchema() = %v on an up to date schema", err)
+	}
+
+	if _, err := m.Down(1); err != nil {
+		t.Fatal(err)
+	}
+	err = checkSchema(repos, false, zap.NewNop())
+	if err == nil || !strings.Contains(err.Error(), "1 migration(s) behind") {
+		t.Errorf("checkSchema() = %v, want the schema to be reported 1 migration behind", err)
+	}
+	if err := checkSchema(repos, true, zap.NewNop()); err != nil {
+		t.Errorf("checkSchema() = %v, want pending migrations allowed", err)
+	}
+
+	if err := checkSchema(storage.NewInMemoryRepositories(), false, zap.NewNop()); err != nil {
+		t.Errorf("checkSchema() = %v for the memory driver", err)
+	}
+}
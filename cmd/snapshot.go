@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/h4n-openschool/api/snapshot"
+	"github.com/h4n-openschool/api/storage"
+	"github.com/spf13/cobra"
+)
+
+// snapshotCmd represents the snapshot command
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Save and inspect snapshots of the stored records",
+}
+
+// snapshotSaveCmd represents the snapshot save command
+var snapshotSaveCmd = &cobra.Command{
+	Use:   "save <file>",
+	Short: "Save every record of the configured storage to a snapshot file",
+	Long: `Save every record of the configured storage to a snapshot file.
+
+The memory storage driver is not supported, since its records only live in
+the server process. Start the server with --snapshot.path to snapshot them.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := storageConfig()
+		if cfg.Driver == "" || cfg.Driver == storage.DriverMemory {
+			return fmt.Errorf("the %v storage driver only lives inside the running server, start it with --snapshot.path instead", storage.DriverMemory)
+		}
+
+		repos, err := storage.Open(cfg)
+		if err != nil {
+			return err
+		}
+		defer repos.Close()
+
+		s, err := snapshot.Take(repos)
+		if err != nil {
+			return err
+		}
+
+		if err := s.Save(args[0]); err != nil {
+			return err
+		}
+
+		printSnapshot(args[0], s)
+		return nil
+	},
+}
+
+// snapshotInspectCmd represents the snapshot inspect command
+var snapshotInspectCmd = &cobra.Command{
+	Use:   "inspect <file>",
+	Short: "Print a summary of a snapshot file, verifying it can be restored",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := snapshot.Load(args[0])
+		if err != nil {
+			return err
+		}
+
+		printSnapshot(args[0], s)
+		return nil
+	},
+}
+
+// printSnapshot prints a summary of the content of a snapshot.
+func printSnapshot(path string, s *snapshot.Snapshot) {
+	fmt.Printf("%v (version %v, taken at %v)\n", path, s.Version, s.TakenAt.Format(time.RFC3339))
+	fmt.Printf("  classes:  %v\n", len(s.Classes))
+	fmt.Printf("  students: %v\n", len(s.Students))
+	fmt.Printf("  teachers: %v\n", len(s.Teachers))
+	fmt.Printf("  grades:   %v\n", len(s.Grades))
+	fmt.Printf("  api keys: %v\n", len(s.ApiKeys))
+	fmt.Printf("  audit:    %v\n", len(s.Audit))
+	fmt.Printf("  outbox:   %v\n", len(s.Outbox))
+}
+
+func init() {
+	rootCmd.AddCommand(snapshotCmd)
+	snapshotCmd.AddCommand(snapshotSaveCmd, snapshotInspectCmd)
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/h4n-openschool/api/server"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// tlsVersions maps the values of `tls.min-version` to TLS versions.
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// newTlsConfig creates the TLS configuration of the server from the `tls.*`
+// configuration keys, along with the reloader keeping its certificate up to
+// date. It returns nil for both when no certificate is configured, which
+// leaves the server speaking plain HTTP.
+func newTlsConfig(logger *zap.Logger) (*tls.Config, *server.CertificateReloader, error) {
+	certFile := viper.GetString("tls.cert")
+	keyFile := viper.GetString("tls.key")
+
+	if certFile == "" && keyFile == "" {
+		return nil, nil, nil
+	}
+	if certFile == "" || keyFile == "" {
+		return nil, nil, errors.New("tls.cert and tls.key must be configured together")
+	}
+
+	if interval := viper.GetDuration("tls.reload-interval"); interval <= 0 {
+		return nil, nil, fmt.Errorf("tls.reload-interval must be positive, got %v", interval)
+	}
+
+	minVersion, ok := tlsVersions[viper.GetString("tls.min-version")]
+	if !ok {
+		return nil, nil, fmt.Errorf("invalid tls.min-version %q: must be 1.0, 1.1, 1.2 or 1.3", viper.GetString("tls.min-version"))
+	}
+
+	certs, err := server.NewCertificateReloader(certFile, keyFile, logger)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load tls.cert and tls.key: %w", err)
+	}
+
+	return &tls.Config{
+		GetCertificate: certs.GetCertificate,
+		MinVersion:     minVersion,
+	}, certs, nil
+}
+
+// newInternalTlsConfig derives the TLS configuration of the internal listener
+// from the server's, requiring clients to present a certificate issued by one
+// of the authorities in `tls.client-ca`.
+func newInternalTlsConfig(config *tls.Config) (*tls.Config, error) {
+	if config == nil {
+		return nil, errors.New("tls.internal-addr needs tls.cert and tls.key")
+	}
+
+	caFile := viper.GetString("tls.client-ca")
+	if caFile == "" {
+		return nil, errors.New("tls.internal-addr needs tls.client-ca")
+	}
+
+	pem, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tls.client-ca: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in tls.client-ca %v", caFile)
+	}
+
+	internal := config.Clone()
+	internal.ClientAuth = tls.RequireAndVerifyClientCert
+	internal.ClientCAs = pool
+
+	return internal, nil
+}
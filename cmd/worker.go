@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/h4n-openschool/api/commands"
+	"github.com/h4n-openschool/api/events"
+	"github.com/h4n-openschool/api/outbox"
+	"github.com/h4n-openschool/api/storage"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// workerCmd represents the worker command
+var workerCmd = &cobra.Command{
+	Use:   "worker",
+	Short: "Apply commands read from the message bus without serving HTTP",
+	Long: `Worker consumes commands from the command queue and applies them to the
+configured database, publishing the resulting events like the server does.
+
+The memory storage driver keeps its records inside a single process, so use
+` + "`serve --amqp.consume`" + ` instead of a separate worker with it.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger, _ := zap.NewDevelopment()
+
+		cfg := storageConfig()
+		if cfg.Driver == "" || cfg.Driver == storage.DriverMemory {
+			return fmt.Errorf("the worker needs a database, use `serve --amqp.consume` with the %v storage driver", storage.DriverMemory)
+		}
+
+		if viper.GetString("amqp.dsn") == "" {
+			return errors.New("the worker needs a message bus, set --amqp.dsn")
+		}
+
+		repos, err := storage.Open(cfg)
+		if err != nil {
+			return err
+		}
+		defer repos.Close()
+
+		pending, err := repos.PendingMigrations()
+		if err != nil {
+			return err
+		}
+		if len(pending) > 0 {
+			return fmt.Errorf("the database schema is %v migration(s) behind, run `migrate up`", len(pending))
+		}
+
+		publisher, relay := newEventBus(repos, logger)
+		defer relay.Publisher.Close()
+
+		consumer, err := newConsumer(repos, publisher, logger)
+		if err != nil {
+			return err
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			relay.Run(ctx)
+		}()
+		go func() {
+			defer wg.Done()
+			consumer.Run(ctx)
+		}()
+
+		<-ctx.Done()
+		logger.Info("shutting down")
+		wg.Wait()
+
+		return nil
+	},
+}
+
+// newEventBus returns the publisher handlers should record events with, and
+// the relay delivering them to the message bus. Without a configured message
+// bus, events are dropped and the relay is nil.
+func newEventBus(repos *storage.Repositories, logger *zap.Logger) (events.Publisher, *outbox.Relay) {
+	dsn := viper.GetString("amqp.dsn")
+	if dsn == "" {
+		return events.NoopPublisher{}, nil
+	}
+
+	bus := events.NewAmqpPublisher(dsn, viper.GetString("amqp.exchange"), logger)
+	return outbox.NewPublisher(repos.Outbox), outbox.NewRelay(repos.Outbox, bus, logger)
+}
+
+// newConsumer creates a consumer applying commands from the configured queue
+// to the repositories.
+func newConsumer(repos *storage.Repositories, publisher events.Publisher, logger *zap.Logger) (*commands.Consumer, error) {
+	dsn := viper.GetString("amqp.dsn")
+	if dsn == "" {
+		return nil, errors.New("consuming commands needs a message bus, set --amqp.dsn")
+	}
+
+	handler, err := commands.NewHandler(repos, publisher, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return commands.NewConsumer(dsn, viper.GetString("amqp.queue"), handler, logger), nil
+}
+
+func init() {
+	rootCmd.AddCommand(workerCmd)
+}
@@ -0,0 +1,85 @@
+// Package commands lets other OpenSchool services change data through the
+// message bus instead of the HTTP API.
+//
+// Commands are JSON messages read from a queue by a [Consumer]. Their payload
+// is validated against the request schemas of the OpenAPI specification, and
+// a [Handler] applies them through the same repositories the HTTP handlers
+// use, publishing the same domain events.
+//
+// Every message is settled in one of three ways:
+//
+//   - applied commands are acknowledged;
+//   - commands that can never succeed, because they are malformed, fail
+//     validation or refer to records that do not exist, are moved to the
+//     dead-letter queue straight away;
+//   - commands that fail for any other reason, such as the database being
+//     unreachable, are retried with backoff and moved to the dead-letter queue
+//     once they have used up their attempts.
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// The types of every command the API accepts.
+const (
+	// StudentCreate creates a student from a StudentsCreateRequest payload.
+	StudentCreate = "student.create"
+
+	// ClassEnroll enrolls a student in the class given by the command's
+	// ClassId, from a ClassEnrollmentRequest payload.
+	ClassEnroll = "class.enroll"
+
+	// GradeCreate records a grade in the class given by the command's
+	// ClassId, from a GradesCreateRequest payload.
+	GradeCreate = "grade.create"
+)
+
+// schemas maps every command type to the OpenAPI schema its payload must
+// match.
+var schemas = map[string]string{
+	StudentCreate: "StudentsCreateRequest",
+	ClassEnroll:   "ClassEnrollmentRequest",
+	GradeCreate:   "GradesCreateRequest",
+}
+
+var (
+	// InvalidCommand is returned, wrapped, for commands that will never
+	// succeed no matter how often they are retried.
+	InvalidCommand = errors.New("invalid command")
+)
+
+// Command is the envelope every command message is wrapped in.
+type Command struct {
+	// Id uniquely identifies the command, for tracing it through the logs
+	// and applying it only once when the broker delivers it again. It is
+	// required.
+	Id string `json:"id"`
+
+	// Type is what to do, for example `class.enroll`.
+	Type string `json:"type"`
+
+	// ActorId is the id of the user on whose behalf the command is sent, if
+	// any. It is copied to the events the command causes.
+	ActorId string `json:"actorId,omitempty"`
+
+	// ClassId is the class the command applies to, for commands that the
+	// HTTP API scopes to a class.
+	ClassId string `json:"classId,omitempty"`
+
+	// Payload is the command-specific body, matching the request schema of
+	// the equivalent HTTP operation.
+	Payload json.RawMessage `json:"payload"`
+}
+
+// Decode parses a command message.
+func Decode(body []byte) (Command, error) {
+	var c Command
+	if err := json.Unmarshal(body, &c); err != nil {
+		return c, fmt.Errorf("%w: %v", InvalidCommand, err)
+	}
+
+	return c, nil
+}
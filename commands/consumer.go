@@ -0,0 +1,276 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/h4n-openschool/api/events"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultMaxAttempts is how often a command is tried before it is moved to
+	// the dead-letter queue.
+	DefaultMaxAttempts = 5
+
+	// DefaultRetryDelay is the delay before the first retry of a command. It
+	// doubles with every further attempt.
+	DefaultRetryDelay = time.Second
+
+	// DefaultPrefetch is how many unacknowledged commands the broker hands to
+	// the consumer at a time.
+	DefaultPrefetch = 10
+
+	// dialTimeout bounds how long connecting to an unreachable broker takes.
+	dialTimeout = 5 * time.Second
+
+	// reconnectDelay is how long the consumer waits before reconnecting after
+	// losing the broker.
+	reconnectDelay = 5 * time.Second
+
+	// attemptsHeader counts the failed attempts of a retried command.
+	attemptsHeader = "x-attempts"
+
+	// errorHeader holds why a dead-lettered command failed.
+	errorHeader = "x-error"
+)
+
+// Consumer reads commands from a queue and hands them to a [Handler]. It
+// declares the queue, its dead-letter queue and the queues failed commands
+// wait in before they are retried, and reconnects whenever the connection
+// drops.
+type Consumer struct {
+	// Dsn is the connection string of the AMQP broker.
+	Dsn string
+
+	// Queue is the name of the queue commands are read from.
+	Queue string
+
+	Handler *Handler
+	Logger  *zap.Logger
+
+	MaxAttempts int
+	RetryDelay  time.Duration
+	Prefetch    int
+}
+
+// NewConsumer creates a new instance of [Consumer] with the default settings.
+func NewConsumer(dsn string, queue string, handler *Handler, logger *zap.Logger) *Consumer {
+	return &Consumer{
+		Dsn:         dsn,
+		Queue:       queue,
+		Handler:     handler,
+		Logger:      logger.Named("commands"),
+		MaxAttempts: DefaultMaxAttempts,
+		RetryDelay:  DefaultRetryDelay,
+		Prefetch:    DefaultPrefetch,
+	}
+}
+
+// DeadLetterQueue returns the name of the queue failed commands are moved to.
+func (c *Consumer) DeadLetterQueue() string {
+	return c.Queue + ".dead"
+}
+
+// RetryQueue returns the name of the queue failed commands wait in for the
+// given delay, after which the broker moves them back to the queue.
+func (c *Consumer) RetryQueue(delay time.Duration) string {
+	return fmt.Sprintf("%v.retry.%v", c.Queue, delay)
+}
+
+// retryDelay returns how long a command waits after its given failed attempt,
+// doubling from RetryDelay.
+func (c *Consumer) retryDelay(attempt int) time.Duration {
+	return c.RetryDelay << (attempt - 1)
+}
+
+// Run consumes commands until ctx is cancelled, reconnecting to the broker as
+// needed. A command being handled when ctx is cancelled is finished first.
+func (c *Consumer) Run(ctx context.Context) {
+	for {
+		err := c.consume(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		c.Logger.Sugar().Warnw("not connected to message bus, retrying", "queue", c.Queue, "in", reconnectDelay, "error", err)
+
+		select {
+		case <-time.After(reconnectDelay):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// consume connects to the broker and handles deliveries until the connection
+// drops or ctx is cancelled.
+func (c *Consumer) consume(ctx context.Context) error {
+	conn, err := amqp.DialConfig(c.Dsn, amqp.Config{
+		Dial: amqp.DefaultDial(dialTimeout),
+	})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	ch, err := conn.Channel()
+	if err != nil {
+		return err
+	}
+
+	for _, queue := range []string{c.DeadLetterQueue(), c.Queue} {
+		if _, err := ch.QueueDeclare(queue, true, false, false, false, nil); err != nil {
+			return err
+		}
+	}
+
+	// Waiting for a retry happens in the broker rather than here, so a failing
+	// command doesn't hold up the others. Each delay has a queue of its own,
+	// since messages only expire once they reach the head of their queue.
+	for attempt := 1; attempt < c.MaxAttempts; attempt++ {
+		delay := c.retryDelay(attempt)
+		_, err := ch.QueueDeclare(c.RetryQueue(delay), true, false, false, false, amqp.Table{
+			"x-message-ttl":             delay.Milliseconds(),
+			"x-dead-letter-exchange":    "",
+			"x-dead-letter-routing-key": c.Queue,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := ch.Qos(c.Prefetch, 0, false); err != nil {
+		return err
+	}
+
+	// Commands are only acknowledged once their retried or dead-lettered copy
+	// has been confirmed, so they are never lost in between.
+	confirmed, err := events.NewConfirmedChannel(ch)
+	if err != nil {
+		return err
+	}
+
+	deliveries, err := ch.Consume(c.Queue, "", false, false, false, false, nil)
+	if err != nil {
+		return err
+	}
+
+	c.Logger.Sugar().Infow("consuming commands", "queue", c.Queue)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case d, ok := <-deliveries:
+			if !ok {
+				return errors.New("delivery channel closed")
+			}
+			c.process(ctx, confirmed, d)
+		}
+	}
+}
+
+// process handles a single delivery and settles it.
+func (c *Consumer) process(ctx context.Context, ch *events.ConfirmedChannel, d amqp.Delivery) {
+	attempts := headerInt(d.Headers, attemptsHeader) + 1
+
+	cmd, err := Decode(d.Body)
+	if err == nil {
+		err = c.Handler.Handle(ctx, cmd)
+	}
+
+	log := c.Logger.Sugar().With("commandId", cmd.Id, "type", cmd.Type, "attempt", attempts)
+
+	switch {
+	case err == nil:
+		log.Debugw("applied command")
+		c.settle(log, d.Ack(false))
+
+	case errors.Is(err, InvalidCommand):
+		log.Warnw("rejected invalid command", "error", err)
+		c.deadLetter(log, ch, d, attempts, err)
+
+	case attempts >= c.MaxAttempts:
+		log.Errorw("giving up on command", "error", err)
+		c.deadLetter(log, ch, d, attempts, err)
+
+	default:
+		delay := c.retryDelay(attempts)
+		log.Warnw("failed to apply command, will retry", "in", delay, "error", err)
+		c.republish(log, ch, d, c.RetryQueue(delay), amqp.Table{attemptsHeader: int32(attempts)})
+	}
+}
+
+// deadLetter moves a delivery to the dead-letter queue, recording why it
+// failed.
+func (c *Consumer) deadLetter(log *zap.SugaredLogger, ch *events.ConfirmedChannel, d amqp.Delivery, attempts int, reason error) {
+	c.republish(log, ch, d, c.DeadLetterQueue(), amqp.Table{
+		attemptsHeader: int32(attempts),
+		errorHeader:    reason.Error(),
+	})
+}
+
+// republish copies a delivery to a queue with extra headers, then acknowledges
+// the original once the broker confirmed the copy. If the copy can't be
+// published, the original is requeued instead so it is not lost.
+func (c *Consumer) republish(log *zap.SugaredLogger, ch *events.ConfirmedChannel, d amqp.Delivery, queue string, headers amqp.Table) {
+	table := amqp.Table{}
+	for k, v := range d.Headers {
+		table[k] = v
+	}
+	for k, v := range headers {
+		table[k] = v
+	}
+
+	// Shutting down must not abort settling the delivery, so the publish gets
+	// a deadline of its own.
+	publishCtx, cancel := context.WithTimeout(context.Background(), dialTimeout)
+	defer cancel()
+
+	err := ch.Publish(publishCtx, "", queue, amqp.Publishing{
+		Headers:      table,
+		ContentType:  d.ContentType,
+		DeliveryMode: amqp.Persistent,
+		MessageId:    d.MessageId,
+		Timestamp:    d.Timestamp,
+		Type:         d.Type,
+		Body:         d.Body,
+	})
+	if err != nil {
+		log.Errorw("failed to move command, requeueing it", "queue", queue, "error", err)
+		c.settle(log, d.Nack(false, true))
+		return
+	}
+
+	c.settle(log, d.Ack(false))
+}
+
+// settle logs a failure to acknowledge or reject a delivery. The broker
+// redelivers it once the channel closes, so there is nothing else to do.
+func (c *Consumer) settle(log *zap.SugaredLogger, err error) {
+	if err != nil {
+		log.Errorw("failed to settle command", "error", err)
+	}
+}
+
+// headerInt reads an integer header, which may arrive with any integer type.
+func headerInt(headers amqp.Table, key string) int {
+	switch v := headers[key].(type) {
+	case int8:
+		return int(v)
+	case int16:
+		return int(v)
+	case int32:
+		return int(v)
+	case int64:
+		return int(v)
+	case int:
+		return v
+	}
+
+	return 0
+}
@@ -0,0 +1,303 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/h4n-openschool/api/api"
+	"github.com/h4n-openschool/api/events"
+	"github.com/h4n-openschool/api/models"
+	"github.com/h4n-openschool/api/outbox"
+	"github.com/h4n-openschool/api/repos/classes"
+	"github.com/h4n-openschool/api/repos/grades"
+	"github.com/h4n-openschool/api/repos/students"
+	"github.com/h4n-openschool/api/storage"
+	"go.uber.org/zap"
+)
+
+// Handler validates commands and applies them to the repositories.
+type Handler struct {
+	ClassRepository   classes.ClassRepository
+	StudentRepository students.StudentRepository
+	GradeRepository   grades.GradeRepository
+	Publisher         events.Publisher
+	Logger            *zap.Logger
+
+	// Storage applies commands together with the events describing them,
+	// see [storage.Repositories.Atomically].
+	Storage *storage.Repositories
+
+	// schemas are the payload schemas by command type.
+	schemas map[string]*openapi3.Schema
+}
+
+// NewHandler creates a new instance of [Handler], loading the payload schemas
+// from the embedded OpenAPI specification.
+func NewHandler(repos *storage.Repositories, publisher events.Publisher, logger *zap.Logger) (*Handler, error) {
+	swagger, err := api.GetSwagger()
+	if err != nil {
+		return nil, err
+	}
+
+	h := &Handler{
+		ClassRepository:   repos.Classes,
+		StudentRepository: repos.Students,
+		GradeRepository:   repos.Grades,
+		Publisher:         publisher,
+		Storage:           repos,
+		Logger:            logger.Named("commands"),
+		schemas:           map[string]*openapi3.Schema{},
+	}
+
+	for commandType, name := range schemas {
+		ref, ok := swagger.Components.Schemas[name]
+		if !ok || ref.Value == nil {
+			return nil, fmt.Errorf("schema %v for %v commands is missing from the specification", name, commandType)
+		}
+		h.schemas[commandType] = ref.Value
+	}
+
+	return h, nil
+}
+
+// Handle validates and applies a single command. Errors wrapping
+// [InvalidCommand] mean the command must not be retried.
+func (h *Handler) Handle(ctx context.Context, c Command) error {
+	if err := h.validate(c); err != nil {
+		return err
+	}
+
+	switch c.Type {
+	case StudentCreate:
+		return h.createStudent(ctx, c)
+	case ClassEnroll:
+		return h.enroll(ctx, c)
+	case GradeCreate:
+		return h.createGrade(ctx, c)
+	}
+
+	return fmt.Errorf("%w: unknown type %q", InvalidCommand, c.Type)
+}
+
+// validate checks the command has an id, and its payload against the schema
+// of its type.
+func (h *Handler) validate(c Command) error {
+	// Redelivered commands are recognised by their id.
+	if c.Id == "" {
+		return fmt.Errorf("%w: commands need an id", InvalidCommand)
+	}
+
+	schema, ok := h.schemas[c.Type]
+	if !ok {
+		return fmt.Errorf("%w: unknown type %q", InvalidCommand, c.Type)
+	}
+
+	var payload any
+	if err := json.Unmarshal(c.Payload, &payload); err != nil {
+		return fmt.Errorf("%w: payload is not valid JSON: %v", InvalidCommand, err)
+	}
+
+	if err := schema.VisitJSON(payload, openapi3.VisitAsRequest(), openapi3.MultiErrors()); err != nil {
+		return fmt.Errorf("%w: %v", InvalidCommand, describeSchemaError(err))
+	}
+
+	if c.Type != StudentCreate && c.ClassId == "" {
+		return fmt.Errorf("%w: %v commands need a classId", InvalidCommand, c.Type)
+	}
+
+	return nil
+}
+
+// describeSchemaError summarises a validation error on a single line, without
+// the schema and value dumps kin-openapi includes by default.
+func describeSchemaError(err error) string {
+	var reasons []string
+
+	var walk func(err error)
+	walk = func(err error) {
+		switch e := err.(type) {
+		case openapi3.MultiError:
+			for _, err := range e {
+				walk(err)
+			}
+		case *openapi3.SchemaError:
+			reasons = append(reasons, fmt.Sprintf("/%v: %v", strings.Join(e.JSONPointer(), "/"), e.Reason))
+		default:
+			reasons = append(reasons, err.Error())
+		}
+	}
+	walk(err)
+
+	return strings.Join(reasons, "; ")
+}
+
+func (h *Handler) createStudent(ctx context.Context, c Command) error {
+	var body api.StudentsCreateRequest
+	if err := json.Unmarshal(c.Payload, &body); err != nil {
+		return fmt.Errorf("%w: %v", InvalidCommand, err)
+	}
+
+	return h.apply(c, func(tx *storage.Repositories) error {
+		student, err := tx.Students.Create(models.Student{FullName: body.FullName})
+		if err != nil {
+			return err
+		}
+
+		return h.publish(ctx, tx, c, events.StudentCreated, student.Id, student.AsApiStudent())
+	})
+}
+
+func (h *Handler) enroll(ctx context.Context, c Command) error {
+	var body api.ClassEnrollmentRequest
+	if err := json.Unmarshal(c.Payload, &body); err != nil {
+		return fmt.Errorf("%w: %v", InvalidCommand, err)
+	}
+
+	if _, err := h.getClass(c.ClassId); err != nil {
+		return err
+	}
+
+	if err := h.checkStudent(body.StudentId); err != nil {
+		return err
+	}
+
+	err := h.apply(c, func(tx *storage.Repositories) error {
+		// Enrolling a student twice is not an error, and changes nothing.
+		enrolled, err := tx.Classes.Enroll(c.ClassId, body.StudentId)
+		if err != nil || !enrolled {
+			return err
+		}
+
+		class, err := tx.Classes.Get(c.ClassId)
+		if err != nil {
+			return err
+		}
+
+		return h.publish(ctx, tx, c, events.ClassUpdated, class.Id, class.AsApiClass())
+	})
+	if errors.Is(err, classes.ClassDoesNotExist) {
+		return fmt.Errorf("%w: %v", InvalidCommand, err)
+	}
+
+	return err
+}
+
+func (h *Handler) createGrade(ctx context.Context, c Command) error {
+	var body api.GradesCreateRequest
+	if err := json.Unmarshal(c.Payload, &body); err != nil {
+		return fmt.Errorf("%w: %v", InvalidCommand, err)
+	}
+
+	if _, err := h.getClass(c.ClassId); err != nil {
+		return err
+	}
+
+	if err := h.checkStudent(body.StudentId); err != nil {
+		return err
+	}
+
+	return h.apply(c, func(tx *storage.Repositories) error {
+		grade, err := tx.Grades.Create(models.Grade{
+			ClassId:   c.ClassId,
+			StudentId: body.StudentId,
+			Value:     body.Value,
+		}, models.GradeChange{ChangedBy: c.ActorId})
+		if err != nil {
+			return err
+		}
+
+		return h.publish(ctx, tx, c, events.GradeCreated, grade.Id, events.GradePayload{ClassId: c.ClassId, Grade: grade.AsApiGrade()})
+	})
+}
+
+// appliedAlready rolls back the transaction of a command that turns out to
+// have been applied before.
+var appliedAlready = errors.New("command applied already")
+
+// apply runs fn with repositories bound to a transaction, unless the command
+// was applied already. Commands are recorded in the inbox in that same
+// transaction once fn succeeded, so commands the broker delivers again once
+// applied are skipped, while ones that failed are retried.
+func (h *Handler) apply(c Command, fn func(tx *storage.Repositories) error) error {
+	err := h.Storage.Atomically(func(tx *storage.Repositories) error {
+		applied, err := tx.Inbox.Applied(c.Id)
+		if err != nil {
+			return err
+		}
+		if applied {
+			return appliedAlready
+		}
+
+		if err := fn(tx); err != nil {
+			return err
+		}
+
+		// Another consumer may have applied the command meanwhile, in which
+		// case failing rolls back the writes of this one.
+		applied, err = tx.Inbox.Record(c.Id, c.Type)
+		if err != nil {
+			return err
+		}
+		if applied {
+			return appliedAlready
+		}
+
+		return nil
+	})
+
+	if errors.Is(err, appliedAlready) {
+		h.Logger.Sugar().Infow("skipped command applied already", "commandId", c.Id, "type", c.Type)
+		return nil
+	}
+
+	return err
+}
+
+// getClass returns the class with the given id, or an error wrapping
+// [InvalidCommand] if there is none.
+func (h *Handler) getClass(id string) (*models.Class, error) {
+	class, err := h.ClassRepository.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if class == nil {
+		return nil, fmt.Errorf("%w: class %v does not exist", InvalidCommand, id)
+	}
+
+	return class, nil
+}
+
+// checkStudent returns an error wrapping [InvalidCommand] if there is no
+// student with the given id.
+func (h *Handler) checkStudent(id string) error {
+	student, err := h.StudentRepository.Get(id)
+	if err != nil {
+		return err
+	}
+
+	if student == nil {
+		return fmt.Errorf("%w: student %v does not exist", InvalidCommand, id)
+	}
+
+	return nil
+}
+
+// publish records a domain event in the transaction of the command it
+// describes, like the HTTP handlers do.
+func (h *Handler) publish(ctx context.Context, tx *storage.Repositories, c Command, eventType string, aggregateId string, payload any) error {
+	if h.Publisher == nil {
+		return nil
+	}
+
+	e, err := events.New(eventType, c.ActorId, aggregateId, payload)
+	if err != nil {
+		return err
+	}
+
+	return outbox.PublisherIn(h.Publisher, tx.Outbox).Publish(ctx, e)
+}
@@ -0,0 +1,134 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.uber.org/zap"
+)
+
+// dialTimeout bounds how long a publish waits for an unreachable broker.
+const dialTimeout = 5 * time.Second
+
+// AmqpPublisher implements the [Publisher] interface by publishing events to
+// a topic exchange, using the event type as the routing key. It connects
+// lazily and reconnects after the connection drops, so the API can start
+// before the broker does. Publishing only succeeds once the broker has
+// confirmed the event, see [ConfirmedChannel].
+type AmqpPublisher struct {
+	// Dsn is the connection string of the AMQP broker.
+	Dsn string
+
+	// Exchange is the name of the topic exchange events are published to.
+	Exchange string
+
+	Logger *zap.Logger
+
+	mu      sync.Mutex
+	conn    *amqp.Connection
+	channel *ConfirmedChannel
+}
+
+// NewAmqpPublisher creates a new instance of [AmqpPublisher]
+func NewAmqpPublisher(dsn string, exchange string, logger *zap.Logger) *AmqpPublisher {
+	return &AmqpPublisher{
+		Dsn:      dsn,
+		Exchange: exchange,
+		Logger:   logger.Named("events"),
+	}
+}
+
+func (p *AmqpPublisher) Publish(ctx context.Context, e Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ch, err := p.connect()
+	if err != nil {
+		return err
+	}
+
+	err = ch.Publish(ctx, p.Exchange, e.Type, amqp.Publishing{
+		ContentType:  "application/json",
+		DeliveryMode: amqp.Persistent,
+		MessageId:    e.Id,
+		Timestamp:    e.Timestamp,
+		Type:         e.Type,
+		Body:         body,
+	})
+	if err != nil {
+		// Drop the channel so the next publish starts from a fresh connection,
+		// unless it worked but nobody listens for the event yet.
+		if !errors.Is(err, Unroutable) {
+			p.reset()
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (p *AmqpPublisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.reset()
+	return nil
+}
+
+// connect returns an open channel, dialling the broker and declaring the
+// exchange first when needed. The caller must hold the lock.
+func (p *AmqpPublisher) connect() (*ConfirmedChannel, error) {
+	if p.channel != nil && !p.channel.IsClosed() {
+		return p.channel, nil
+	}
+	p.reset()
+
+	conn, err := amqp.DialConfig(p.Dsn, amqp.Config{
+		Dial: amqp.DefaultDial(dialTimeout),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	if err := ch.ExchangeDeclare(p.Exchange, amqp.ExchangeTopic, true, false, false, false, nil); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	confirmed, err := NewConfirmedChannel(ch)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	p.Logger.Sugar().Infow("connected to message bus", "exchange", p.Exchange)
+	p.conn = conn
+	p.channel = confirmed
+
+	return confirmed, nil
+}
+
+// reset closes the current connection, if any. The caller must hold the lock.
+func (p *AmqpPublisher) reset() {
+	if p.conn != nil {
+		_ = p.conn.Close()
+	}
+
+	p.conn = nil
+	p.channel = nil
+}
@@ -0,0 +1,83 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// confirmTimeout bounds how long a publish waits for the broker to confirm it.
+const confirmTimeout = 5 * time.Second
+
+// Unroutable is returned when the broker has no queue to route a message to.
+var Unroutable = errors.New("no queue is bound for the message")
+
+// ConfirmedChannel publishes messages on a channel in confirm mode, and only
+// reports success once the broker has taken responsibility for them. Messages
+// are published as mandatory, so those no queue is bound for fail instead of
+// being dropped. It must not be used by several goroutines at once.
+type ConfirmedChannel struct {
+	*amqp.Channel
+
+	// returns receives the messages the broker could not route.
+	returns chan amqp.Return
+}
+
+// NewConfirmedChannel puts ch in confirm mode and wraps it in a
+// [ConfirmedChannel].
+func NewConfirmedChannel(ch *amqp.Channel) (*ConfirmedChannel, error) {
+	if err := ch.Confirm(false); err != nil {
+		return nil, fmt.Errorf("failed to enable publisher confirms: %w", err)
+	}
+
+	// The broker returns an unroutable message before confirming it, so with
+	// one message in flight at a time a single slot is enough.
+	returns := ch.NotifyReturn(make(chan amqp.Return, 1))
+
+	return &ConfirmedChannel{Channel: ch, returns: returns}, nil
+}
+
+// Publish publishes msg and waits until the broker confirms it. It fails if
+// the broker rejects or returns the message, or doesn't confirm it in time.
+func (c *ConfirmedChannel) Publish(ctx context.Context, exchange string, key string, msg amqp.Publishing) error {
+	// Drop returns left over from a previous message which failed anyway.
+	for drained := false; !drained; {
+		select {
+		case _, ok := <-c.returns:
+			drained = !ok
+		default:
+			drained = true
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, confirmTimeout)
+	defer cancel()
+
+	confirmation, err := c.PublishWithDeferredConfirmWithContext(ctx, exchange, key, true, false, msg)
+	if err != nil {
+		return err
+	}
+	if confirmation == nil {
+		return errors.New("channel is not in confirm mode")
+	}
+
+	if !confirmation.Wait() {
+		if ctx.Err() != nil {
+			return fmt.Errorf("broker did not confirm message: %w", ctx.Err())
+		}
+		return errors.New("broker rejected message")
+	}
+
+	select {
+	case r, ok := <-c.returns:
+		if !ok {
+			return errors.New("channel closed before the message was confirmed")
+		}
+		return fmt.Errorf("%w: %v %v", Unroutable, r.ReplyCode, r.ReplyText)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,106 @@
+// Package events describes the domain events emitted by the API and publishes
+// them to the message bus.
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/h4n-openschool/api/api"
+	"github.com/lucsky/cuid"
+)
+
+// The types of every event emitted by the API. They double as the routing keys
+// messages are published with, so consumers can bind to patterns such as
+// `class.*` or `*.deleted`.
+const (
+	ClassCreated = "class.created"
+	ClassUpdated = "class.updated"
+	ClassDeleted = "class.deleted"
+
+	StudentCreated = "student.created"
+	StudentUpdated = "student.updated"
+	StudentDeleted = "student.deleted"
+
+	TeacherCreated = "teacher.created"
+	TeacherUpdated = "teacher.updated"
+	TeacherDeleted = "teacher.deleted"
+
+	GradeCreated = "grade.created"
+	GradeUpdated = "grade.updated"
+	GradeDeleted = "grade.deleted"
+)
+
+// Event is the envelope every message on the bus is wrapped in.
+type Event struct {
+	// Id uniquely identifies the event, so consumers can drop duplicates.
+	Id string `json:"id"`
+
+	// Type is what happened, for example `class.created`.
+	Type string `json:"type"`
+
+	// Timestamp is the time the event happened.
+	Timestamp time.Time `json:"timestamp"`
+
+	// ActorId is the id of the user that caused the event, if any.
+	ActorId string `json:"actorId,omitempty"`
+
+	// AggregateId is the id of the record the event is about.
+	AggregateId string `json:"aggregateId"`
+
+	// Payload is the event-specific body, usually the API representation of
+	// the record.
+	Payload json.RawMessage `json:"payload"`
+}
+
+// New creates an event of the given type, encoding payload as its body.
+func New(eventType string, actorId string, aggregateId string, payload any) (Event, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return Event{}, err
+	}
+
+	return Event{
+		Id:          cuid.New(),
+		Type:        eventType,
+		Timestamp:   time.Now().UTC(),
+		ActorId:     actorId,
+		AggregateId: aggregateId,
+		Payload:     body,
+	}, nil
+}
+
+// DeletedPayload is the body of every `*.deleted` event.
+type DeletedPayload struct {
+	Id string `json:"id"`
+}
+
+// GradePayload is the body of `grade.created` and `grade.updated` events,
+// which need the class a grade belongs to alongside the grade itself.
+type GradePayload struct {
+	ClassId string    `json:"classId"`
+	Grade   api.Grade `json:"grade"`
+}
+
+// Publisher sends events to the message bus.
+type Publisher interface {
+	// Publish sends a single event. It only returns nil once the message bus
+	// has accepted the event, so the caller may forget about it.
+	Publish(ctx context.Context, e Event) error
+
+	// Close releases any connection held by the publisher.
+	Close() error
+}
+
+// NoopPublisher is a [Publisher] that drops every event, for use when no
+// message bus is configured.
+type NoopPublisher struct{}
+
+func (NoopPublisher) Publish(ctx context.Context, e Event) error {
+	return nil
+}
+
+func (NoopPublisher) Close() error {
+	return nil
+}
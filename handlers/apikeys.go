@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/h4n-openschool/api/api"
+	"github.com/h4n-openschool/api/auth"
+	"github.com/h4n-openschool/api/models"
+	"github.com/h4n-openschool/api/storage"
+	"github.com/h4n-openschool/api/utils"
+)
+
+const (
+	// apiKeyPrefix starts every API key, so leaked keys are easy to spot.
+	apiKeyPrefix = "osk_"
+
+	// apiKeyShownLength is how much of a key is stored in the clear to tell
+	// keys apart.
+	apiKeyShownLength = len(apiKeyPrefix) + 6
+)
+
+// ApiKeysList implements the apiKeysList contract from the OpenAPI spec.
+func (i *OpenSchoolImpl) ApiKeysList(c *gin.Context) {
+	if ok := auth.MustAuthorize(c, i.TeacherRepository, "apiKeysList"); ok {
+		return
+	}
+
+	t := c.MustGet("user").(*models.Teacher)
+
+	keys, err := i.ApiKeyRepository.List(t.Id)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	response := api.ApiKeysListResponse{ApiKeys: []api.ApiKey{}}
+	for _, key := range keys {
+		response.ApiKeys = append(response.ApiKeys, key.AsApiApiKey())
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ApiKeysCreate implements the apiKeysCreate contract from the OpenAPI spec.
+func (i *OpenSchoolImpl) ApiKeysCreate(c *gin.Context) {
+	if ok := auth.MustAuthorize(c, i.TeacherRepository, "apiKeysCreate"); ok {
+		return
+	}
+
+	var body api.ApiKeysCreateJSONRequestBody
+	if err := c.BindJSON(&body); err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	t := c.MustGet("user").(*models.Teacher)
+
+	secret, err := newOpaqueToken()
+	if err != nil {
+		_ = c.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to generate API key: %w", err))
+		return
+	}
+	key := apiKeyPrefix + secret
+
+	model := models.ApiKey{
+		UserId: t.Id,
+		Name:   body.Name,
+		Prefix: key[:apiKeyShownLength],
+		Hash:   utils.HashToken(key),
+	}
+	for _, s := range body.Scopes {
+		if !model.HasScope(models.Scope(s)) {
+			model.Scopes = append(model.Scopes, models.Scope(s))
+		}
+	}
+
+	var created *models.ApiKey
+	err = i.Storage.Atomically(func(tx *storage.Repositories) error {
+		var err error
+		if created, err = tx.ApiKeys.Create(model); err != nil {
+			return err
+		}
+		// Only the key's public part is recorded, the key itself is a secret.
+		return i.audit(c, tx, "apiKeysCreate", created.Id, nil, created.AsApiApiKey())
+	})
+	if err != nil {
+		_ = c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, api.ApiKeysCreateResponse{
+		ApiKey: created.AsApiApiKey(),
+		Key:    key,
+	})
+}
+
+// ApiKeysRevoke implements the apiKeysRevoke contract from the OpenAPI spec.
+func (i *OpenSchoolImpl) ApiKeysRevoke(c *gin.Context, id api.Cuid) {
+	if ok := auth.MustAuthorize(c, i.TeacherRepository, "apiKeysRevoke"); ok {
+		return
+	}
+
+	t := c.MustGet("user").(*models.Teacher)
+
+	key, err := i.ApiKeyRepository.Get(id)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	// The keys of others are reported as missing, so their ids can't be
+	// probed.
+	if key == nil || (key.UserId != t.Id && t.Role != models.RoleAdmin) {
+		_ = c.AbortWithError(http.StatusNotFound, errors.New("API key not found"))
+		return
+	}
+
+	now := time.Now()
+	revoked := *key
+	revoked.RevokedAt = &now
+
+	err = i.Storage.Atomically(func(tx *storage.Repositories) error {
+		if err := tx.ApiKeys.Revoke(key.Id, now); err != nil {
+			return err
+		}
+		return i.audit(c, tx, "apiKeysRevoke", key.Id, key.AsApiApiKey(), revoked.AsApiApiKey())
+	})
+	if err != nil {
+		_ = c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/h4n-openschool/api/api"
+	"github.com/h4n-openschool/api/audit"
+	"github.com/h4n-openschool/api/auth"
+	"github.com/h4n-openschool/api/storage"
+	"github.com/h4n-openschool/api/utils"
+)
+
+// AuditList implements the auditList operation from the OpenAPI specification.
+func (i *OpenSchoolImpl) AuditList(ctx *gin.Context, params api.AuditListParams) {
+	if ok := auth.MustAuthorize(ctx, i.TeacherRepository, "auditList"); ok {
+		return
+	}
+
+	var filter audit.Filter
+	if params.ActorId != nil {
+		filter.ActorId = *params.ActorId
+	}
+	if params.Operation != nil {
+		filter.Operation = *params.Operation
+	}
+	if params.ResourceId != nil {
+		filter.ResourceId = *params.ResourceId
+	}
+
+	for _, bound := range []struct {
+		param *api.DateTime
+		into  **time.Time
+	}{{params.Since, &filter.Since}, {params.Until, &filter.Until}} {
+		if bound.param == nil {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, *bound.param)
+		if err != nil {
+			_ = ctx.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+		*bound.into = &t
+	}
+
+	pagination := utils.NewPaginationQuery()
+	pagination.ReadFromOptional(params.Page, params.PerPage)
+
+	records, err := i.Audit.List(filter, pagination)
+	if err != nil {
+		_ = ctx.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	total, err := i.Audit.Count(filter)
+	if err != nil {
+		_ = ctx.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	response := api.AuditListResponse{
+		Pagination: utils.GeneratePaginationData("/v1/audit", total, pagination),
+		Records:    []api.AuditRecord{},
+	}
+	for _, r := range records {
+		response.Records = append(response.Records, asApiAuditRecord(r))
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func asApiAuditRecord(r audit.Record) api.AuditRecord {
+	return api.AuditRecord{
+		Sequence:   r.Sequence,
+		ActorId:    r.ActorId,
+		Operation:  r.Operation,
+		ResourceId: r.ResourceId,
+		Before:     snapshotObject(r.Before),
+		After:      snapshotObject(r.After),
+		ClientIp:   r.ClientIp,
+		At:         r.At.Format(time.RFC3339),
+	}
+}
+
+// snapshotObject decodes a recorded snapshot for the API, which has no room
+// for anything but objects.
+func snapshotObject(raw json.RawMessage) *map[string]interface{} {
+	if raw == nil {
+		return nil
+	}
+
+	var object map[string]interface{}
+	if err := json.Unmarshal(raw, &object); err != nil || object == nil {
+		return nil
+	}
+
+	return &object
+}
+
+// audit records a change made by the current request in the audit log. Before
+// and after are the API representations of the changed record, nil for
+// creates and deletes respectively. Like [OpenSchoolImpl.publish], it runs in
+// the transaction of the change, so failing to record it fails the change too.
+func (i *OpenSchoolImpl) audit(ctx *gin.Context, tx *storage.Repositories, operation string, resourceId string, before any, after any) error {
+	return i.auditAs(ctx, tx, ctx.GetString("auth.userId"), operation, resourceId, before, after)
+}
+
+// auditAs is like [OpenSchoolImpl.audit] for changes made by requests that are
+// not authenticated, such as activating an account, where the actor is only
+// known once the request has been handled.
+func (i *OpenSchoolImpl) auditAs(ctx *gin.Context, tx *storage.Repositories, actorId string, operation string, resourceId string, before any, after any) error {
+	if tx.Audit == nil {
+		return nil
+	}
+
+	r := audit.Record{
+		ActorId:    actorId,
+		Operation:  operation,
+		ResourceId: resourceId,
+		ClientIp:   ctx.ClientIP(),
+		At:         time.Now(),
+	}
+
+	var err error
+	if r.Before, err = snapshot(before); err != nil {
+		return fmt.Errorf("failed to record change in audit log: %w", err)
+	}
+	if r.After, err = snapshot(after); err != nil {
+		return fmt.Errorf("failed to record change in audit log: %w", err)
+	}
+
+	if err := tx.Audit.Append(r); err != nil {
+		return fmt.Errorf("failed to record change in audit log: %w", err)
+	}
+
+	return nil
+}
+
+// snapshot encodes v as JSON, returning nil when there is nothing to record.
+func snapshot(v any) (json.RawMessage, error) {
+	b, err := json.Marshal(v)
+	if err != nil || bytes.Equal(b, []byte("null")) {
+		return nil, err
+	}
+
+	return b, nil
+}
@@ -1,71 +1,303 @@
 package handlers
 
 import (
+	"crypto/rand"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/h4n-openschool/api/api"
 	"github.com/h4n-openschool/api/auth"
 	"github.com/h4n-openschool/api/models"
-	"golang.org/x/crypto/bcrypt"
+	"github.com/h4n-openschool/api/utils"
+	"github.com/lucsky/cuid"
+)
+
+// The reasons login refuses an account for, sent as the `reason` of the error.
+const (
+	reasonAccountPending  = "account_pending"
+	reasonAccountDisabled = "account_disabled"
+	reasonAccountUnknown  = "account_unknown"
+	reasonLockedOut       = "locked_out"
 )
 
 func (i *OpenSchoolImpl) AuthCurrentUser(c *gin.Context) {
-  i.Logger.Info("header is " + c.GetHeader("Authorization"))
+	if ok := auth.MustAuthorize(c, i.TeacherRepository, "authCurrentUser"); ok {
+		return
+	}
 
-  if ok := auth.MustAuthenticate(c, i.TeacherRepository); ok {
-    return
-  }
-  teacher := c.Value("user").(*models.Teacher)
+	teacher := c.Value("user").(*models.Teacher)
 
-  c.JSON(200, teacher.AsApiTeacher())
+	c.JSON(200, teacher.AsApiTeacher())
 }
 
 func (i *OpenSchoolImpl) AuthLogin(c *gin.Context) {
 	var body api.AuthLoginJSONRequestBody
 	if err := c.BindJSON(&body); err != nil {
 		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	wait, err := i.Lockout.Check(body.Email, c.ClientIP(), time.Now())
+	if err != nil {
+		_ = c.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to check lockout: %w", err))
+		return
+	}
+	if wait > 0 {
+		abortLockedOut(c, wait)
+		return
 	}
 
 	t, err := i.TeacherRepository.GetByEmail(body.Email)
 	if err != nil {
-    _ = c.AbortWithError(
-      http.StatusInternalServerError,
-      fmt.Errorf("failed to get teacher: %v", err.Error()),
-    )
+		_ = c.AbortWithError(
+			http.StatusInternalServerError,
+			fmt.Errorf("failed to get teacher: %v", err.Error()),
+		)
+		return
 	}
 
 	if t == nil {
-    _ = c.AbortWithError(
-      http.StatusInternalServerError,
-      fmt.Errorf("teacher not found for email %v", body.Email),
-    )
+		i.failLogin(c, body.Email)
 		_ = c.AbortWithError(http.StatusNotFound, errors.New("Teacher not found for that email."))
-    return
+		return
+	}
+
+	if !utils.CheckPassword(t.PasswordHash, body.Password) {
+		i.failLogin(c, body.Email)
+		_ = c.AbortWithError(http.StatusUnauthorized, errors.New("Invalid email or password."))
+		return
+	}
+
+	// Only tell whether an account is pending or disabled to whoever knows its
+	// password, so the status can't be probed with a wrong one.
+	if aborted := abortInactive(c, t); aborted {
+		return
+	}
+
+	// With two-factor authentication, the password only gets a challenge.
+	// Failed attempts are kept until the code is verified too, so they can't
+	// be cleared with the password alone.
+	if issued := i.respondLogin(c, t); !issued {
+		return
+	}
+
+	if err := i.Lockout.Succeed(body.Email); err != nil {
+		i.Logger.Sugar().Errorw("failed to reset failed login attempts", "userId", t.Id, "error", err)
+	}
+}
+
+// abortInactive responds with 403 if a teacher may not log in because their
+// account is pending or disabled, and returns true when it did.
+func abortInactive(c *gin.Context, t *models.Teacher) bool {
+	switch t.Status {
+	case models.TeacherPending:
+		_ = c.AbortWithError(http.StatusForbidden, errors.New("This account has not been activated yet, use the invitation that was sent to it.")).SetMeta(reasonAccountPending)
+		return true
+	case models.TeacherDisabled:
+		_ = c.AbortWithError(http.StatusForbidden, errors.New("This account has been disabled.")).SetMeta(reasonAccountDisabled)
+		return true
+	}
+
+	return false
+}
+
+// respondLogin completes a login once a teacher proved who they are. It
+// responds with a challenge if they enabled two-factor authentication, and
+// with tokens for a new session otherwise. It reports whether tokens were
+// issued.
+func (i *OpenSchoolImpl) respondLogin(c *gin.Context, t *models.Teacher) bool {
+	settings, err := i.TotpRepository.Get(t.Id)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to get two-factor settings: %w", err))
+		return false
+	}
+
+	if settings != nil && settings.Enabled() {
+		challenge, err := i.challenge(t)
+		if err != nil {
+			_ = c.AbortWithError(http.StatusInternalServerError, err)
+			return false
+		}
+
+		c.JSON(http.StatusAccepted, challenge)
+		return false
+	}
+
+	response, err := i.issueTokens(t, cuid.New())
+	if err != nil {
+		_ = c.AbortWithError(http.StatusInternalServerError, err)
+		return false
+	}
+
+	c.JSON(http.StatusOK, response)
+	return true
+}
+
+// failLogin records a failed attempt to log in to an account. Failing to
+// record it is logged rather than reported, since the attempt failed anyway.
+func (i *OpenSchoolImpl) failLogin(c *gin.Context, email string) {
+	if err := i.Lockout.Fail(email, c.ClientIP(), time.Now()); err != nil {
+		i.Logger.Sugar().Errorw("failed to record failed login attempt", "error", err)
+	}
+}
+
+// abortLockedOut responds with 429, telling the client how long to wait
+// before trying again.
+func abortLockedOut(c *gin.Context, wait time.Duration) {
+	seconds := int(math.Ceil(wait.Seconds()))
+	c.Header("Retry-After", strconv.Itoa(seconds))
+	_ = c.AbortWithError(
+		http.StatusTooManyRequests,
+		fmt.Errorf("Too many failed login attempts, try again in %v seconds.", seconds),
+	).SetMeta(reasonLockedOut)
+}
+
+// AuthRefresh implements the authRefresh contract from the OpenAPI spec.
+func (i *OpenSchoolImpl) AuthRefresh(c *gin.Context) {
+	var body api.AuthRefreshJSONRequestBody
+	if err := c.BindJSON(&body); err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	hash := utils.HashToken(body.RefreshToken)
+	rt, err := i.TokenRepository.GetRefreshToken(hash)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to get refresh token: %w", err))
+		return
+	}
+
+	now := time.Now()
+	if rt == nil || rt.RevokedAt != nil || !now.Before(rt.ExpiresAt) {
+		_ = c.AbortWithError(http.StatusUnauthorized, errors.New("Invalid refresh token."))
+		return
+	}
+
+	// A refresh token presented twice has been stolen by somebody, but we
+	// can't tell whether the legitimate client or the thief came second, so
+	// end the whole session.
+	used, err := i.TokenRepository.UseRefreshToken(hash, now)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to use refresh token: %w", err))
+		return
+	}
+	if !used {
+		if err := i.TokenRepository.RevokeFamily(rt.FamilyId, now.Add(i.AccessTokenTtl)); err != nil {
+			_ = c.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to revoke session: %w", err))
+			return
+		}
+
+		i.Logger.Sugar().Warnw("refresh token reused, revoked session", "userId", rt.UserId, "sessionId", rt.FamilyId)
+		_ = c.AbortWithError(http.StatusUnauthorized, errors.New("Refresh token already used, the session has been revoked."))
+		return
+	}
+
+	t, err := i.TeacherRepository.Get(rt.UserId)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to get teacher: %w", err))
+		return
+	}
+	if t == nil || t.Status != models.TeacherActive {
+		_ = c.AbortWithError(http.StatusUnauthorized, errors.New("Invalid refresh token."))
+		return
+	}
+
+	response, err := i.issueTokens(t, rt.FamilyId)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// AuthLogout implements the authLogout contract from the OpenAPI spec.
+func (i *OpenSchoolImpl) AuthLogout(c *gin.Context) {
+	if ok := auth.MustAuthorize(c, i.TeacherRepository, "authLogout"); ok {
+		return
+	}
+
+	claims := c.MustGet("auth.claims").(*utils.UserClaims)
+
+	until := time.Now().Add(i.AccessTokenTtl)
+	if claims.ExpiresAt != nil {
+		until = claims.ExpiresAt.Time
+	}
+
+	if err := i.TokenRepository.RevokeAccessToken(claims.ID, until); err != nil {
+		_ = c.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to revoke token: %w", err))
+		return
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(t.PasswordHash), []byte(body.Password)); err != nil {
-    _ = c.AbortWithError(
-      http.StatusInternalServerError,
-      fmt.Errorf("failed to compare hash and password: %v", err.Error()),
-    )
+	// Tokens issued before sessions existed have no session to revoke.
+	if claims.SessionId != "" {
+		if err := i.TokenRepository.RevokeFamily(claims.SessionId, time.Now().Add(i.AccessTokenTtl)); err != nil {
+			_ = c.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to revoke session: %w", err))
+			return
+		}
 	}
 
+	c.Status(http.StatusNoContent)
+}
+
+// issueTokens creates an access token and a refresh token for the teacher,
+// both belonging to the session identified by sessionId.
+func (i *OpenSchoolImpl) issueTokens(t *models.Teacher, sessionId string) (*api.AuthLoginResponse, error) {
 	u := models.User[models.Teacher]{
 		Person:   t,
 		PersonId: t.Id,
+		Role:     t.Role,
 	}
-  token, err := u.Jwt()
-  if err != nil {
-    _ = c.AbortWithError(
-      http.StatusInternalServerError,
-      fmt.Errorf("failed to generate jwt: %v", err.Error()),
-    )
-  }
-
-	c.JSON(http.StatusOK, api.AuthLoginResponse{
-    Token: token,
-  })
+	token, err := u.Jwt(i.Keys, i.AccessTokenTtl, sessionId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate jwt: %w", err)
+	}
+
+	refreshToken, err := newOpaqueToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+
+	now := time.Now()
+	err = i.TokenRepository.CreateRefreshToken(models.RefreshToken{
+		Hash:      utils.HashToken(refreshToken),
+		FamilyId:  sessionId,
+		UserId:    t.Id,
+		ExpiresAt: now.Add(i.RefreshTokenTtl),
+		CreatedAt: now,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to store refresh token: %w", err)
+	}
+
+	return &api.AuthLoginResponse{
+		Token:        token,
+		ExpiresIn:    int(i.AccessTokenTtl.Seconds()),
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+// newOpaqueToken generates a random, URL-safe token.
+func newOpaqueToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// AuthJwks implements the authJwks contract from the OpenAPI spec.
+func (i *OpenSchoolImpl) AuthJwks(c *gin.Context) {
+	// Let downstream services cache the keys for a while, but not so long that
+	// they miss a rotation.
+	c.Header("Cache-Control", "public, max-age=300")
+
+	c.JSON(http.StatusOK, api.JwksResponse{Keys: i.Keys.Jwks()})
 }
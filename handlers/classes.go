@@ -9,15 +9,17 @@ import (
 	"github.com/gosimple/slug"
 	"github.com/h4n-openschool/api/api"
 	"github.com/h4n-openschool/api/auth"
+	"github.com/h4n-openschool/api/events"
 	"github.com/h4n-openschool/api/models"
 	"github.com/h4n-openschool/api/repos/classes"
+	"github.com/h4n-openschool/api/storage"
 	"github.com/h4n-openschool/api/utils"
 )
 
 // ClassesList implements the classesList operation from the OpenAPI
 // specification in [../api/spec.yaml].
 func (i *OpenSchoolImpl) ClassesList(ctx *gin.Context, params api.ClassesListParams) {
-	if ok := auth.MustAuthenticate(ctx, i.TeacherRepository); ok {
+	if ok := auth.MustAuthorize(ctx, i.TeacherRepository, "classesList"); ok {
 		return
 	}
 
@@ -55,19 +57,26 @@ func (i *OpenSchoolImpl) ClassesList(ctx *gin.Context, params api.ClassesListPar
 
 // ClassesCreate implements the classesCreate contract from the OpenAPI spec.
 func (i *OpenSchoolImpl) ClassesCreate(ctx *gin.Context) {
+	if ok := auth.MustAuthorize(ctx, i.TeacherRepository, "classesCreate"); ok {
+		return
+	}
+
 	var body api.ClassesCreateRequest
 	if err := ctx.BindJSON(&body); err != nil {
 		_ = ctx.AbortWithError(400, err)
+		return
 	}
 
-	sd, err := time.Parse(time.RFC3339, *body.StartDate)
+	sd, err := parseDate(body.StartDate, time.Time{})
 	if err != nil {
 		_ = ctx.AbortWithError(400, err)
+		return
 	}
 
-	ed, err := time.Parse(time.RFC3339, *body.EndDate)
+	ed, err := parseDate(body.EndDate, time.Time{})
 	if err != nil {
 		_ = ctx.AbortWithError(400, err)
+		return
 	}
 
 	in := models.Class{
@@ -86,9 +95,24 @@ func (i *OpenSchoolImpl) ClassesCreate(ctx *gin.Context) {
 		in.Description = body.Description
 	}
 
-	class, err := i.ClassRepository.Create(in)
+	if body.TeacherIds != nil {
+		in.TeacherIds = *body.TeacherIds
+	}
+
+	var class *models.Class
+	err = i.Storage.Atomically(func(tx *storage.Repositories) error {
+		var err error
+		if class, err = tx.Classes.Create(in); err != nil {
+			return err
+		}
+		if err := i.publish(ctx, tx, events.ClassCreated, class.Id, class.AsApiClass()); err != nil {
+			return err
+		}
+		return i.audit(ctx, tx, "classesCreate", class.Id, nil, class.AsApiClass())
+	})
 	if err != nil {
 		_ = ctx.AbortWithError(http.StatusInternalServerError, err)
+		return
 	}
 
 	response := api.ClassesCreateResponse{
@@ -100,6 +124,10 @@ func (i *OpenSchoolImpl) ClassesCreate(ctx *gin.Context) {
 
 // ClassesGet implements the classesGet contract from the OpenAPI spec.
 func (i *OpenSchoolImpl) ClassesGet(ctx *gin.Context, id api.Cuid) {
+	if ok := auth.MustAuthorize(ctx, i.TeacherRepository, "classesGet"); ok {
+		return
+	}
+
 	class, err := i.ClassRepository.Get(id)
 	if err != nil {
 		_ = ctx.AbortWithError(http.StatusInternalServerError, err)
@@ -115,46 +143,127 @@ func (i *OpenSchoolImpl) ClassesGet(ctx *gin.Context, id api.Cuid) {
 }
 
 func (i *OpenSchoolImpl) ClassesUpdate(ctx *gin.Context, id api.Cuid) {
+	if ok := auth.MustAuthorize(ctx, i.TeacherRepository, "classesUpdate"); ok {
+		return
+	}
+	if ok := auth.MustTeachClass(ctx, i.ClassRepository, id); ok {
+		return
+	}
+
 	var body api.ClassesUpdateJSONRequestBody
 	_ = ctx.Bind(&body)
 
-	sd, err := time.Parse(time.RFC3339, *body.StartDate)
+	before, err := i.ClassRepository.Get(id)
+	if err != nil {
+		_ = ctx.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if before == nil {
+		_ = ctx.AbortWithError(http.StatusNotFound, classes.ClassDoesNotExist)
+		return
+	}
+
+	// Dates left out of the request keep their current value.
+	sd, err := parseDate(body.StartDate, before.StartDate)
 	if err != nil {
 		_ = ctx.AbortWithError(400, err)
+		return
 	}
 
-	ed, err := time.Parse(time.RFC3339, *body.EndDate)
+	ed, err := parseDate(body.EndDate, before.EndDate)
 	if err != nil {
 		_ = ctx.AbortWithError(400, err)
+		return
 	}
 
 	class := &models.Class{
-    StartDate: sd,
-    EndDate: ed,
-  }
+		StartDate: sd,
+		EndDate:   ed,
+	}
 	class.Id = id
 	class = class.ReconcileWithApiClass(body.Description, body.DisplayName)
 
-	class, err = i.ClassRepository.Update(class)
+	// Teachers could otherwise assign themselves to any class, so only admins
+	// may change who teaches a class.
+	if body.TeacherIds != nil {
+		if ctx.MustGet("user").(*models.Teacher).Role != models.RoleAdmin {
+			_ = ctx.AbortWithError(http.StatusForbidden, errors.New("only admins may assign teachers to a class"))
+			return
+		}
+		class.TeacherIds = *body.TeacherIds
+	}
+
+	err = i.Storage.Atomically(func(tx *storage.Repositories) error {
+		var err error
+		if class, err = tx.Classes.Update(class); err != nil {
+			return err
+		}
+		if err := i.publish(ctx, tx, events.ClassUpdated, class.Id, class.AsApiClass()); err != nil {
+			return err
+		}
+		return i.audit(ctx, tx, "classesUpdate", class.Id, before.AsApiClass(), class.AsApiClass())
+	})
 	if err != nil {
-		_ = ctx.AbortWithError(http.StatusInternalServerError, err)
+		if err == classes.ClassDoesNotExist {
+			_ = ctx.AbortWithError(http.StatusNotFound, err)
+		} else {
+			_ = ctx.AbortWithError(http.StatusInternalServerError, err)
+		}
+		return
 	}
 
-	ctx.JSON(http.StatusOK, api.ClassesUpdateResponse{Class: class.AsApiClass()})
+	response := api.ClassesUpdateResponse{Class: class.AsApiClass()}
+
+	ctx.JSON(http.StatusOK, response)
 }
 
 func (i *OpenSchoolImpl) ClassesDelete(ctx *gin.Context, id api.Cuid) {
+	if ok := auth.MustAuthorize(ctx, i.TeacherRepository, "classesDelete"); ok {
+		return
+	}
+
+	before, err := i.ClassRepository.Get(id)
+	if err != nil {
+		_ = ctx.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if before == nil {
+		_ = ctx.AbortWithError(http.StatusNotFound, classes.ClassDoesNotExist)
+		return
+	}
+
 	class := models.Class{}
 	class.Id = id
 
-	err := i.ClassRepository.Delete(class)
+	err = i.Storage.Atomically(func(tx *storage.Repositories) error {
+		if err := tx.Classes.Delete(class); err != nil {
+			return err
+		}
+		if err := i.publish(ctx, tx, events.ClassDeleted, id, events.DeletedPayload{Id: id}); err != nil {
+			return err
+		}
+		return i.audit(ctx, tx, "classesDelete", id, before.AsApiClass(), nil)
+	})
 	if err != nil {
 		if err == classes.ClassDoesNotExist {
 			_ = ctx.AbortWithError(http.StatusNotFound, err)
 		} else {
 			_ = ctx.AbortWithError(http.StatusInternalServerError, err)
 		}
+		return
 	}
 
 	ctx.JSON(http.StatusOK, gin.H{"ok": true})
 }
+
+// parseDate parses an RFC3339 date from a request body, returning fallback
+// when it was left out.
+func parseDate(value *api.DateTime, fallback time.Time) (time.Time, error) {
+	if value == nil {
+		return fallback, nil
+	}
+
+	return time.Parse(time.RFC3339, *value)
+}
@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/h4n-openschool/api/events"
+	"github.com/h4n-openschool/api/outbox"
+	"github.com/h4n-openschool/api/storage"
+)
+
+// publish records a domain event in the transaction of the write it describes,
+// run with [storage.Repositories.Atomically], so failing to record it fails the
+// write too.
+func (i *OpenSchoolImpl) publish(ctx *gin.Context, tx *storage.Repositories, eventType string, aggregateId string, payload any) error {
+	if i.Publisher == nil {
+		return nil
+	}
+
+	e, err := events.New(eventType, ctx.GetString("auth.userId"), aggregateId, payload)
+	if err != nil {
+		return err
+	}
+
+	return outbox.PublisherIn(i.Publisher, tx.Outbox).Publish(ctx.Request.Context(), e)
+}
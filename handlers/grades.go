@@ -3,21 +3,32 @@ package handlers
 import (
 	"errors"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/h4n-openschool/api/api"
 	"github.com/h4n-openschool/api/auth"
+	"github.com/h4n-openschool/api/events"
 	"github.com/h4n-openschool/api/models"
 	"github.com/h4n-openschool/api/repos/grades"
+	"github.com/h4n-openschool/api/storage"
 	"github.com/h4n-openschool/api/utils"
 )
 
+// reasonReasonRequired is the error reason of changes to grades that need a
+// reason but were given none.
+const reasonReasonRequired = "reason_required"
+
 // GradesList implements the gradesList operation from the OpenAPI
 // specification in [../api/spec.yaml].
 func (i *OpenSchoolImpl) GradesList(ctx *gin.Context, id api.Cuid, params api.GradesListParams) {
-  if ok := auth.MustAuthenticate(ctx, i.TeacherRepository); ok {
-    return
-  }
+	if ok := auth.MustAuthorize(ctx, i.TeacherRepository, "gradesList"); ok {
+		return
+	}
+	if ok := auth.MustTeachClass(ctx, i.ClassRepository, id); ok {
+		return
+	}
 
 	// Read pagination options from the GradesListParams object
 	pagination := utils.NewPaginationQuery()
@@ -27,23 +38,25 @@ func (i *OpenSchoolImpl) GradesList(ctx *gin.Context, id api.Cuid, params api.Gr
 	grades, err := i.GradeRepository.GetAll(id, pagination)
 	if err != nil {
 		_ = ctx.AbortWithError(http.StatusInternalServerError, err)
+		return
 	}
 
-	// Get the total number of grades in the database to build the PaginationData object.
-	total, err := i.GradeRepository.Count()
+	// Get the number of grades in the class to build the PaginationData object.
+	total, err := i.GradeRepository.CountForClass(id)
 	if err != nil {
 		_ = ctx.AbortWithError(http.StatusInternalServerError, err)
+		return
 	}
 
 	// Generate pagination data from the total and input pagination options.
-	paginationData := utils.GeneratePaginationData("/v1/grades", total, pagination)
+	paginationData := utils.GeneratePaginationData("/v1/classes/"+id+"/grades", total, pagination)
 
 	// Convert the grade model array to an api.GradeList type to meet the OpenAPI definition.
 	gradeList := models.GradesAsApiGradeList(grades)
 
 	// Build the response body as a GradesListResponse type.
 	response := api.GradesListResponse{
-		Grades:    gradeList,
+		Grades:     gradeList,
 		Pagination: paginationData,
 	}
 
@@ -53,20 +66,39 @@ func (i *OpenSchoolImpl) GradesList(ctx *gin.Context, id api.Cuid, params api.Gr
 
 // GradesCreate implements the gradesCreate contract from the OpenAPI spec.
 func (i *OpenSchoolImpl) GradesCreate(ctx *gin.Context, classId api.Cuid) {
+	if ok := auth.MustAuthorize(ctx, i.TeacherRepository, "gradesCreate"); ok {
+		return
+	}
+	if ok := auth.MustTeachClass(ctx, i.ClassRepository, classId); ok {
+		return
+	}
+
 	var body api.GradesCreateRequest
 	if err := ctx.BindJSON(&body); err != nil {
 		_ = ctx.AbortWithError(400, err)
+		return
 	}
 
 	in := models.Grade{
-    ClassId: classId,
-    StudentId: body.StudentId,
-    Value: body.Value,
+		ClassId:   classId,
+		StudentId: body.StudentId,
+		Value:     body.Value,
 	}
 
-  grade, err := i.GradeRepository.Create(in)
+	var grade *models.Grade
+	err := i.Storage.Atomically(func(tx *storage.Repositories) error {
+		var err error
+		if grade, err = tx.Grades.Create(in, models.GradeChange{ChangedBy: ctx.GetString("auth.userId")}); err != nil {
+			return err
+		}
+		if err := i.publish(ctx, tx, events.GradeCreated, grade.Id, events.GradePayload{ClassId: classId, Grade: grade.AsApiGrade()}); err != nil {
+			return err
+		}
+		return i.audit(ctx, tx, "gradesCreate", grade.Id, nil, grade.AsApiGrade())
+	})
 	if err != nil {
 		_ = ctx.AbortWithError(http.StatusInternalServerError, err)
+		return
 	}
 
 	response := api.GradesCreateResponse{
@@ -78,14 +110,15 @@ func (i *OpenSchoolImpl) GradesCreate(ctx *gin.Context, classId api.Cuid) {
 
 // GradesGet implements the gradesGet contract from the OpenAPI spec.
 func (i *OpenSchoolImpl) GradesGet(ctx *gin.Context, id api.Cuid, grade api.Cuid) {
-	g, err := i.GradeRepository.Get(grade)
-	if err != nil {
-		_ = ctx.AbortWithError(http.StatusInternalServerError, err)
+	if ok := auth.MustAuthorize(ctx, i.TeacherRepository, "gradesGet"); ok {
+		return
+	}
+	if ok := auth.MustTeachClass(ctx, i.ClassRepository, id); ok {
 		return
 	}
 
-	if g == nil {
-		_ = ctx.AbortWithError(http.StatusNotFound, errors.New("grade not found"))
+	g, ok := i.getClassGrade(ctx, id, grade)
+	if !ok {
 		return
 	}
 
@@ -93,35 +126,245 @@ func (i *OpenSchoolImpl) GradesGet(ctx *gin.Context, id api.Cuid, grade api.Cuid
 }
 
 func (i *OpenSchoolImpl) GradesUpdate(ctx *gin.Context, id api.Cuid, grade api.Cuid) {
+	if ok := auth.MustAuthorize(ctx, i.TeacherRepository, "gradesUpdate"); ok {
+		return
+	}
+	if ok := auth.MustTeachClass(ctx, i.ClassRepository, id); ok {
+		return
+	}
+
 	var body api.GradesUpdateJSONRequestBody
 	_ = ctx.Bind(&body)
 
-	g := &models.Grade{}
-	g.Id = grade
-  if body.Value != nil {
-    g.Value = *body.Value
-  }
+	g, ok := i.getClassGrade(ctx, id, grade)
+	if !ok {
+		return
+	}
+
+	reason, ok := i.mustGiveReason(ctx, id, body.Reason)
+	if !ok {
+		return
+	}
+
+	before := g.AsApiGrade()
 
-	g, err := i.GradeRepository.Update(g)
+	if body.Value != nil {
+		g.Value = *body.Value
+	}
+
+	err := i.Storage.Atomically(func(tx *storage.Repositories) error {
+		var err error
+		g, err = tx.Grades.Update(g, models.GradeChange{
+			ChangedBy: ctx.GetString("auth.userId"),
+			Reason:    reason,
+		})
+		if err != nil {
+			return err
+		}
+		if err := i.publish(ctx, tx, events.GradeUpdated, g.Id, events.GradePayload{ClassId: id, Grade: g.AsApiGrade()}); err != nil {
+			return err
+		}
+		return i.audit(ctx, tx, "gradesUpdate", g.Id, before, g.AsApiGrade())
+	})
 	if err != nil {
-		_ = ctx.AbortWithError(http.StatusInternalServerError, err)
+		if err == grades.GradeDoesNotExist {
+			_ = ctx.AbortWithError(http.StatusNotFound, err)
+		} else {
+			_ = ctx.AbortWithError(http.StatusInternalServerError, err)
+		}
+		return
 	}
 
-	ctx.JSON(http.StatusOK, api.GradesUpdateResponse{Grade: g.AsApiGrade()})
+	response := api.GradesUpdateResponse{Grade: g.AsApiGrade()}
+
+	ctx.JSON(http.StatusOK, response)
 }
 
 func (i *OpenSchoolImpl) GradesDelete(ctx *gin.Context, id api.Cuid, grade api.Cuid) {
-	g := models.Grade{}
-	g.Id = grade
+	if ok := auth.MustAuthorize(ctx, i.TeacherRepository, "gradesDelete"); ok {
+		return
+	}
+	if ok := auth.MustTeachClass(ctx, i.ClassRepository, id); ok {
+		return
+	}
 
-	err := i.GradeRepository.Delete(g)
+	g, ok := i.getClassGrade(ctx, id, grade)
+	if !ok {
+		return
+	}
+
+	err := i.Storage.Atomically(func(tx *storage.Repositories) error {
+		if err := tx.Grades.Delete(*g); err != nil {
+			return err
+		}
+		if err := i.publish(ctx, tx, events.GradeDeleted, grade, events.DeletedPayload{Id: grade}); err != nil {
+			return err
+		}
+		return i.audit(ctx, tx, "gradesDelete", grade, g.AsApiGrade(), nil)
+	})
 	if err != nil {
 		if err == grades.GradeDoesNotExist {
 			_ = ctx.AbortWithError(http.StatusNotFound, err)
 		} else {
 			_ = ctx.AbortWithError(http.StatusInternalServerError, err)
 		}
+		return
 	}
 
 	ctx.JSON(http.StatusOK, gin.H{"ok": true})
 }
+
+// GradesHistory implements the gradesHistory contract from the OpenAPI spec.
+func (i *OpenSchoolImpl) GradesHistory(ctx *gin.Context, id api.Cuid, grade api.Cuid) {
+	if ok := auth.MustAuthorize(ctx, i.TeacherRepository, "gradesHistory"); ok {
+		return
+	}
+	if ok := auth.MustTeachClass(ctx, i.ClassRepository, id); ok {
+		return
+	}
+
+	if _, ok := i.getClassGrade(ctx, id, grade); !ok {
+		return
+	}
+
+	versions, err := i.GradeRepository.History(grade)
+	if err != nil {
+		if err == grades.GradeDoesNotExist {
+			_ = ctx.AbortWithError(http.StatusNotFound, err)
+		} else {
+			_ = ctx.AbortWithError(http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	response := api.GradesHistoryResponse{Versions: []api.GradeVersion{}}
+	for _, v := range versions {
+		response.Versions = append(response.Versions, v.AsApiGradeVersion())
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// GradesRevert implements the gradesRevert contract from the OpenAPI spec.
+func (i *OpenSchoolImpl) GradesRevert(ctx *gin.Context, id api.Cuid, grade api.Cuid) {
+	if ok := auth.MustAuthorize(ctx, i.TeacherRepository, "gradesRevert"); ok {
+		return
+	}
+
+	var body api.GradesRevertRequest
+	if err := ctx.BindJSON(&body); err != nil {
+		_ = ctx.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	g, ok := i.getClassGrade(ctx, id, grade)
+	if !ok {
+		return
+	}
+
+	versions, err := i.GradeRepository.History(grade)
+	if err != nil {
+		if err == grades.GradeDoesNotExist {
+			_ = ctx.AbortWithError(http.StatusNotFound, err)
+		} else {
+			_ = ctx.AbortWithError(http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	var target *models.GradeVersion
+	for k := range versions {
+		if versions[k].Version == body.Version {
+			target = &versions[k]
+		}
+	}
+	if target == nil {
+		_ = ctx.AbortWithError(http.StatusNotFound, errors.New("grade version not found"))
+		return
+	}
+
+	reason, ok := i.mustGiveReason(ctx, id, body.Reason)
+	if !ok {
+		return
+	}
+
+	before := g.AsApiGrade()
+	g.Value = target.Value
+
+	// Reverting adds a version rather than dropping the later ones, so the
+	// history still shows the value that was reverted.
+	err = i.Storage.Atomically(func(tx *storage.Repositories) error {
+		var err error
+		g, err = tx.Grades.Update(g, models.GradeChange{
+			ChangedBy: ctx.GetString("auth.userId"),
+			Reason:    reason,
+			RevertOf:  &target.Version,
+		})
+		if err != nil {
+			return err
+		}
+		if err := i.publish(ctx, tx, events.GradeUpdated, g.Id, events.GradePayload{ClassId: id, Grade: g.AsApiGrade()}); err != nil {
+			return err
+		}
+		return i.audit(ctx, tx, "gradesRevert", g.Id, before, g.AsApiGrade())
+	})
+	if err != nil {
+		if err == grades.GradeDoesNotExist {
+			_ = ctx.AbortWithError(http.StatusNotFound, err)
+		} else {
+			_ = ctx.AbortWithError(http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	response := api.GradesRevertResponse{Grade: g.AsApiGrade()}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// mustGiveReason returns the reason given for changing a grade of a class,
+// nil if it is blank. Once the term of the class has closed, grades are final
+// unless corrected for a reason, so it responds with 400 if there is none, and
+// returns false when the request has been aborted.
+func (i *OpenSchoolImpl) mustGiveReason(ctx *gin.Context, classId api.Cuid, reason *string) (*string, bool) {
+	if reason != nil {
+		if trimmed := strings.TrimSpace(*reason); trimmed != "" {
+			return &trimmed, true
+		}
+	}
+
+	class, err := i.ClassRepository.Get(classId)
+	if err != nil {
+		_ = ctx.AbortWithError(http.StatusInternalServerError, err)
+		return nil, false
+	}
+
+	if class == nil {
+		_ = ctx.AbortWithError(http.StatusNotFound, errors.New("Class not found"))
+		return nil, false
+	}
+
+	if class.TermClosed(time.Now()) {
+		_ = ctx.AbortWithError(http.StatusBadRequest, errors.New("The term of this class has closed, so changing its grades needs a reason.")).SetMeta(reasonReasonRequired)
+		return nil, false
+	}
+
+	return nil, true
+}
+
+// getClassGrade returns the grade with the given id, responding with 404 if it
+// does not exist or belongs to another class than the one in the path.
+func (i *OpenSchoolImpl) getClassGrade(ctx *gin.Context, classId api.Cuid, gradeId api.Cuid) (*models.Grade, bool) {
+	g, err := i.GradeRepository.Get(gradeId)
+	if err != nil {
+		_ = ctx.AbortWithError(http.StatusInternalServerError, err)
+		return nil, false
+	}
+
+	if g == nil || g.ClassId != classId {
+		_ = ctx.AbortWithError(http.StatusNotFound, errors.New("grade not found"))
+		return nil, false
+	}
+
+	return g, true
+}
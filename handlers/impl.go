@@ -1,10 +1,22 @@
 package handlers
 
 import (
+	"time"
+
+	"github.com/h4n-openschool/api/audit"
+	"github.com/h4n-openschool/api/events"
+	"github.com/h4n-openschool/api/lockout"
+	"github.com/h4n-openschool/api/notify"
+	"github.com/h4n-openschool/api/oidc"
+	"github.com/h4n-openschool/api/repos/apikeys"
 	"github.com/h4n-openschool/api/repos/classes"
 	"github.com/h4n-openschool/api/repos/grades"
 	"github.com/h4n-openschool/api/repos/students"
 	"github.com/h4n-openschool/api/repos/teachers"
+	"github.com/h4n-openschool/api/repos/tokens"
+	"github.com/h4n-openschool/api/repos/totp"
+	"github.com/h4n-openschool/api/storage"
+	"github.com/h4n-openschool/api/utils"
 	"go.uber.org/zap"
 )
 
@@ -15,5 +27,36 @@ type OpenSchoolImpl struct {
 	ClassRepository   classes.ClassRepository
 	TeacherRepository teachers.TeacherRepository
 	GradeRepository   grades.GradeRepository
+	TokenRepository   tokens.TokenRepository
+	TotpRepository    totp.TotpRepository
+	ApiKeyRepository  apikeys.ApiKeyRepository
+	Publisher         events.Publisher
+	Audit             audit.Store
+	Notifier          notify.Notifier
+	Keys              *utils.KeySet
+	Lockout           *lockout.Guard
+	Oidc              *oidc.Provider
 	Logger            *zap.Logger
+
+	// Storage runs writes together with the events describing them, see
+	// [storage.Repositories.Atomically].
+	Storage *storage.Repositories
+
+	// AccessTokenTtl is how long access tokens are valid for.
+	AccessTokenTtl time.Duration
+
+	// RefreshTokenTtl is how long refresh tokens are valid for. Refreshing
+	// issues a new refresh token with the full lifetime, so a session only
+	// ends after this long without any refresh.
+	RefreshTokenTtl time.Duration
+
+	// ResetTokenTtl is how long password reset tokens are valid for.
+	ResetTokenTtl time.Duration
+
+	// InvitationTtl is how long the invitations sent to new teachers are
+	// valid for.
+	InvitationTtl time.Duration
+
+	// TotpIssuer is the name authenticator apps list accounts under.
+	TotpIssuer string
 }
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/h4n-openschool/api/api"
+	"github.com/h4n-openschool/api/events"
+	"github.com/h4n-openschool/api/models"
+	"github.com/h4n-openschool/api/notify"
+	"github.com/h4n-openschool/api/storage"
+	"github.com/h4n-openschool/api/utils"
+	"github.com/lucsky/cuid"
+)
+
+// AuthActivate implements the authActivate contract from the OpenAPI spec.
+func (i *OpenSchoolImpl) AuthActivate(c *gin.Context) {
+	var body api.AuthActivateJSONRequestBody
+	if err := c.BindJSON(&body); err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	claims := jwt.RegisteredClaims{}
+	_, err := jwt.ParseWithClaims(body.Token, &claims, i.Keys.Keyfunc)
+	if err != nil || !claims.VerifyAudience(utils.AudienceInvitation, true) {
+		_ = c.AbortWithError(http.StatusBadRequest, errors.New("Invalid or expired invitation token."))
+		return
+	}
+
+	if !mustFitPassword(c, body.Password) {
+		return
+	}
+
+	hash, err := utils.HashPassword(body.Password)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to hash password: %w", err))
+		return
+	}
+
+	// Activating only succeeds for pending teachers, which makes invitation
+	// tokens single-use without having to store them.
+	var activated bool
+	var t *models.Teacher
+	err = i.Storage.Atomically(func(tx *storage.Repositories) error {
+		var err error
+		if activated, err = tx.Teachers.Activate(claims.Subject, hash); err != nil {
+			return fmt.Errorf("failed to activate teacher: %w", err)
+		}
+		if t, err = tx.Teachers.Get(claims.Subject); err != nil {
+			return fmt.Errorf("failed to get teacher: %w", err)
+		}
+		if t == nil || !activated {
+			return nil
+		}
+		if err := i.publish(c, tx, events.TeacherUpdated, t.Id, t.AsApiTeacher()); err != nil {
+			return err
+		}
+
+		pending := *t
+		pending.Status = models.TeacherPending
+		return i.auditAs(c, tx, t.Id, "authActivate", t.Id, pending.AsApiTeacher(), t.AsApiTeacher())
+	})
+	if err != nil {
+		_ = c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+	if t == nil {
+		_ = c.AbortWithError(http.StatusBadRequest, errors.New("Invalid or expired invitation token."))
+		return
+	}
+	if !activated {
+		_ = c.AbortWithError(http.StatusConflict, fmt.Errorf("This account can't be activated, it is %v.", t.Status))
+		return
+	}
+
+	response, err := i.issueTokens(t, cuid.New())
+	if err != nil {
+		_ = c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// sendInvitation sends a pending teacher the token they activate their account
+// with. Failing to do so is logged rather than failing the request, since the
+// teacher has already been created.
+func (i *OpenSchoolImpl) sendInvitation(c *gin.Context, t *models.Teacher) {
+	now := time.Now()
+	claims := jwt.RegisteredClaims{
+		ID:        cuid.New(),
+		Issuer:    `osapi`,
+		Subject:   t.Id,
+		Audience:  []string{utils.AudienceInvitation},
+		ExpiresAt: jwt.NewNumericDate(now.Add(i.InvitationTtl)),
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
+	}
+
+	token, err := i.Keys.Sign(claims)
+	if err == nil {
+		err = i.Notifier.Notify(c.Request.Context(), notify.Message{
+			To:      t.Email,
+			Subject: "Your OpenSchool account",
+			Body: fmt.Sprintf(
+				"An OpenSchool account has been created for you. Use this token to choose your password within %v:\n\n%v",
+				i.InvitationTtl, token,
+			),
+			SentAt: now,
+		})
+	}
+
+	if err != nil {
+		i.Logger.Sugar().Errorw("failed to send invitation", "userId", t.Id, "error", err)
+	}
+}
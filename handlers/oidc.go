@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/h4n-openschool/api/api"
+	"github.com/h4n-openschool/api/oidc"
+	"github.com/h4n-openschool/api/utils"
+	"github.com/lucsky/cuid"
+)
+
+const (
+	// oidcCookie holds the state of a login with the identity provider
+	// between the start and the callback.
+	oidcCookie = "osapi_oidc"
+
+	// oidcCookiePath limits the cookie to the endpoints of the flow.
+	oidcCookiePath = "/v1/auth/oidc"
+
+	// oidcStateTtl is how long users have to log in with the identity
+	// provider.
+	oidcStateTtl = 10 * time.Minute
+)
+
+// oidcState is the state of a login with the identity provider, kept in a
+// signed cookie. The verifier must never leave the browser and the server,
+// which is why it isn't part of the state sent to the provider.
+type oidcState struct {
+	jwt.RegisteredClaims
+
+	State    string `json:"state"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+}
+
+// AuthOidcStart implements the authOidcStart contract from the OpenAPI spec.
+func (i *OpenSchoolImpl) AuthOidcStart(c *gin.Context) {
+	if i.Oidc == nil {
+		_ = c.AbortWithError(http.StatusNotFound, errors.New("Logging in with an identity provider is not configured."))
+		return
+	}
+
+	state := oidcState{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        cuid.New(),
+			Issuer:    `osapi`,
+			Audience:  []string{utils.AudienceOidcState},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(oidcStateTtl)),
+		},
+	}
+	for _, v := range []*string{&state.State, &state.Nonce, &state.Verifier} {
+		var err error
+		if *v, err = newOpaqueToken(); err != nil {
+			_ = c.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to generate login state: %w", err))
+			return
+		}
+	}
+
+	cookie, err := i.Keys.Sign(state)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to sign login state: %w", err))
+		return
+	}
+
+	location, err := i.Oidc.AuthCodeURL(c.Request.Context(), state.State, state.Nonce, state.Verifier)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusBadGateway, fmt.Errorf("failed to reach the identity provider: %w", err))
+		return
+	}
+
+	i.setOidcCookie(c, cookie, int(oidcStateTtl.Seconds()))
+	c.Redirect(http.StatusFound, location)
+}
+
+// AuthOidcCallback implements the authOidcCallback contract from the OpenAPI
+// spec.
+func (i *OpenSchoolImpl) AuthOidcCallback(c *gin.Context, params api.AuthOidcCallbackParams) {
+	if i.Oidc == nil {
+		_ = c.AbortWithError(http.StatusNotFound, errors.New("Logging in with an identity provider is not configured."))
+		return
+	}
+
+	// The state only works once, whatever the outcome.
+	cookie, _ := c.Cookie(oidcCookie)
+	i.setOidcCookie(c, "", -1)
+
+	state := oidcState{}
+	_, err := jwt.ParseWithClaims(cookie, &state, i.Keys.Keyfunc)
+	if err != nil || !state.VerifyAudience(utils.AudienceOidcState, true) {
+		_ = c.AbortWithError(http.StatusBadRequest, errors.New("The login has expired or was started in another browser, start again."))
+		return
+	}
+
+	if params.State == nil || subtle.ConstantTimeCompare([]byte(*params.State), []byte(state.State)) != 1 {
+		_ = c.AbortWithError(http.StatusBadRequest, errors.New("The login state does not match, start again."))
+		return
+	}
+
+	if params.Error != nil {
+		message := *params.Error
+		if params.ErrorDescription != nil {
+			message += ": " + *params.ErrorDescription
+		}
+		_ = c.AbortWithError(http.StatusUnauthorized, fmt.Errorf("The identity provider refused the login (%v).", message))
+		return
+	}
+
+	if params.Code == nil {
+		_ = c.AbortWithError(http.StatusBadRequest, errors.New("The identity provider sent no code."))
+		return
+	}
+
+	identity, err := i.Oidc.Exchange(c.Request.Context(), *params.Code, state.Verifier, state.Nonce)
+	if err != nil {
+		if errors.Is(err, oidc.InvalidGrant) {
+			_ = c.AbortWithError(http.StatusUnauthorized, errors.New("The login with the identity provider has expired, start again."))
+			return
+		}
+		if errors.Is(err, oidc.InvalidIdToken) {
+			_ = c.Error(gin.Error{Err: err, Type: gin.ErrorTypePrivate})
+			_ = c.AbortWithError(http.StatusUnauthorized, errors.New("The identity provider sent an invalid ID token."))
+			return
+		}
+		_ = c.AbortWithError(http.StatusBadGateway, fmt.Errorf("failed to complete the login with the identity provider: %w", err))
+		return
+	}
+
+	if identity.Email == "" || identity.EmailVerified == nil || !*identity.EmailVerified {
+		_ = c.AbortWithError(http.StatusUnauthorized, errors.New("The identity provider did not assert a verified email."))
+		return
+	}
+
+	t, err := i.TeacherRepository.GetByEmail(identity.Email)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to get teacher: %w", err))
+		return
+	}
+
+	if t == nil {
+		_ = c.AbortWithError(http.StatusForbidden, errors.New("No teacher has the email asserted by the identity provider.")).SetMeta(reasonAccountUnknown)
+		return
+	}
+
+	if aborted := abortInactive(c, t); aborted {
+		return
+	}
+
+	i.Logger.Sugar().Infow("logged in with identity provider", "userId", t.Id, "subject", identity.Subject)
+	i.respondLogin(c, t)
+}
+
+// setOidcCookie sets the login state cookie, or deletes it if maxAge is
+// negative. It is only sent over HTTPS if the callback is served over HTTPS.
+func (i *OpenSchoolImpl) setOidcCookie(c *gin.Context, value string, maxAge int) {
+	secure := false
+	if u, err := url.Parse(i.Oidc.Config.RedirectUrl); err == nil {
+		secure = strings.EqualFold(u.Scheme, "https")
+	}
+
+	// Lax still sends the cookie when the provider redirects back, which is a
+	// top-level navigation.
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcCookie, value, maxAge, oidcCookiePath, "", secure, true)
+}
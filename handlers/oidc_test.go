@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/h4n-openschool/api/api"
+	"github.com/h4n-openschool/api/oidc"
+	"github.com/h4n-openschool/api/oidc/oidctest"
+	"github.com/h4n-openschool/api/repos/teachers"
+	"github.com/h4n-openschool/api/repos/tokens"
+	"github.com/h4n-openschool/api/repos/totp"
+	"github.com/h4n-openschool/api/utils"
+	"go.uber.org/zap"
+)
+
+// newOidcTestServer serves the API with logging in through a mock identity
+// provider.
+func newOidcTestServer(t *testing.T) (*gin.Engine, *oidctest.Server) {
+	t.Helper()
+
+	idp := oidctest.NewServer(t, "osapi", "secret")
+
+	keys, err := utils.NewRandomKeySet()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	si := &OpenSchoolImpl{
+		TeacherRepository: teachers.NewInMemoryTeacherRepository(0),
+		TokenRepository:   tokens.NewInMemoryTokenRepository(),
+		TotpRepository:    totp.NewInMemoryTotpRepository(),
+		Keys:              keys,
+		Oidc: oidc.NewProvider(oidc.Config{
+			Issuer:       idp.Issuer(),
+			ClientId:     idp.ClientId,
+			ClientSecret: idp.ClientSecret,
+			RedirectUrl:  "http://osapi.example/v1/auth/oidc/callback",
+		}),
+		Logger:          zap.NewNop(),
+		AccessTokenTtl:  time.Minute,
+		RefreshTokenTtl: time.Hour,
+	}
+
+	gin.SetMode(gin.TestMode)
+	return api.RegisterHandlers(gin.New(), si), idp
+}
+
+// oidcLogin logs in as the teacher with the given email, and returns the
+// response to the callback. tamper, if set, may change the callback URL
+// before it is requested.
+func oidcLogin(t *testing.T, e *gin.Engine, idp *oidctest.Server, email string, tamper func(callback *url.URL)) *httptest.ResponseRecorder {
+	t.Helper()
+
+	start := httptest.NewRecorder()
+	e.ServeHTTP(start, httptest.NewRequest(http.MethodGet, "/v1/auth/oidc/start", nil))
+	if start.Code != http.StatusFound {
+		t.Fatalf("start answered %v, want %v", start.Code, http.StatusFound)
+	}
+
+	callback, err := idp.Authorize(start.Header().Get("Location"), email)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tamper != nil {
+		tamper(callback)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, callback.RequestURI(), nil)
+	for _, c := range start.Result().Cookies() {
+		req.AddCookie(c)
+	}
+
+	res := httptest.NewRecorder()
+	e.ServeHTTP(res, req)
+
+	return res
+}
+
+func TestAuthOidcLogin(t *testing.T) {
+	e, idp := newOidcTestServer(t)
+
+	res := oidcLogin(t, e, idp, "john.doe@school.edu", nil)
+	if res.Code != http.StatusOK {
+		t.Fatalf("callback answered %v, want %v", res.Code, http.StatusOK)
+	}
+
+	var body api.AuthLoginResponse
+	if err := json.Unmarshal(res.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Token == "" || body.RefreshToken == "" {
+		t.Errorf("callback issued no tokens: %s", res.Body)
+	}
+}
+
+func TestAuthOidcLoginRejected(t *testing.T) {
+	tests := []struct {
+		name   string
+		email  string
+		tamper func(callback *url.URL)
+		claims func(claims jwt.MapClaims)
+		sign   func(idp *oidctest.Server) func(claims jwt.MapClaims) (string, error)
+		want   int
+	}{
+		{
+			name: "state mismatch",
+			tamper: func(callback *url.URL) {
+				q := callback.Query()
+				q.Set("state", "forged")
+				callback.RawQuery = q.Encode()
+			},
+			want: http.StatusBadRequest,
+		},
+		{
+			name:   "nonce mismatch",
+			claims: func(claims jwt.MapClaims) { claims["nonce"] = "replayed" },
+			want:   http.StatusUnauthorized,
+		},
+		{
+			name:   "wrong issuer",
+			claims: func(claims jwt.MapClaims) { claims["iss"] = "https://evil.example" },
+			want:   http.StatusUnauthorized,
+		},
+		{
+			name:   "wrong audience",
+			claims: func(claims jwt.MapClaims) { claims["aud"] = "another-client" },
+			want:   http.StatusUnauthorized,
+		},
+		{
+			name: "signed with HS256",
+			sign: func(idp *oidctest.Server) func(claims jwt.MapClaims) (string, error) {
+				return func(claims jwt.MapClaims) (string, error) {
+					return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(idp.ClientSecret))
+				}
+			},
+			want: http.StatusUnauthorized,
+		},
+		{
+			name:   "unverified email",
+			claims: func(claims jwt.MapClaims) { claims["email_verified"] = false },
+			want:   http.StatusUnauthorized,
+		},
+		{
+			name:   "email not asserted as verified",
+			claims: func(claims jwt.MapClaims) { delete(claims, "email_verified") },
+			want:   http.StatusUnauthorized,
+		},
+		{
+			name:  "unknown email",
+			email: "nobody@school.edu",
+			want:  http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, idp := newOidcTestServer(t)
+			idp.Claims = tt.claims
+			if tt.sign != nil {
+				idp.Sign = tt.sign(idp)
+			}
+
+			email := tt.email
+			if email == "" {
+				email = "john.doe@school.edu"
+			}
+
+			res := oidcLogin(t, e, idp, email, tt.tamper)
+			if res.Code != tt.want {
+				t.Errorf("callback answered %v, want %v", res.Code, tt.want)
+			}
+			if res.Code == http.StatusOK {
+				t.Errorf("callback issued tokens: %s", res.Body)
+			}
+		})
+	}
+}
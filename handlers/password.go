@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/h4n-openschool/api/api"
+	"github.com/h4n-openschool/api/auth"
+	"github.com/h4n-openschool/api/models"
+	"github.com/h4n-openschool/api/notify"
+	"github.com/h4n-openschool/api/repos/teachers"
+	"github.com/h4n-openschool/api/storage"
+	"github.com/h4n-openschool/api/utils"
+	"github.com/lucsky/cuid"
+)
+
+// AuthPasswordChange implements the authPasswordChange contract from the
+// OpenAPI spec.
+func (i *OpenSchoolImpl) AuthPasswordChange(c *gin.Context) {
+	if ok := auth.MustAuthorize(c, i.TeacherRepository, "authPasswordChange"); ok {
+		return
+	}
+
+	var body api.AuthPasswordChangeJSONRequestBody
+	if err := c.BindJSON(&body); err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+	if !mustFitPassword(c, body.NewPassword) {
+		return
+	}
+
+	t := c.MustGet("user").(*models.Teacher)
+	if !utils.CheckPassword(t.PasswordHash, body.CurrentPassword) {
+		_ = c.AbortWithError(http.StatusForbidden, errors.New("The current password is incorrect."))
+		return
+	}
+
+	if err := i.setPassword(c, "authPasswordChange", t.Id, body.NewPassword); err != nil {
+		_ = c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	// Every session, this one included, was revoked along with the old
+	// password, so carry on in a new one.
+	response, err := i.issueTokens(t, cuid.New())
+	if err != nil {
+		_ = c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// AuthPasswordReset implements the authPasswordReset contract from the OpenAPI
+// spec.
+func (i *OpenSchoolImpl) AuthPasswordReset(c *gin.Context) {
+	var body api.AuthPasswordResetJSONRequestBody
+	if err := c.BindJSON(&body); err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	t, err := i.TeacherRepository.GetByEmail(body.Email)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to get teacher: %w", err))
+		return
+	}
+
+	// Answer the same whether or not the teacher exists. Pending teachers
+	// activate their account with their invitation instead, and disabled
+	// teachers may not log in at all.
+	if t == nil || t.Status != models.TeacherActive {
+		c.Status(http.StatusAccepted)
+		return
+	}
+
+	token, err := newOpaqueToken()
+	if err != nil {
+		_ = c.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to generate reset token: %w", err))
+		return
+	}
+
+	now := time.Now()
+	err = i.TokenRepository.CreateOneTimeToken(models.OneTimeToken{
+		Hash:      utils.HashToken(token),
+		Purpose:   models.PurposePasswordReset,
+		UserId:    t.Id,
+		ExpiresAt: now.Add(i.ResetTokenTtl),
+		CreatedAt: now,
+	})
+	if err != nil {
+		_ = c.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to store reset token: %w", err))
+		return
+	}
+
+	// Failing to deliver is logged rather than reported, since telling the
+	// caller would reveal that the teacher exists.
+	err = i.Notifier.Notify(c.Request.Context(), notify.Message{
+		To:      t.Email,
+		Subject: "Reset your OpenSchool password",
+		Body: fmt.Sprintf(
+			"Somebody asked to reset the password of your OpenSchool account. If it was you, use this token to choose a new password within %v:\n\n%v\n\nOtherwise, you can ignore this message.",
+			i.ResetTokenTtl, token,
+		),
+		SentAt: now,
+	})
+	if err != nil {
+		i.Logger.Sugar().Errorw("failed to send password reset token", "userId", t.Id, "error", err)
+	}
+
+	c.Status(http.StatusAccepted)
+}
+
+// AuthPasswordResetConfirm implements the authPasswordResetConfirm contract
+// from the OpenAPI spec.
+func (i *OpenSchoolImpl) AuthPasswordResetConfirm(c *gin.Context) {
+	var body api.AuthPasswordResetConfirmJSONRequestBody
+	if err := c.BindJSON(&body); err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	// Check the password before using the token, which only works once.
+	if !mustFitPassword(c, body.NewPassword) {
+		return
+	}
+
+	userId, err := i.TokenRepository.UseOneTimeToken(utils.HashToken(body.Token), models.PurposePasswordReset, time.Now())
+	if err != nil {
+		_ = c.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to use reset token: %w", err))
+		return
+	}
+	if userId == "" {
+		_ = c.AbortWithError(http.StatusBadRequest, errors.New("Invalid or expired reset token."))
+		return
+	}
+
+	if err := i.setPassword(c, "authPasswordResetConfirm", userId, body.NewPassword); err != nil {
+		if errors.Is(err, teachers.TeacherDoesNotExist) {
+			_ = c.AbortWithError(http.StatusBadRequest, errors.New("Invalid or expired reset token."))
+			return
+		}
+		_ = c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// mustFitPassword aborts with 400 and returns false if password is too long
+// to be hashed.
+func mustFitPassword(c *gin.Context, password string) bool {
+	if len([]byte(password)) > utils.MaxPasswordBytes {
+		_ = c.AbortWithError(http.StatusBadRequest, fmt.Errorf("Passwords may be at most %v bytes long.", utils.MaxPasswordBytes))
+		return false
+	}
+
+	return true
+}
+
+// setPassword replaces the password of a teacher and revokes all of their
+// sessions, which may have been started with the old password. The change is
+// recorded as the given operation, made by the teacher themselves.
+func (i *OpenSchoolImpl) setPassword(c *gin.Context, operation string, userId string, password string) error {
+	hash, err := utils.HashPassword(password)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	return i.Storage.Atomically(func(tx *storage.Repositories) error {
+		if err := tx.Teachers.SetPasswordHash(userId, hash); err != nil {
+			return fmt.Errorf("failed to set password: %w", err)
+		}
+
+		if err := tx.Tokens.RevokeUserSessions(userId, time.Now().Add(i.AccessTokenTtl)); err != nil {
+			return fmt.Errorf("failed to revoke sessions: %w", err)
+		}
+
+		// Passwords are secrets, so only that one was changed is recorded.
+		return i.auditAs(c, tx, userId, operation, userId, nil, nil)
+	})
+}
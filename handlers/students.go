@@ -7,17 +7,19 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/h4n-openschool/api/api"
 	"github.com/h4n-openschool/api/auth"
+	"github.com/h4n-openschool/api/events"
 	"github.com/h4n-openschool/api/models"
 	"github.com/h4n-openschool/api/repos/students"
+	"github.com/h4n-openschool/api/storage"
 	"github.com/h4n-openschool/api/utils"
 )
 
 // StudentsList implements the studentsList operation from the OpenAPI
 // specification in [../api/spec.yaml].
 func (i *OpenSchoolImpl) StudentsList(ctx *gin.Context, params api.StudentsListParams) {
-  if ok := auth.MustAuthenticate(ctx, i.TeacherRepository); ok {
-    return
-  }
+	if ok := auth.MustAuthorize(ctx, i.TeacherRepository, "studentsList"); ok {
+		return
+	}
 
 	// Read pagination options from the StudentsListParams object
 	pagination := utils.NewPaginationQuery()
@@ -43,7 +45,7 @@ func (i *OpenSchoolImpl) StudentsList(ctx *gin.Context, params api.StudentsListP
 
 	// Build the response body as a StudentsListResponse type.
 	response := api.StudentsListResponse{
-		Students:    studentList,
+		Students:   studentList,
 		Pagination: paginationData,
 	}
 
@@ -53,18 +55,34 @@ func (i *OpenSchoolImpl) StudentsList(ctx *gin.Context, params api.StudentsListP
 
 // StudentsCreate implements the studentsCreate contract from the OpenAPI spec.
 func (i *OpenSchoolImpl) StudentsCreate(ctx *gin.Context) {
+	if ok := auth.MustAuthorize(ctx, i.TeacherRepository, "studentsCreate"); ok {
+		return
+	}
+
 	var body api.StudentsCreateRequest
 	if err := ctx.BindJSON(&body); err != nil {
 		_ = ctx.AbortWithError(400, err)
+		return
 	}
 
 	in := models.Student{
-    FullName: body.FullName,
+		FullName: body.FullName,
 	}
 
-	student, err := i.StudentRepository.Create(in)
+	var student *models.Student
+	err := i.Storage.Atomically(func(tx *storage.Repositories) error {
+		var err error
+		if student, err = tx.Students.Create(in); err != nil {
+			return err
+		}
+		if err := i.publish(ctx, tx, events.StudentCreated, student.Id, student.AsApiStudent()); err != nil {
+			return err
+		}
+		return i.audit(ctx, tx, "studentsCreate", student.Id, nil, student.AsApiStudent())
+	})
 	if err != nil {
 		_ = ctx.AbortWithError(http.StatusInternalServerError, err)
+		return
 	}
 
 	response := api.StudentsCreateResponse{
@@ -76,6 +94,10 @@ func (i *OpenSchoolImpl) StudentsCreate(ctx *gin.Context) {
 
 // StudentsGet implements the studentsGet contract from the OpenAPI spec.
 func (i *OpenSchoolImpl) StudentsGet(ctx *gin.Context, id api.Cuid) {
+	if ok := auth.MustAuthorize(ctx, i.TeacherRepository, "studentsGet"); ok {
+		return
+	}
+
 	student, err := i.StudentRepository.Get(id)
 	if err != nil {
 		_ = ctx.AbortWithError(http.StatusInternalServerError, err)
@@ -91,32 +113,87 @@ func (i *OpenSchoolImpl) StudentsGet(ctx *gin.Context, id api.Cuid) {
 }
 
 func (i *OpenSchoolImpl) StudentsUpdate(ctx *gin.Context, id api.Cuid) {
+	if ok := auth.MustAuthorize(ctx, i.TeacherRepository, "studentsUpdate"); ok {
+		return
+	}
+
 	var body api.StudentsUpdateJSONRequestBody
 	_ = ctx.Bind(&body)
 
+	before, err := i.StudentRepository.Get(id)
+	if err != nil {
+		_ = ctx.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if before == nil {
+		_ = ctx.AbortWithError(http.StatusNotFound, students.StudentDoesNotExist)
+		return
+	}
+
 	student := &models.Student{}
 	student.Id = id
 	student.FullName = body.FullName
 
-	student, err := i.StudentRepository.Update(student)
+	err = i.Storage.Atomically(func(tx *storage.Repositories) error {
+		var err error
+		if student, err = tx.Students.Update(student); err != nil {
+			return err
+		}
+		if err := i.publish(ctx, tx, events.StudentUpdated, student.Id, student.AsApiStudent()); err != nil {
+			return err
+		}
+		return i.audit(ctx, tx, "studentsUpdate", student.Id, before.AsApiStudent(), student.AsApiStudent())
+	})
 	if err != nil {
-		_ = ctx.AbortWithError(http.StatusInternalServerError, err)
+		if err == students.StudentDoesNotExist {
+			_ = ctx.AbortWithError(http.StatusNotFound, err)
+		} else {
+			_ = ctx.AbortWithError(http.StatusInternalServerError, err)
+		}
+		return
 	}
 
-	ctx.JSON(http.StatusOK, api.StudentsUpdateResponse{Student: student.AsApiStudent()})
+	response := api.StudentsUpdateResponse{Student: student.AsApiStudent()}
+
+	ctx.JSON(http.StatusOK, response)
 }
 
 func (i *OpenSchoolImpl) StudentsDelete(ctx *gin.Context, id api.Cuid) {
+	if ok := auth.MustAuthorize(ctx, i.TeacherRepository, "studentsDelete"); ok {
+		return
+	}
+
+	before, err := i.StudentRepository.Get(id)
+	if err != nil {
+		_ = ctx.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if before == nil {
+		_ = ctx.AbortWithError(http.StatusNotFound, students.StudentDoesNotExist)
+		return
+	}
+
 	student := models.Student{}
 	student.Id = id
 
-	err := i.StudentRepository.Delete(student)
+	err = i.Storage.Atomically(func(tx *storage.Repositories) error {
+		if err := tx.Students.Delete(student); err != nil {
+			return err
+		}
+		if err := i.publish(ctx, tx, events.StudentDeleted, id, events.DeletedPayload{Id: id}); err != nil {
+			return err
+		}
+		return i.audit(ctx, tx, "studentsDelete", id, before.AsApiStudent(), nil)
+	})
 	if err != nil {
 		if err == students.StudentDoesNotExist {
 			_ = ctx.AbortWithError(http.StatusNotFound, err)
 		} else {
 			_ = ctx.AbortWithError(http.StatusInternalServerError, err)
 		}
+		return
 	}
 
 	ctx.JSON(http.StatusOK, gin.H{"ok": true})
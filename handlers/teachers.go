@@ -3,17 +3,25 @@ package handlers
 import (
 	"errors"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/h4n-openschool/api/api"
+	"github.com/h4n-openschool/api/auth"
+	"github.com/h4n-openschool/api/events"
 	"github.com/h4n-openschool/api/models"
 	"github.com/h4n-openschool/api/repos/teachers"
+	"github.com/h4n-openschool/api/storage"
 	"github.com/h4n-openschool/api/utils"
 )
 
 // Teachers implements the teachersList operation from the OpenAPI
 // specification in [../api/spec.yaml].
 func (i *OpenSchoolImpl) TeachersList(ctx *gin.Context, params api.TeachersListParams) {
+	if ok := auth.MustAuthorize(ctx, i.TeacherRepository, "teachersList"); ok {
+		return
+	}
+
 	// Read pagination options from the ClassesListParams object
 	pagination := utils.NewPaginationQuery()
 	pagination.ReadFromOptional(params.Page, params.PerPage)
@@ -22,12 +30,14 @@ func (i *OpenSchoolImpl) TeachersList(ctx *gin.Context, params api.TeachersListP
 	classes, err := i.TeacherRepository.GetAll(pagination)
 	if err != nil {
 		_ = ctx.AbortWithError(http.StatusInternalServerError, err)
+		return
 	}
 
-	// Get the total number of classes in the database to build the PaginationData object.
-	total, err := i.ClassRepository.Count()
+	// Get the total number of teachers in the database to build the PaginationData object.
+	total, err := i.TeacherRepository.Count()
 	if err != nil {
 		_ = ctx.AbortWithError(http.StatusInternalServerError, err)
+		return
 	}
 
 	// Generate pagination data from the total and input pagination options.
@@ -48,28 +58,55 @@ func (i *OpenSchoolImpl) TeachersList(ctx *gin.Context, params api.TeachersListP
 
 // TeachersCreate implements the teachersCreate contract from the OpenAPI spec.
 func (i *OpenSchoolImpl) TeachersCreate(ctx *gin.Context) {
+	if ok := auth.MustAuthorize(ctx, i.TeacherRepository, "teachersCreate"); ok {
+		return
+	}
+
 	var body api.TeachersCreateJSONRequestBody
 	_ = ctx.Bind(&body)
 
+	// New teachers choose their own password when they accept the invitation
+	// sent to them.
 	in := models.Teacher{
 		FullName: body.FullName,
 		Email:    body.Email,
+		Status:   models.TeacherPending,
+	}
+	if body.Role != nil {
+		in.Role = models.Role(*body.Role)
 	}
 
-	teacher, err := i.TeacherRepository.Create(in)
+	var teacher *models.Teacher
+	err := i.Storage.Atomically(func(tx *storage.Repositories) error {
+		var err error
+		if teacher, err = tx.Teachers.Create(in); err != nil {
+			return err
+		}
+		if err := i.publish(ctx, tx, events.TeacherCreated, teacher.Id, teacher.AsApiTeacher()); err != nil {
+			return err
+		}
+		return i.audit(ctx, tx, "teachersCreate", teacher.Id, nil, teacher.AsApiTeacher())
+	})
 	if err != nil {
 		_ = ctx.AbortWithError(http.StatusInternalServerError, err)
+		return
 	}
 
 	response := api.TeachersCreateResponse{
 		Teacher: teacher.AsApiTeacher(),
 	}
 
+	i.sendInvitation(ctx, teacher)
+
 	ctx.JSON(http.StatusCreated, response)
 }
 
 // TeachersGet implements the teachersGet contract from the OpenAPI spec.
 func (i *OpenSchoolImpl) TeachersGet(ctx *gin.Context, id api.Cuid) {
+	if ok := auth.MustAuthorize(ctx, i.TeacherRepository, "teachersGet"); ok {
+		return
+	}
+
 	teacher, err := i.TeacherRepository.Get(id)
 	if err != nil {
 		_ = ctx.AbortWithError(http.StatusInternalServerError, err)
@@ -86,35 +123,144 @@ func (i *OpenSchoolImpl) TeachersGet(ctx *gin.Context, id api.Cuid) {
 
 // TeachersUpdate implements the teachersUpdate contract from the OpenAPI spec.
 func (i *OpenSchoolImpl) TeachersUpdate(ctx *gin.Context, id api.Cuid) {
+	if ok := auth.MustAuthorize(ctx, i.TeacherRepository, "teachersUpdate"); ok {
+		return
+	}
+
 	var body api.TeachersUpdateJSONRequestBody
 	_ = ctx.Bind(&body)
 
+	before, err := i.TeacherRepository.Get(id)
+	if err != nil {
+		_ = ctx.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if before == nil {
+		_ = ctx.AbortWithError(http.StatusNotFound, teachers.TeacherDoesNotExist)
+		return
+	}
+
 	teacher := &models.Teacher{}
 	teacher.Id = id
 	teacher.FullName = body.FullName
 	teacher.Email = body.Email
+	if body.Role != nil {
+		teacher.Role = models.Role(*body.Role)
+	}
+	if body.Status != nil {
+		if *body.Status == api.Pending {
+			_ = ctx.AbortWithError(http.StatusBadRequest, errors.New("Teachers can't be made pending."))
+			return
+		}
+		teacher.Status = models.TeacherStatus(*body.Status)
+	}
 
-	teacher, err := i.TeacherRepository.Update(teacher)
+	err = i.Storage.Atomically(func(tx *storage.Repositories) error {
+		var err error
+		if teacher, err = tx.Teachers.Update(teacher); err != nil {
+			return err
+		}
+		if err := i.publish(ctx, tx, events.TeacherUpdated, teacher.Id, teacher.AsApiTeacher()); err != nil {
+			return err
+		}
+		return i.audit(ctx, tx, "teachersUpdate", teacher.Id, before.AsApiTeacher(), teacher.AsApiTeacher())
+	})
 	if err != nil {
-		_ = ctx.AbortWithError(http.StatusInternalServerError, err)
+		if err == teachers.TeacherDoesNotExist {
+			_ = ctx.AbortWithError(http.StatusNotFound, err)
+		} else {
+			_ = ctx.AbortWithError(http.StatusInternalServerError, err)
+		}
+		return
 	}
 
-	ctx.JSON(http.StatusOK, api.TeachersUpdateResponse{Teacher: teacher.AsApiTeacher()})
+	// Disabled teachers are refused from their next request on, but end
+	// their sessions too so they can't be refreshed once re-enabled.
+	if teacher.Status == models.TeacherDisabled {
+		if err := i.TokenRepository.RevokeUserSessions(teacher.Id, time.Now().Add(i.AccessTokenTtl)); err != nil {
+			_ = ctx.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	response := api.TeachersUpdateResponse{Teacher: teacher.AsApiTeacher()}
+
+	ctx.JSON(http.StatusOK, response)
 }
 
 // TeachersDelete implements the teachersDelete contract from the OpenAPI spec.
 func (i *OpenSchoolImpl) TeachersDelete(ctx *gin.Context, id api.Cuid) {
+	if ok := auth.MustAuthorize(ctx, i.TeacherRepository, "teachersDelete"); ok {
+		return
+	}
+
+	before, err := i.TeacherRepository.Get(id)
+	if err != nil {
+		_ = ctx.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if before == nil {
+		_ = ctx.AbortWithError(http.StatusNotFound, teachers.TeacherDoesNotExist)
+		return
+	}
+
 	teacher := models.Teacher{}
 	teacher.Id = id
 
-	err := i.TeacherRepository.Delete(teacher)
+	err = i.Storage.Atomically(func(tx *storage.Repositories) error {
+		if err := tx.Teachers.Delete(teacher); err != nil {
+			return err
+		}
+		if err := i.publish(ctx, tx, events.TeacherDeleted, id, events.DeletedPayload{Id: id}); err != nil {
+			return err
+		}
+		return i.audit(ctx, tx, "teachersDelete", id, before.AsApiTeacher(), nil)
+	})
 	if err != nil {
 		if err == teachers.TeacherDoesNotExist {
 			_ = ctx.AbortWithError(http.StatusNotFound, err)
 		} else {
 			_ = ctx.AbortWithError(http.StatusInternalServerError, err)
 		}
+		return
 	}
 
 	ctx.JSON(http.StatusOK, gin.H{"ok": true})
 }
+
+// TeachersUnlock implements the teachersUnlock contract from the OpenAPI spec.
+func (i *OpenSchoolImpl) TeachersUnlock(ctx *gin.Context, id api.Cuid) {
+	if ok := auth.MustAuthorize(ctx, i.TeacherRepository, "teachersUnlock"); ok {
+		return
+	}
+
+	teacher, err := i.TeacherRepository.Get(id)
+	if err != nil {
+		_ = ctx.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if teacher == nil {
+		_ = ctx.AbortWithError(http.StatusNotFound, errors.New("Teacher not found"))
+		return
+	}
+
+	// Unlocking changes no field of the teacher, so there is nothing to
+	// snapshot, but who lifted a lockout is still worth recording. Failed
+	// logins are not kept in the storage, so unlocking comes last, once the
+	// record could be added.
+	err = i.Storage.Atomically(func(tx *storage.Repositories) error {
+		if err := i.audit(ctx, tx, "teachersUnlock", teacher.Id, nil, nil); err != nil {
+			return err
+		}
+		return i.Lockout.Unlock(teacher.Email)
+	})
+	if err != nil {
+		_ = ctx.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
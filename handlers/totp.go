@@ -0,0 +1,346 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/h4n-openschool/api/api"
+	"github.com/h4n-openschool/api/auth"
+	"github.com/h4n-openschool/api/models"
+	totpRepos "github.com/h4n-openschool/api/repos/totp"
+	"github.com/h4n-openschool/api/storage"
+	"github.com/h4n-openschool/api/totp"
+	"github.com/h4n-openschool/api/utils"
+	"github.com/lucsky/cuid"
+)
+
+const (
+	// challengeTtl is how long the second step of a login may take.
+	challengeTtl = 5 * time.Minute
+
+	// recoveryCodeCount is the number of recovery codes issued at a time.
+	recoveryCodeCount = 10
+)
+
+// recoveryEncoding is the alphabet recovery codes are written with.
+var recoveryEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// AuthLoginVerify implements the authLoginVerify contract from the OpenAPI
+// spec.
+func (i *OpenSchoolImpl) AuthLoginVerify(c *gin.Context) {
+	var body api.AuthLoginVerifyJSONRequestBody
+	if err := c.BindJSON(&body); err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	invalid := errors.New("Invalid or expired challenge token.")
+
+	claims := jwt.RegisteredClaims{}
+	_, err := jwt.ParseWithClaims(body.ChallengeToken, &claims, i.Keys.Keyfunc)
+	if err != nil || !claims.VerifyAudience(utils.AudienceLoginChallenge, true) {
+		_ = c.AbortWithError(http.StatusUnauthorized, invalid)
+		return
+	}
+
+	// Challenges are revoked once used, so each only completes one login.
+	revoked, err := i.TokenRepository.IsRevoked(claims.ID, "")
+	if err != nil {
+		_ = c.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to check challenge: %w", err))
+		return
+	}
+	if revoked {
+		_ = c.AbortWithError(http.StatusUnauthorized, invalid)
+		return
+	}
+
+	t, err := i.TeacherRepository.Get(claims.Subject)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to get teacher: %w", err))
+		return
+	}
+	if t == nil || t.Status != models.TeacherActive {
+		_ = c.AbortWithError(http.StatusUnauthorized, invalid)
+		return
+	}
+
+	// Codes are much easier to guess than passwords, so they count towards
+	// the same lockout.
+	now := time.Now()
+	wait, err := i.Lockout.Check(t.Email, c.ClientIP(), now)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to check lockout: %w", err))
+		return
+	}
+	if wait > 0 {
+		abortLockedOut(c, wait)
+		return
+	}
+
+	settings, err := i.TotpRepository.Get(t.Id)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to get two-factor settings: %w", err))
+		return
+	}
+	if settings == nil || !settings.Enabled() {
+		_ = c.AbortWithError(http.StatusUnauthorized, invalid)
+		return
+	}
+
+	ok, err := i.checkCode(settings, body.Code, now)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+	if !ok {
+		i.failLogin(c, t.Email)
+		_ = c.AbortWithError(http.StatusUnauthorized, errors.New("Invalid code."))
+		return
+	}
+
+	// Concurrent requests may all have passed the check above, so only the
+	// one revoking the challenge gets tokens.
+	used, err := i.TokenRepository.UseAccessToken(claims.ID, claims.ExpiresAt.Time)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to revoke challenge: %w", err))
+		return
+	}
+	if !used {
+		_ = c.AbortWithError(http.StatusUnauthorized, invalid)
+		return
+	}
+
+	if err := i.Lockout.Succeed(t.Email); err != nil {
+		i.Logger.Sugar().Errorw("failed to reset failed login attempts", "userId", t.Id, "error", err)
+	}
+
+	response, err := i.issueTokens(t, cuid.New())
+	if err != nil {
+		_ = c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// AuthTotpEnroll implements the authTotpEnroll contract from the OpenAPI spec.
+func (i *OpenSchoolImpl) AuthTotpEnroll(c *gin.Context) {
+	if ok := auth.MustAuthorize(c, i.TeacherRepository, "authTotpEnroll"); ok {
+		return
+	}
+
+	t := c.MustGet("user").(*models.Teacher)
+
+	secret, err := totp.NewSecret()
+	if err != nil {
+		_ = c.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to generate secret: %w", err))
+		return
+	}
+
+	if err := i.TotpRepository.Enroll(t.Id, secret, time.Now()); err != nil {
+		if errors.Is(err, totpRepos.TotpAlreadyEnabled) {
+			_ = c.AbortWithError(http.StatusConflict, errors.New("Two-factor authentication is already enabled, disable it first."))
+			return
+		}
+		_ = c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, api.AuthTotpEnrollResponse{
+		Secret: secret,
+		Uri:    totp.URI(i.TotpIssuer, t.Email, secret),
+	})
+}
+
+// AuthTotpConfirm implements the authTotpConfirm contract from the OpenAPI
+// spec.
+func (i *OpenSchoolImpl) AuthTotpConfirm(c *gin.Context) {
+	if ok := auth.MustAuthorize(c, i.TeacherRepository, "authTotpConfirm"); ok {
+		return
+	}
+
+	var body api.AuthTotpConfirmJSONRequestBody
+	if err := c.BindJSON(&body); err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	settings, ok := i.mustGetTotp(c, false)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	valid, err := i.checkCode(settings, body.Code, now)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+	if !valid {
+		_ = c.AbortWithError(http.StatusBadRequest, errors.New("Invalid code."))
+		return
+	}
+
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for k := range codes {
+		if codes[k], err = newRecoveryCode(); err != nil {
+			_ = c.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to generate recovery code: %w", err))
+			return
+		}
+		hashes[k] = utils.HashToken(normalizeRecoveryCode(codes[k]))
+	}
+
+	err = i.Storage.Atomically(func(tx *storage.Repositories) error {
+		if err := tx.Totp.Confirm(settings.UserId, hashes, now); err != nil {
+			return err
+		}
+		// The secret and recovery codes are never recorded.
+		return i.audit(c, tx, "authTotpConfirm", settings.UserId, nil, nil)
+	})
+	if err != nil {
+		_ = c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, api.AuthTotpConfirmResponse{RecoveryCodes: codes})
+}
+
+// AuthTotpDisable implements the authTotpDisable contract from the OpenAPI
+// spec.
+func (i *OpenSchoolImpl) AuthTotpDisable(c *gin.Context) {
+	if ok := auth.MustAuthorize(c, i.TeacherRepository, "authTotpDisable"); ok {
+		return
+	}
+
+	var body api.AuthTotpDisableJSONRequestBody
+	if err := c.BindJSON(&body); err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	settings, ok := i.mustGetTotp(c, true)
+	if !ok {
+		return
+	}
+
+	valid, err := i.checkCode(settings, body.Code, time.Now())
+	if err != nil {
+		_ = c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+	if !valid {
+		_ = c.AbortWithError(http.StatusBadRequest, errors.New("Invalid code."))
+		return
+	}
+
+	err = i.Storage.Atomically(func(tx *storage.Repositories) error {
+		if err := tx.Totp.Delete(settings.UserId); err != nil {
+			return err
+		}
+		return i.audit(c, tx, "authTotpDisable", settings.UserId, nil, nil)
+	})
+	if err != nil {
+		_ = c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// mustGetTotp returns the two-factor settings of the authorized user. It
+// responds with 409 if they haven't enrolled, or haven't confirmed when
+// enabled is true, and returns false when the request has been aborted.
+func (i *OpenSchoolImpl) mustGetTotp(c *gin.Context, enabled bool) (*models.Totp, bool) {
+	t := c.MustGet("user").(*models.Teacher)
+
+	settings, err := i.TotpRepository.Get(t.Id)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to get two-factor settings: %w", err))
+		return nil, false
+	}
+
+	if settings == nil {
+		_ = c.AbortWithError(http.StatusConflict, errors.New("Two-factor authentication has not been set up."))
+		return nil, false
+	}
+
+	if enabled && !settings.Enabled() {
+		_ = c.AbortWithError(http.StatusConflict, errors.New("Two-factor authentication is not enabled."))
+		return nil, false
+	}
+
+	return settings, true
+}
+
+// challenge issues the token the second step of a login is made with.
+func (i *OpenSchoolImpl) challenge(t *models.Teacher) (*api.AuthLoginChallenge, error) {
+	now := time.Now()
+	token, err := i.Keys.Sign(jwt.RegisteredClaims{
+		ID:        cuid.New(),
+		Issuer:    `osapi`,
+		Subject:   t.Id,
+		Audience:  []string{utils.AudienceLoginChallenge},
+		ExpiresAt: jwt.NewNumericDate(now.Add(challengeTtl)),
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate challenge: %w", err)
+	}
+
+	return &api.AuthLoginChallenge{
+		ChallengeToken: token,
+		ExpiresIn:      int(challengeTtl.Seconds()),
+	}, nil
+}
+
+// checkCode reports whether code is a valid code from the authenticator app
+// or an unused recovery code, and marks it used so it can't be replayed.
+func (i *OpenSchoolImpl) checkCode(settings *models.Totp, code string, now time.Time) (bool, error) {
+	code = strings.TrimSpace(code)
+
+	if len(code) == totp.Digits && strings.Trim(code, "0123456789") == "" {
+		step, ok := totp.Validate(settings.Secret, code, now)
+		if !ok {
+			return false, nil
+		}
+
+		used, err := i.TotpRepository.UseStep(settings.UserId, step)
+		if err != nil {
+			return false, fmt.Errorf("failed to use code: %w", err)
+		}
+		return used, nil
+	}
+
+	used, err := i.TotpRepository.UseRecoveryCode(settings.UserId, utils.HashToken(normalizeRecoveryCode(code)), now)
+	if err != nil {
+		return false, fmt.Errorf("failed to use recovery code: %w", err)
+	}
+	return used, nil
+}
+
+// newRecoveryCode generates a random recovery code, such as `abcde-fghij`.
+func newRecoveryCode() (string, error) {
+	b := make([]byte, 7)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	code := strings.ToLower(recoveryEncoding.EncodeToString(b))[:10]
+	return code[:5] + "-" + code[5:], nil
+}
+
+// normalizeRecoveryCode strips the formatting users may or may not type, so
+// it doesn't change the hash.
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
@@ -0,0 +1,63 @@
+package inbox
+
+import (
+	"sort"
+	"sync"
+)
+
+// InMemoryStore implements the [Store] interface using an in-memory set of
+// ids. It is safe for concurrent use.
+type InMemoryStore struct {
+	mu sync.Mutex
+
+	// ids are the ids of every applied command.
+	ids map[string]bool
+}
+
+// NewInMemoryStore creates a new instance of [InMemoryStore]
+func NewInMemoryStore() *InMemoryStore {
+	return NewInMemoryStoreFrom(nil)
+}
+
+// NewInMemoryStoreFrom creates a new instance of [InMemoryStore] holding the
+// given ids of applied commands, such as those of a snapshot.
+func NewInMemoryStoreFrom(ids []string) *InMemoryStore {
+	s := &InMemoryStore{ids: map[string]bool{}}
+	for _, id := range ids {
+		s.ids[id] = true
+	}
+
+	return s
+}
+
+func (s *InMemoryStore) Applied(id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.ids[id], nil
+}
+
+func (s *InMemoryStore) Record(id string, commandType string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ids[id] {
+		return true, nil
+	}
+	s.ids[id] = true
+
+	return false, nil
+}
+
+func (s *InMemoryStore) All() ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := make([]string, 0, len(s.ids))
+	for id := range s.ids {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	return ids, nil
+}
@@ -0,0 +1,24 @@
+// Package inbox remembers which commands from the message bus have been
+// applied, so that commands the broker delivers again, for example after a
+// consumer lost its connection before acknowledging them, are applied only
+// once.
+//
+// Commands are recorded in the transaction of the writes they cause, see
+// [storage.Repositories.Atomically], once they succeeded, so a command is
+// recorded if and only if it was applied. That holds for the memory driver
+// too, which has no transactions, as long as a command is not applied by
+// several consumers at once.
+package inbox
+
+// Store records the ids of applied commands.
+type Store interface {
+	// Applied reports whether the command with the given id was applied.
+	Applied(id string) (bool, error)
+
+	// Record marks the command with the given id as applied, reporting
+	// whether it had been already.
+	Record(id string, commandType string) (bool, error)
+
+	// All returns the ids of every applied command.
+	All() ([]string, error)
+}
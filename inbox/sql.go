@@ -0,0 +1,66 @@
+package inbox
+
+import (
+	"time"
+
+	"github.com/h4n-openschool/api/utils"
+)
+
+// SqlStore implements the [Store] interface on top of the `inbox_commands`
+// table. Queries are written to run on both PostgreSQL and SQLite.
+type SqlStore struct {
+	// DB is the database connection, or the transaction, used for every query.
+	DB utils.SqlConn
+}
+
+// NewSqlStore creates a new instance of [SqlStore]
+func NewSqlStore(db utils.SqlConn) *SqlStore {
+	return &SqlStore{DB: db}
+}
+
+func (s *SqlStore) Applied(id string) (bool, error) {
+	var count int
+	if err := s.DB.QueryRow(`SELECT COUNT(*) FROM inbox_commands WHERE id = $1`, id).Scan(&count); err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (s *SqlStore) Record(id string, commandType string) (bool, error) {
+	// A concurrent transaction recording the same command makes this insert
+	// wait for it, and do nothing once it committed.
+	res, err := s.DB.Exec(
+		`INSERT INTO inbox_commands (id, type, applied_at) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`,
+		id, commandType, time.Now().UTC(),
+	)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 0, nil
+}
+
+func (s *SqlStore) All() ([]string, error) {
+	rows, err := s.DB.Query(`SELECT id FROM inbox_commands ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
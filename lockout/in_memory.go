@@ -0,0 +1,88 @@
+package lockout
+
+import (
+	"sync"
+	"time"
+)
+
+// InMemoryStore implements the [Store] interface using an in-memory map. It
+// is safe for concurrent use.
+type InMemoryStore struct {
+	mu sync.Mutex
+
+	// failures maps every key to the times of its failed attempts, oldest
+	// first.
+	failures map[string][]time.Time
+
+	// retention is how long failed attempts are kept.
+	retention time.Duration
+
+	// swept is when keys without recent failures were last dropped.
+	swept time.Time
+}
+
+// NewInMemoryStore creates a new instance of [InMemoryStore] that keeps
+// failed attempts for retention, which should be the longest window of the
+// policies using it.
+func NewInMemoryStore(retention time.Duration) *InMemoryStore {
+	return &InMemoryStore{
+		failures:  map[string][]time.Time{},
+		retention: retention,
+	}
+}
+
+func (s *InMemoryStore) AddFailure(key string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures[key] = append(s.prune(s.failures[key], at), at)
+
+	// Sweeping every key is too costly to do on each failure, which would
+	// make a distributed attack quadratic.
+	if at.Sub(s.swept) > s.retention {
+		for k, failures := range s.failures {
+			if failures = s.prune(failures, at); len(failures) == 0 {
+				delete(s.failures, k)
+			} else {
+				s.failures[k] = failures
+			}
+		}
+		s.swept = at
+	}
+
+	return nil
+}
+
+func (s *InMemoryStore) Failures(key string, since time.Time) ([]time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var failures []time.Time
+	for _, at := range s.failures[key] {
+		if !at.Before(since) {
+			failures = append(failures, at)
+		}
+	}
+
+	return failures, nil
+}
+
+func (s *InMemoryStore) Reset(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.failures, key)
+	return nil
+}
+
+// prune returns failures without the attempts older than the retention.
+func (s *InMemoryStore) prune(failures []time.Time, now time.Time) []time.Time {
+	cutoff := now.Add(-s.retention)
+
+	k := 0
+	for k < len(failures) && failures[k].Before(cutoff) {
+		k++
+	}
+
+	return failures[k:]
+}
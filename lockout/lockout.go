@@ -0,0 +1,144 @@
+// Package lockout slows down password guessing. It counts failed login
+// attempts per account and per client address over a sliding window, and
+// makes callers wait exponentially longer between attempts once too many have
+// failed, up to a temporary lockout.
+package lockout
+
+import (
+	"strings"
+	"time"
+)
+
+// Store keeps the failed attempts of every key. The in-memory implementation
+// only counts attempts made against one instance; a shared implementation,
+// for example on Redis, counts them across every instance.
+type Store interface {
+	// AddFailure records a failed attempt for key at the given time.
+	AddFailure(key string, at time.Time) error
+
+	// Failures returns the times of the failed attempts recorded for key since
+	// the given time, oldest first. Implementations may forget older attempts.
+	Failures(key string, since time.Time) ([]time.Time, error)
+
+	// Reset forgets every failed attempt of key.
+	Reset(key string) error
+}
+
+// Policy decides how many failed attempts are tolerated before attempts are
+// refused, and for how long.
+type Policy struct {
+	// Window is how far back failed attempts are counted.
+	Window time.Duration
+
+	// Threshold is the number of failed attempts within the window that are
+	// tolerated without delay.
+	Threshold int
+
+	// BaseDelay is how long attempts are refused after the first failed
+	// attempt beyond the threshold. It doubles with every further failure.
+	BaseDelay time.Duration
+
+	// MaxDelay caps the delay, which at this point amounts to a temporary
+	// lockout.
+	MaxDelay time.Duration
+}
+
+// Delay returns how long after the last of failures attempts are refused.
+func (p Policy) Delay(failures int) time.Duration {
+	excess := failures - p.Threshold
+	if excess <= 0 {
+		return 0
+	}
+
+	delay := p.BaseDelay
+	for i := 1; i < excess && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+
+	if delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	return delay
+}
+
+// Guard applies a policy per account and another, usually more lenient since
+// many users may share an address, per client address.
+type Guard struct {
+	Store   Store
+	Account Policy
+	Client  Policy
+}
+
+// NewGuard creates a new instance of [Guard]
+func NewGuard(store Store, account Policy, client Policy) *Guard {
+	return &Guard{Store: store, Account: account, Client: client}
+}
+
+// Check returns how long the caller must wait before attempting to log in to
+// the account from the client address, or zero if it may try now.
+func (g *Guard) Check(account string, client string, now time.Time) (time.Duration, error) {
+	var wait time.Duration
+
+	for _, k := range g.keys(account, client) {
+		failures, err := g.Store.Failures(k.key, now.Add(-k.policy.Window))
+		if err != nil {
+			return 0, err
+		}
+		if len(failures) == 0 {
+			continue
+		}
+
+		last := failures[len(failures)-1]
+		if w := last.Add(k.policy.Delay(len(failures))).Sub(now); w > wait {
+			wait = w
+		}
+	}
+
+	return wait, nil
+}
+
+// Fail records a failed attempt to log in to the account from the client
+// address.
+func (g *Guard) Fail(account string, client string, at time.Time) error {
+	for _, k := range g.keys(account, client) {
+		if err := g.Store.AddFailure(k.key, at); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Succeed records a successful login to the account, forgetting its failed
+// attempts. Those of the client address are kept, so an attacker can't clear
+// them by logging in to an account of their own.
+func (g *Guard) Succeed(account string) error {
+	return g.Unlock(account)
+}
+
+// Unlock forgets every failed attempt to log in to the account, lifting any
+// lockout.
+func (g *Guard) Unlock(account string) error {
+	return g.Store.Reset(accountKey(account))
+}
+
+type policyKey struct {
+	key    string
+	policy Policy
+}
+
+func (g *Guard) keys(account string, client string) []policyKey {
+	keys := []policyKey{{accountKey(account), g.Account}}
+	if client != "" {
+		keys = append(keys, policyKey{"client:" + client, g.Client})
+	}
+
+	return keys
+}
+
+// accountKey returns the store key of an account. Emails are compared without
+// regard to case, so changing it doesn't buy more attempts.
+func accountKey(account string) string {
+	return "account:" + strings.ToLower(account)
+}
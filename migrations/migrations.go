@@ -0,0 +1,330 @@
+// Package migrations holds the versioned SQL schema of every SQL storage
+// driver, embedded in the binary, and applies it to a database.
+//
+// Each driver has its own directory of migration files named
+// `<version>_<name>.up.sql` and `<version>_<name>.down.sql`. Applied versions
+// are tracked in the `schema_migrations` table.
+package migrations
+
+import (
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed postgres/*.sql sqlite/*.sql
+var files embed.FS
+
+var (
+	NoMigrations      = errors.New("no migrations found for that driver")
+	InvalidName       = errors.New("migration names may only contain letters, digits and underscores")
+	NothingToRollBack = errors.New("there are no applied migrations to roll back")
+)
+
+var (
+	fileNamePattern = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+	namePattern     = regexp.MustCompile(`^\w+$`)
+)
+
+// Migration is a single versioned schema change.
+type Migration struct {
+	// Version orders migrations and is recorded once the migration is applied.
+	Version int64
+
+	// Name is the human-friendly part of the file name.
+	Name string
+
+	// Up is the SQL that applies the change.
+	Up string
+
+	// Down is the SQL that reverts the change.
+	Down string
+}
+
+// Status describes whether a migration has been applied to a database.
+type Status struct {
+	Migration
+
+	// AppliedAt is the time the migration was applied, or nil when pending.
+	AppliedAt *time.Time
+}
+
+// Load returns the embedded migrations of a storage driver, ordered by
+// version.
+func Load(driver string) ([]Migration, error) {
+	entries, err := fs.ReadDir(files, driver)
+	if err != nil || len(entries) == 0 {
+		return nil, fmt.Errorf("%w: %v", NoMigrations, driver)
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		match := fileNamePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+
+		version, _ := strconv.ParseInt(match[1], 10, 64)
+		content, err := fs.ReadFile(files, path.Join(driver, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		}
+
+		if match[3] == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	var migrations []Migration
+	for _, m := range byVersion {
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Migrator applies and reverts migrations on a database.
+type Migrator struct {
+	// DB is the database the migrations are applied to.
+	DB *sql.DB
+
+	// Migrations are all known migrations, ordered by version.
+	Migrations []Migration
+}
+
+// NewMigrator creates a [Migrator] for the embedded migrations of a storage
+// driver, creating the version tracking table if it does not exist yet.
+func NewMigrator(db *sql.DB, driver string) (*Migrator, error) {
+	migrations, err := Load(driver)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+  version    BIGINT PRIMARY KEY,
+  name       TEXT NOT NULL,
+  applied_at TIMESTAMP NOT NULL
+)`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	return &Migrator{DB: db, Migrations: migrations}, nil
+}
+
+// Status returns every known migration along with when it was applied.
+func (m *Migrator) Status() ([]Status, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []Status
+	for _, migration := range m.Migrations {
+		s := Status{Migration: migration}
+		if at, ok := applied[migration.Version]; ok {
+			at := at
+			s.AppliedAt = &at
+		}
+		statuses = append(statuses, s)
+	}
+
+	return statuses, nil
+}
+
+// Pending returns the migrations that have not been applied yet.
+func (m *Migrator) Pending() ([]Migration, error) {
+	statuses, err := m.Status()
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for _, s := range statuses {
+		if s.AppliedAt == nil {
+			pending = append(pending, s.Migration)
+		}
+	}
+
+	return pending, nil
+}
+
+// Up applies every pending migration in order, each in its own transaction,
+// and returns the ones that were applied.
+func (m *Migrator) Up() ([]Migration, error) {
+	pending, err := m.Pending()
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, migration := range pending {
+		err := m.inTx(migration.Up, func(tx *sql.Tx) error {
+			_, err := tx.Exec(
+				`INSERT INTO schema_migrations (version, name, applied_at) VALUES ($1, $2, $3)`,
+				migration.Version, migration.Name, time.Now().UTC(),
+			)
+			return err
+		})
+		if err != nil {
+			return done, fmt.Errorf("failed to apply migration %v: %w", migration.Version, err)
+		}
+
+		done = append(done, migration)
+	}
+
+	return done, nil
+}
+
+// Down reverts the last n applied migrations, newest first, and returns the
+// ones that were reverted.
+func (m *Migrator) Down(n int) ([]Migration, error) {
+	statuses, err := m.Status()
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []Migration
+	for i := len(statuses) - 1; i >= 0; i-- {
+		if statuses[i].AppliedAt != nil {
+			applied = append(applied, statuses[i].Migration)
+		}
+	}
+	if len(applied) == 0 {
+		return nil, NothingToRollBack
+	}
+	if n < len(applied) {
+		applied = applied[:n]
+	}
+
+	var done []Migration
+	for _, migration := range applied {
+		err := m.inTx(migration.Down, func(tx *sql.Tx) error {
+			_, err := tx.Exec(`DELETE FROM schema_migrations WHERE version = $1`, migration.Version)
+			return err
+		})
+		if err != nil {
+			return done, fmt.Errorf("failed to revert migration %v: %w", migration.Version, err)
+		}
+
+		done = append(done, migration)
+	}
+
+	return done, nil
+}
+
+// applied returns the applied versions mapped to the time they were applied.
+func (m *Migrator) applied() (map[int64]time.Time, error) {
+	rows, err := m.DB.Query(`SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[int64]time.Time{}
+	for rows.Next() {
+		var version int64
+		var at time.Time
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, err
+		}
+		applied[version] = at
+	}
+
+	return applied, rows.Err()
+}
+
+// inTx runs a migration script and its bookkeeping in a single transaction.
+func (m *Migrator) inTx(script string, record func(tx *sql.Tx) error) error {
+	tx, err := m.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if strings.TrimSpace(script) != "" {
+		if _, err := tx.Exec(script); err != nil {
+			return err
+		}
+	}
+
+	if err := record(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Create writes an empty up and down migration with the next free version
+// into the directory of every driver under dir, keeping the drivers in step.
+// It returns the paths of the created files.
+func Create(dir string, name string) ([]string, error) {
+	if !namePattern.MatchString(name) {
+		return nil, InvalidName
+	}
+
+	drivers, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// Find the highest version in use by any driver.
+	var next int64 = 1
+	var driverDirs []string
+	for _, d := range drivers {
+		if !d.IsDir() {
+			continue
+		}
+		driverDirs = append(driverDirs, filepath.Join(dir, d.Name()))
+
+		entries, err := os.ReadDir(filepath.Join(dir, d.Name()))
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			match := fileNamePattern.FindStringSubmatch(entry.Name())
+			if match == nil {
+				continue
+			}
+			version, _ := strconv.ParseInt(match[1], 10, 64)
+			if version >= next {
+				next = version + 1
+			}
+		}
+	}
+
+	var created []string
+	for _, d := range driverDirs {
+		for _, direction := range []string{"up", "down"} {
+			p := filepath.Join(d, fmt.Sprintf("%04d_%v.%v.sql", next, name, direction))
+			content := fmt.Sprintf("-- %v: %v\n", name, direction)
+			if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+				return created, err
+			}
+			created = append(created, p)
+		}
+	}
+
+	return created, nil
+}
@@ -0,0 +1,228 @@
+package migrations_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/h4n-openschool/api/migrations"
+	"github.com/h4n-openschool/api/storage"
+)
+
+// newMigrator creates a [migrations.Migrator] for a new SQLite database.
+func newMigrator(t *testing.T) *migrations.Migrator {
+	t.Helper()
+
+	db, err := storage.OpenDB(storage.Config{Driver: storage.DriverSqlite, Path: filepath.Join(t.TempDir(), "openschool.db")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	m, err := migrations.NewMigrator(db, storage.DriverSqlite)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return m
+}
+
+// versions returns the versions of the given migrations, in order.
+func versions(ms []migrations.Migration) []int64 {
+	var versions []int64
+	for _, m := range ms {
+		versions = append(versions, m.Version)
+	}
+
+	return versions
+}
+
+// recorded returns the versions recorded in the version table, in order.
+func recorded(t *testing.T, m *migrations.Migrator) []int64 {
+	t.Helper()
+
+	rows, err := m.DB.Query(`SELECT version FROM schema_migrations ORDER BY version`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	var versions []int64
+	for rows.Next() {
+		var version int64
+		if err := rows.Scan(&version); err != nil {
+			t.Fatal(err)
+		}
+		versions = append(versions, version)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	return versions
+}
+
+// hasTable reports whether the database has a table with the given name.
+func hasTable(t *testing.T, m *migrations.Migrator, name string) bool {
+	t.Helper()
+
+	var count int
+	if err := m.DB.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = $1`, name).Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+
+	return count > 0
+}
+
+func TestDriversStayInStep(t *testing.T) {
+	postgres, err := migrations.Load(storage.DriverPostgres)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlite, err := migrations.Load(storage.DriverSqlite)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(postgres) != len(sqlite) {
+		t.Fatalf("postgres has %v migrations and sqlite %v", len(postgres), len(sqlite))
+	}
+	for k := range postgres {
+		if postgres[k].Version != sqlite[k].Version || postgres[k].Name != sqlite[k].Name {
+			t.Errorf("migration %v is %v_%v on postgres and %v_%v on sqlite", k, postgres[k].Version, postgres[k].Name, sqlite[k].Version, sqlite[k].Name)
+		}
+	}
+
+	if _, err := migrations.Load("unknown"); !errors.Is(err, migrations.NoMigrations) {
+		t.Errorf("Load(unknown) = %v, want %v", err, migrations.NoMigrations)
+	}
+}
+
+func TestUpDownAndStatus(t *testing.T) {
+	m := newMigrator(t)
+	all := versions(m.Migrations)
+
+	// The version table is created along with the migrator.
+	if !hasTable(t, m, "schema_migrations") {
+		t.Fatal("schema_migrations was not created")
+	}
+	if got := recorded(t, m); len(got) != 0 {
+		t.Fatalf("recorded versions %v before migrating", got)
+	}
+
+	pending, err := m.Pending()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(versions(pending), all) {
+		t.Errorf("Pending() = %v, want %v", versions(pending), all)
+	}
+
+	applied, err := m.Up()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(versions(applied), all) {
+		t.Errorf("Up() applied %v, want %v", versions(applied), all)
+	}
+	if got := recorded(t, m); !reflect.DeepEqual(got, all) {
+		t.Errorf("recorded versions %v, want %v", got, all)
+	}
+	if !hasTable(t, m, "classes") {
+		t.Error("Up() did not create the classes table")
+	}
+
+	statuses, err := m.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range statuses {
+		if s.AppliedAt == nil {
+			t.Errorf("Status() reports %v as pending after Up()", s.Version)
+		}
+	}
+
+	if applied, err := m.Up(); err != nil || len(applied) != 0 {
+		t.Errorf("second Up() = %v, %v, want nothing applied", versions(applied), err)
+	}
+
+	// Reverting goes newest first.
+	reverted, err := m.Down(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int64{all[len(all)-1], all[len(all)-2]}; !reflect.DeepEqual(versions(reverted), want) {
+		t.Errorf("Down(2) reverted %v, want %v", versions(reverted), want)
+	}
+	if got := recorded(t, m); !reflect.DeepEqual(got, all[:len(all)-2]) {
+		t.Errorf("recorded versions %v after Down(2), want %v", got, all[:len(all)-2])
+	}
+
+	statuses, err = m.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statuses) != len(all) {
+		t.Fatalf("Status() has %v migrations, want %v", len(statuses), len(all))
+	}
+	for k, s := range statuses {
+		if wantPending := k >= len(all)-2; (s.AppliedAt == nil) != wantPending {
+			t.Errorf("Status() reports %v as pending: %v, want %v", s.Version, s.AppliedAt == nil, wantPending)
+		}
+	}
+
+	if applied, err := m.Up(); err != nil || !reflect.DeepEqual(versions(applied), all[len(all)-2:]) {
+		t.Errorf("Up() after Down(2) = %v, %v, want %v", versions(applied), err, all[len(all)-2:])
+	}
+
+	// Asking for more than was applied reverts everything.
+	if reverted, err := m.Down(len(all) + 5); err != nil || len(reverted) != len(all) {
+		t.Errorf("Down(%v) reverted %v migrations, %v, want %v", len(all)+5, len(reverted), err, len(all))
+	}
+	if hasTable(t, m, "classes") {
+		t.Error("reverting every migration left the classes table behind")
+	}
+	if _, err := m.Down(1); !errors.Is(err, migrations.NothingToRollBack) {
+		t.Errorf("Down(1) with nothing applied = %v, want %v", err, migrations.NothingToRollBack)
+	}
+}
+
+func TestCreate(t *testing.T) {
+	dir := t.TempDir()
+	for _, driver := range []string{"postgres", "sqlite"} {
+		if err := os.Mkdir(filepath.Join(dir, driver), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The next version follows the highest one of any driver.
+	if err := os.WriteFile(filepath.Join(dir, "sqlite", "0007_existing.up.sql"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	created, err := migrations.Create(dir, "add_rooms")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		filepath.Join(dir, "postgres", "0008_add_rooms.up.sql"),
+		filepath.Join(dir, "postgres", "0008_add_rooms.down.sql"),
+		filepath.Join(dir, "sqlite", "0008_add_rooms.up.sql"),
+		filepath.Join(dir, "sqlite", "0008_add_rooms.down.sql"),
+	}
+	if !reflect.DeepEqual(created, want) {
+		t.Errorf("Create() = %v, want %v", created, want)
+	}
+	for _, p := range want {
+		if _, err := os.Stat(p); err != nil {
+			t.Error(err)
+		}
+	}
+
+	if _, err := migrations.Create(dir, "add-rooms"); !errors.Is(err, migrations.InvalidName) {
+		t.Errorf("Create(add-rooms) = %v, want %v", err, migrations.InvalidName)
+	}
+}
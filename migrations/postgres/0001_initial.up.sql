@@ -0,0 +1,43 @@
+CREATE TABLE classes (
+  id           TEXT PRIMARY KEY,
+  name         TEXT NOT NULL UNIQUE,
+  display_name TEXT NOT NULL,
+  description  TEXT,
+  start_date   TIMESTAMPTZ NOT NULL,
+  end_date     TIMESTAMPTZ NOT NULL,
+  created_at   TIMESTAMPTZ NOT NULL,
+  updated_at   TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE students (
+  id         TEXT PRIMARY KEY,
+  full_name  TEXT NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL,
+  updated_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE class_students (
+  class_id   TEXT NOT NULL REFERENCES classes (id) ON DELETE CASCADE,
+  student_id TEXT NOT NULL REFERENCES students (id) ON DELETE CASCADE,
+  PRIMARY KEY (class_id, student_id)
+);
+
+CREATE TABLE teachers (
+  id            TEXT PRIMARY KEY,
+  full_name     TEXT NOT NULL,
+  email         TEXT NOT NULL UNIQUE,
+  password_hash TEXT NOT NULL DEFAULT '',
+  created_at    TIMESTAMPTZ NOT NULL,
+  updated_at    TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE grades (
+  id         TEXT PRIMARY KEY,
+  class_id   TEXT NOT NULL REFERENCES classes (id) ON DELETE CASCADE,
+  student_id TEXT NOT NULL REFERENCES students (id) ON DELETE CASCADE,
+  value      INTEGER NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL,
+  updated_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX grades_class_id_idx ON grades (class_id);
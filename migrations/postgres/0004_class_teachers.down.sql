@@ -0,0 +1 @@
+DROP TABLE class_teachers;
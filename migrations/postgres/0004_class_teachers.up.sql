@@ -0,0 +1,7 @@
+CREATE TABLE class_teachers (
+  class_id   TEXT NOT NULL REFERENCES classes (id) ON DELETE CASCADE,
+  teacher_id TEXT NOT NULL REFERENCES teachers (id) ON DELETE CASCADE,
+  PRIMARY KEY (class_id, teacher_id)
+);
+
+CREATE INDEX class_teachers_teacher_id_idx ON class_teachers (teacher_id);
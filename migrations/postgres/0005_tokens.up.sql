@@ -0,0 +1,16 @@
+CREATE TABLE refresh_tokens (
+  hash       TEXT PRIMARY KEY,
+  family_id  TEXT NOT NULL,
+  user_id    TEXT NOT NULL REFERENCES teachers (id) ON DELETE CASCADE,
+  expires_at TIMESTAMPTZ NOT NULL,
+  used_at    TIMESTAMPTZ,
+  revoked_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX refresh_tokens_family_id_idx ON refresh_tokens (family_id);
+
+CREATE TABLE revoked_tokens (
+  id            TEXT PRIMARY KEY,
+  revoked_until TIMESTAMPTZ NOT NULL
+);
@@ -0,0 +1,8 @@
+CREATE TABLE one_time_tokens (
+  hash       TEXT PRIMARY KEY,
+  purpose    TEXT NOT NULL,
+  user_id    TEXT NOT NULL REFERENCES teachers (id) ON DELETE CASCADE,
+  expires_at TIMESTAMPTZ NOT NULL,
+  used_at    TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL
+);
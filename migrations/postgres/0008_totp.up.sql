@@ -0,0 +1,14 @@
+CREATE TABLE teacher_totp (
+  teacher_id   TEXT PRIMARY KEY REFERENCES teachers (id) ON DELETE CASCADE,
+  secret       TEXT NOT NULL,
+  confirmed_at TIMESTAMPTZ,
+  last_step    BIGINT NOT NULL DEFAULT 0,
+  created_at   TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE recovery_codes (
+  teacher_id TEXT NOT NULL REFERENCES teacher_totp (teacher_id) ON DELETE CASCADE,
+  hash       TEXT NOT NULL,
+  used_at    TIMESTAMPTZ,
+  PRIMARY KEY (teacher_id, hash)
+);
@@ -0,0 +1,12 @@
+CREATE TABLE api_keys (
+  id         TEXT PRIMARY KEY,
+  teacher_id TEXT NOT NULL REFERENCES teachers (id) ON DELETE CASCADE,
+  name       TEXT NOT NULL,
+  prefix     TEXT NOT NULL,
+  hash       TEXT NOT NULL UNIQUE,
+  scopes     TEXT NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL,
+  revoked_at TIMESTAMPTZ
+);
+
+CREATE INDEX api_keys_teacher_id_idx ON api_keys (teacher_id);
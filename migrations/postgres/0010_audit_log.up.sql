@@ -0,0 +1,27 @@
+CREATE TABLE audit_log (
+  sequence    BIGSERIAL PRIMARY KEY,
+  actor_id    TEXT NOT NULL,
+  operation   TEXT NOT NULL,
+  resource_id TEXT NOT NULL,
+  before_json TEXT,
+  after_json  TEXT,
+  client_ip   TEXT NOT NULL,
+  at          TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX audit_log_resource_id_idx ON audit_log (resource_id);
+CREATE INDEX audit_log_actor_id_idx ON audit_log (actor_id);
+
+-- Audit records are append-only. TRUNCATE skips row triggers, so it needs a
+-- statement trigger of its own.
+CREATE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION 'audit records are append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_no_change BEFORE UPDATE OR DELETE ON audit_log
+  FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
+
+CREATE TRIGGER audit_log_no_truncate BEFORE TRUNCATE ON audit_log
+  FOR EACH STATEMENT EXECUTE FUNCTION audit_log_append_only();
@@ -0,0 +1 @@
+DROP TABLE grade_versions;
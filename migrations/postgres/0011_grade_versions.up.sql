@@ -0,0 +1,15 @@
+CREATE TABLE grade_versions (
+  grade_id   TEXT NOT NULL REFERENCES grades (id) ON DELETE CASCADE,
+  version    INTEGER NOT NULL,
+  value      INTEGER NOT NULL,
+  changed_by TEXT NOT NULL,
+  reason     TEXT,
+  revert_of  INTEGER,
+  changed_at TIMESTAMPTZ NOT NULL,
+  PRIMARY KEY (grade_id, version)
+);
+
+-- Who set the current value of existing grades is unknown, so their history
+-- starts at it.
+INSERT INTO grade_versions (grade_id, version, value, changed_by, changed_at)
+SELECT id, 1, value, '', updated_at FROM grades;
@@ -0,0 +1,3 @@
+-- Lets the relay find whether an aggregate has an earlier message waiting for a
+-- retry, which holds back the later ones.
+CREATE INDEX outbox_events_aggregate_id_idx ON outbox_events (aggregate_id, sequence);
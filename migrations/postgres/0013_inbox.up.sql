@@ -0,0 +1,7 @@
+-- The ids of commands applied from the message bus, so redelivered commands
+-- are only applied once.
+CREATE TABLE inbox_commands (
+  id         TEXT PRIMARY KEY,
+  type       TEXT NOT NULL,
+  applied_at TIMESTAMPTZ NOT NULL
+);
@@ -0,0 +1,5 @@
+DROP TABLE grades;
+DROP TABLE teachers;
+DROP TABLE class_students;
+DROP TABLE students;
+DROP TABLE classes;
@@ -0,0 +1,11 @@
+CREATE TABLE outbox_events (
+  sequence        INTEGER PRIMARY KEY AUTOINCREMENT,
+  id              TEXT NOT NULL UNIQUE,
+  type            TEXT NOT NULL,
+  aggregate_id    TEXT NOT NULL,
+  body            TEXT NOT NULL,
+  attempts        INTEGER NOT NULL DEFAULT 0,
+  next_attempt_at TIMESTAMP NOT NULL,
+  last_error      TEXT NOT NULL DEFAULT '',
+  created_at      TIMESTAMP NOT NULL
+);
@@ -0,0 +1 @@
+ALTER TABLE teachers ADD COLUMN role TEXT NOT NULL DEFAULT 'teacher';
@@ -0,0 +1 @@
+ALTER TABLE teachers ADD COLUMN status TEXT NOT NULL DEFAULT 'active';
@@ -0,0 +1,2 @@
+DROP TABLE recovery_codes;
+DROP TABLE teacher_totp;
@@ -0,0 +1,24 @@
+CREATE TABLE audit_log (
+  sequence    INTEGER PRIMARY KEY AUTOINCREMENT,
+  actor_id    TEXT NOT NULL,
+  operation   TEXT NOT NULL,
+  resource_id TEXT NOT NULL,
+  before_json TEXT,
+  after_json  TEXT,
+  client_ip   TEXT NOT NULL,
+  at          TIMESTAMP NOT NULL
+);
+
+CREATE INDEX audit_log_resource_id_idx ON audit_log (resource_id);
+CREATE INDEX audit_log_actor_id_idx ON audit_log (actor_id);
+
+-- Audit records are append-only.
+CREATE TRIGGER audit_log_no_update BEFORE UPDATE ON audit_log
+BEGIN
+  SELECT RAISE(ABORT, 'audit records are append-only');
+END;
+
+CREATE TRIGGER audit_log_no_delete BEFORE DELETE ON audit_log
+BEGIN
+  SELECT RAISE(ABORT, 'audit records are append-only');
+END;
@@ -0,0 +1 @@
+DROP INDEX outbox_events_aggregate_id_idx;
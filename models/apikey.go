@@ -0,0 +1,82 @@
+package models
+
+import (
+	"time"
+
+	"github.com/h4n-openschool/api/api"
+)
+
+// Scope is an area of the API an [ApiKey] may read or change. See the auth
+// package for the operations each scope allows.
+type Scope string
+
+const (
+	ScopeClassesRead   Scope = "classes:read"
+	ScopeClassesWrite  Scope = "classes:write"
+	ScopeGradesRead    Scope = "grades:read"
+	ScopeGradesWrite   Scope = "grades:write"
+	ScopeStudentsRead  Scope = "students:read"
+	ScopeStudentsWrite Scope = "students:write"
+	ScopeTeachersRead  Scope = "teachers:read"
+	ScopeTeachersWrite Scope = "teachers:write"
+)
+
+// ApiKey is a long-lived credential for scripts, acting as the teacher who
+// owns it. Like tokens, only a hash of the key is stored.
+type ApiKey struct {
+	Id string
+
+	// UserId is the id of the teacher owning the key.
+	UserId string
+
+	// Name describes what the key is used for.
+	Name string
+
+	// Prefix is the start of the key, stored in the clear so keys can be told
+	// apart.
+	Prefix string
+
+	// Hash is the SHA-256 hash of the key, hex-encoded.
+	Hash string
+
+	// Scopes are the areas of the API the key may be used for.
+	Scopes []Scope
+
+	// CreatedAt is the time at which the key was created.
+	CreatedAt time.Time
+
+	// RevokedAt is the time the key was revoked, or nil if it hasn't been.
+	RevokedAt *time.Time
+}
+
+// HasScope reports whether the key was granted scope.
+func (k *ApiKey) HasScope(scope Scope) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (k *ApiKey) AsApiApiKey() api.ApiKey {
+	key := api.ApiKey{
+		Id:        k.Id,
+		Name:      k.Name,
+		Prefix:    k.Prefix,
+		Scopes:    []api.ApiKeyScope{},
+		CreatedAt: k.CreatedAt.Format(time.RFC3339),
+	}
+
+	for _, s := range k.Scopes {
+		key.Scopes = append(key.Scopes, api.ApiKeyScope(s))
+	}
+
+	if k.RevokedAt != nil {
+		revokedAt := k.RevokedAt.Format(time.RFC3339)
+		key.RevokedAt = &revokedAt
+	}
+
+	return key
+}
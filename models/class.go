@@ -19,6 +19,10 @@ type Class struct {
 
 	StudentIds []string `json:"studentIds"`
 
+	// TeacherIds are the teachers assigned to the class, who may manage its
+	// grades.
+	TeacherIds []string `json:"teacherIds"`
+
 	StartDate time.Time `json:"startDate"`
 	EndDate   time.Time `json:"endDate"`
 
@@ -26,12 +30,18 @@ type Class struct {
 }
 
 func (c *Class) AsApiClass() api.Class {
+	// Copy the slices, since c may be a loop variable that is reused before
+	// the result is encoded.
+	studentIds := append([]string{}, c.StudentIds...)
+	teacherIds := append([]string{}, c.TeacherIds...)
+
 	return api.Class{
 		Id:          c.Id,
 		Name:        c.Name,
 		DisplayName: c.DisplayName,
 		Description: *c.Description,
-		StudentIds:  &c.StudentIds,
+		StudentIds:  &studentIds,
+		TeacherIds:  &teacherIds,
 		StartDate:   c.StartDate.Format(time.RFC3339),
 		EndDate:     c.EndDate.Format(time.RFC3339),
 		CreatedAt:   c.CreatedAt.Format(time.RFC3339),
@@ -58,3 +68,20 @@ func ClassesAsApiClassList(classes []Class) api.ClassList {
 	}
 	return classList
 }
+
+// HasTeacher reports whether the teacher is assigned to the class.
+func (c *Class) HasTeacher(teacherId string) bool {
+	for _, id := range c.TeacherIds {
+		if id == teacherId {
+			return true
+		}
+	}
+
+	return false
+}
+
+// TermClosed reports whether the term of the class has closed at the given
+// time, which it does at the end date of the class.
+func (c *Class) TermClosed(at time.Time) bool {
+	return !c.EndDate.IsZero() && !at.Before(c.EndDate)
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"time"
+
+	"github.com/h4n-openschool/api/api"
+)
+
+// GradeChange describes who changed a grade, and why.
+type GradeChange struct {
+	// ChangedBy is the id of the user making the change.
+	ChangedBy string
+
+	// Reason explains the change. It is optional until the term of the
+	// grade's class has closed.
+	Reason *string
+
+	// RevertOf is the version whose value the change restores, if it is a
+	// revert.
+	RevertOf *int
+}
+
+// GradeVersion is a value a grade held. Every grade starts at version 1, and
+// every change of its value adds the next version, so the history of a grade
+// is never rewritten, even by reverts.
+type GradeVersion struct {
+	GradeId string
+	Version int
+	Value   int
+
+	// ChangedBy is the id of the user who set the value. It is empty for
+	// grades created before versions were kept.
+	ChangedBy string
+	Reason    *string
+	RevertOf  *int
+	ChangedAt time.Time
+}
+
+// NewGradeVersion returns the version recording a change of grade to its
+// current value.
+func NewGradeVersion(grade Grade, version int, change GradeChange) GradeVersion {
+	return GradeVersion{
+		GradeId:   grade.Id,
+		Version:   version,
+		Value:     grade.Value,
+		ChangedBy: change.ChangedBy,
+		Reason:    change.Reason,
+		RevertOf:  change.RevertOf,
+		ChangedAt: grade.UpdatedAt,
+	}
+}
+
+func (v *GradeVersion) AsApiGradeVersion() api.GradeVersion {
+	av := api.GradeVersion{
+		Version:   v.Version,
+		Value:     v.Value,
+		Reason:    v.Reason,
+		RevertOf:  v.RevertOf,
+		ChangedAt: v.ChangedAt.Format(time.RFC3339),
+	}
+	if v.ChangedBy != "" {
+		changedBy := v.ChangedBy
+		av.ChangedBy = &changedBy
+	}
+
+	return av
+}
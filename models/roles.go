@@ -0,0 +1,33 @@
+package models
+
+// Role decides which operations a user may perform. See the auth package for
+// the policy mapping operations to roles.
+type Role string
+
+const (
+	// RoleAdmin may perform every operation.
+	RoleAdmin Role = "admin"
+
+	// RoleTeacher manages students and grades, and reads everything else.
+	RoleTeacher Role = "teacher"
+
+	// RoleGuardian reads classes and teachers on behalf of a student.
+	RoleGuardian Role = "guardian"
+
+	// RoleStudent reads classes and teachers.
+	RoleStudent Role = "student"
+)
+
+// Roles lists every known role.
+var Roles = []Role{RoleAdmin, RoleTeacher, RoleGuardian, RoleStudent}
+
+// Valid reports whether r is one of the known roles.
+func (r Role) Valid() bool {
+	for _, role := range Roles {
+		if r == role {
+			return true
+		}
+	}
+
+	return false
+}
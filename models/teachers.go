@@ -6,10 +6,27 @@ import (
 	"github.com/h4n-openschool/api/api"
 )
 
+// TeacherStatus decides whether a teacher may log in.
+type TeacherStatus string
+
+const (
+	// TeacherPending teachers have been invited, but have not activated their
+	// account yet.
+	TeacherPending TeacherStatus = "pending"
+
+	// TeacherActive teachers may log in.
+	TeacherActive TeacherStatus = "active"
+
+	// TeacherDisabled teachers may not log in, and their tokens are refused.
+	TeacherDisabled TeacherStatus = "disabled"
+)
+
 type Teacher struct {
-	FullName     string `json:"fullName"`
-	Email        string `json:"email"`
-	PasswordHash string `json:"-"`
+	FullName     string        `json:"fullName"`
+	Email        string        `json:"email"`
+	PasswordHash string        `json:"-"`
+	Role         Role          `json:"role"`
+	Status       TeacherStatus `json:"status"`
 	BaseMetadata
 }
 
@@ -18,6 +35,8 @@ func (c *Teacher) AsApiTeacher() api.Teacher {
 		Id:        c.Id,
 		FullName:  c.FullName,
 		Email:     c.Email,
+		Role:      api.Role(c.Role),
+		Status:    api.TeacherStatus(c.Status),
 		CreatedAt: c.CreatedAt.Format(time.RFC3339),
 		UpdatedAt: c.UpdatedAt.Format(time.RFC3339),
 	}
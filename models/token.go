@@ -0,0 +1,67 @@
+package models
+
+import "time"
+
+// RefreshToken is a single-use token exchanged for a new access token. Only a
+// hash of the token is stored, so a leaked database can't be used to log in.
+type RefreshToken struct {
+	// Hash is the SHA-256 hash of the token, hex-encoded.
+	Hash string
+
+	// FamilyId identifies the session the token belongs to. Every token issued
+	// by refreshing shares the family of the token it replaced, and the access
+	// tokens of the session carry it in their `sid` claim.
+	FamilyId string
+
+	// UserId is the id of the teacher the token was issued to.
+	UserId string
+
+	// ExpiresAt is the time after which the token is no longer accepted.
+	ExpiresAt time.Time
+
+	// UsedAt is the time the token was exchanged, or nil if it hasn't been.
+	UsedAt *time.Time
+
+	// RevokedAt is the time the token's family was revoked, or nil if it
+	// hasn't been.
+	RevokedAt *time.Time
+
+	// CreatedAt is the time at which the token was issued.
+	CreatedAt time.Time
+}
+
+// Usable reports whether the token may still be exchanged at the given time.
+func (t *RefreshToken) Usable(at time.Time) bool {
+	return t.UsedAt == nil && t.RevokedAt == nil && at.Before(t.ExpiresAt)
+}
+
+// The purposes a [OneTimeToken] can be issued for. A token only works for the
+// purpose it was issued for.
+const (
+	// PurposePasswordReset tokens let a teacher choose a new password.
+	PurposePasswordReset = "password-reset"
+)
+
+// OneTimeToken is a single-use, expiring token sent to a teacher out of band,
+// such as by email, to prove they can read it. Like [RefreshToken], only a
+// hash of the token is stored.
+type OneTimeToken struct {
+	// Hash is the SHA-256 hash of the token, hex-encoded.
+	Hash string
+
+	// Purpose is what the token may be used for, one of the Purpose*
+	// constants.
+	Purpose string
+
+	// UserId is the id of the teacher the token was issued to.
+	UserId string
+
+	// ExpiresAt is the time after which the token is no longer accepted.
+	ExpiresAt time.Time
+
+	// UsedAt is the time the token was used, or nil if it hasn't been.
+	UsedAt *time.Time
+
+	// CreatedAt is the time at which the token was issued.
+	CreatedAt time.Time
+}
@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// Totp holds the two-factor authentication settings of a teacher.
+type Totp struct {
+	// UserId is the id of the teacher the settings belong to.
+	UserId string
+
+	// Secret is the base32-encoded secret shared with the teacher's
+	// authenticator app.
+	Secret string
+
+	// ConfirmedAt is the time the teacher proved their app was set up by
+	// entering a first code, or nil if they haven't yet. Two-factor
+	// authentication is only enforced once confirmed.
+	ConfirmedAt *time.Time
+
+	// LastStep is the time step of the last code used, so codes can't be
+	// replayed.
+	LastStep int64
+
+	// RecoveryCodes are the single-use codes accepted instead of a code from
+	// the app, for when it is lost.
+	RecoveryCodes []RecoveryCode
+
+	// CreatedAt is the time at which the secret was generated.
+	CreatedAt time.Time
+}
+
+// Enabled reports whether two-factor authentication is enforced.
+func (t *Totp) Enabled() bool {
+	return t.ConfirmedAt != nil
+}
+
+// RecoveryCode is a single-use code accepted instead of a TOTP code. Like
+// tokens, only a hash of the code is stored.
+type RecoveryCode struct {
+	// Hash is the SHA-256 hash of the code, hex-encoded.
+	Hash string
+
+	// UsedAt is the time the code was used, or nil if it hasn't been.
+	UsedAt *time.Time
+}
@@ -5,31 +5,30 @@ import (
 
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/h4n-openschool/api/utils"
+	"github.com/lucsky/cuid"
 )
 
 type User[T interface{}] struct {
 	BaseMetadata
 	PersonId string
 	Person   *T
+	Role     Role
 }
 
-func (u *User[T]) Jwt() (string, error) {
-  claims := utils.UserClaims{}
-  claims.RegisteredClaims = jwt.RegisteredClaims{
-    Issuer:    `osapi`,
-    Subject:   u.PersonId,
-    Audience:  []string{"client", "server"},
-    ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
-    IssuedAt:  jwt.NewNumericDate(time.Now()),
-    NotBefore: jwt.NewNumericDate(time.Now()),
-  }
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-
-	tokenStr, err := token.SignedString([]byte(utils.JwtSigningKey))
-	if err != nil {
-		return "", err
+// Jwt issues an access token for the user, signed with the current key of
+// keys. It expires after ttl, and belongs to the session identified by
+// sessionId so it can be revoked along with the session's refresh tokens.
+func (u *User[T]) Jwt(keys *utils.KeySet, ttl time.Duration, sessionId string) (string, error) {
+	claims := utils.UserClaims{Role: string(u.Role), SessionId: sessionId}
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		ID:        cuid.New(),
+		Issuer:    `osapi`,
+		Subject:   u.PersonId,
+		Audience:  []string{"client", utils.AudienceServer},
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
+		NotBefore: jwt.NewNumericDate(time.Now()),
 	}
 
-	return tokenStr, nil
+	return keys.Sign(claims)
 }
@@ -0,0 +1,86 @@
+// Package notify delivers messages, such as password reset links, to users out
+// of band. Real delivery is left to implementations of [Notifier]; the ones in
+// this package only record messages, for development and testing.
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Message is a single notification for a user.
+type Message struct {
+	// To is the email address of the recipient.
+	To string `json:"to"`
+
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+
+	// SentAt is the time the message was handed to the notifier.
+	SentAt time.Time `json:"sentAt"`
+}
+
+// Notifier delivers messages to users.
+type Notifier interface {
+	// Notify delivers a message. It should return once the message has been
+	// handed off, rather than waiting for the recipient to read it.
+	Notify(ctx context.Context, m Message) error
+}
+
+// LogNotifier is a [Notifier] that writes every message to the log instead of
+// delivering it.
+type LogNotifier struct {
+	Logger *zap.Logger
+}
+
+// NewLogNotifier creates a new instance of [LogNotifier]
+func NewLogNotifier(logger *zap.Logger) *LogNotifier {
+	return &LogNotifier{Logger: logger.Named("notify")}
+}
+
+func (n *LogNotifier) Notify(ctx context.Context, m Message) error {
+	n.Logger.Sugar().Infow("notification", "to", m.To, "subject", m.Subject, "body", m.Body)
+	return nil
+}
+
+// FileNotifier is a [Notifier] that appends every message to a file as a line
+// of JSON instead of delivering it. It is safe for concurrent use.
+type FileNotifier struct {
+	// Path is the file messages are appended to. It is created if missing.
+	Path string
+
+	mu sync.Mutex
+}
+
+// NewFileNotifier creates a new instance of [FileNotifier]
+func NewFileNotifier(path string) *FileNotifier {
+	return &FileNotifier{Path: path}
+}
+
+func (n *FileNotifier) Notify(ctx context.Context, m Message) error {
+	line, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	// The file may hold secrets such as reset tokens, so keep it private.
+	f, err := os.OpenFile(n.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	return f.Close()
+}
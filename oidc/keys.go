@@ -0,0 +1,185 @@
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"net/http"
+	"time"
+)
+
+// supportedAlgorithms are the ID token signing algorithms accepted. HMAC is
+// deliberately missing: the client secret must not be able to forge tokens.
+var supportedAlgorithms = []string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+// minRefreshInterval is how often the provider's keys may be fetched again
+// when a token is signed with an unknown key, as happens after a rotation.
+const minRefreshInterval = time.Minute
+
+// keySet holds the provider's public keys by id.
+type keySet struct {
+	keys      map[string]jwk
+	fetchedAt time.Time
+}
+
+// jwk is a single JSON Web Key, with the fields of RSA, EC and OKP keys.
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+
+	// public is the parsed key.
+	public any
+}
+
+// key returns the public key a token was signed with, fetching the
+// provider's keys if it isn't known yet.
+func (p *Provider) key(ctx context.Context, d *discovery, kid string, alg string) (any, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	k, ok := p.findKey(kid)
+	if !ok && (p.keys == nil || time.Since(p.keys.fetchedAt) > minRefreshInterval) {
+		if err := p.fetchKeys(ctx, d); err != nil {
+			return nil, err
+		}
+		k, ok = p.findKey(kid)
+	}
+	if !ok {
+		return nil, fmt.Errorf("unknown key %q", kid)
+	}
+
+	if k.Alg != "" && k.Alg != alg {
+		return nil, fmt.Errorf("key %q is not for %v", kid, alg)
+	}
+
+	return k.public, nil
+}
+
+// findKey returns the key with the given id. Tokens without an id may only be
+// used while the provider has a single key. The caller must hold the lock.
+func (p *Provider) findKey(kid string) (jwk, bool) {
+	if p.keys == nil {
+		return jwk{}, false
+	}
+
+	if kid == "" && len(p.keys.keys) == 1 {
+		for _, k := range p.keys.keys {
+			return k, true
+		}
+	}
+
+	k, ok := p.keys.keys[kid]
+	return k, ok
+}
+
+// fetchKeys replaces the known keys with the provider's current ones. Keys of
+// unsupported types, or not meant for signatures, are skipped. The caller
+// must hold the lock.
+func (p *Provider) fetchKeys(ctx context.Context, d *discovery) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.JwksUri, nil)
+	if err != nil {
+		return err
+	}
+
+	var body struct {
+		Keys []jwk `json:"keys"`
+	}
+	status, err := p.do(req, &body)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("%w: jwks_uri answered %v", ProviderError, status)
+	}
+
+	keys := &keySet{keys: map[string]jwk{}, fetchedAt: time.Now()}
+	for _, k := range body.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		if k.public, err = k.parse(); err == nil {
+			keys.keys[k.Kid] = k
+		}
+	}
+
+	p.keys = keys
+	return nil
+}
+
+// parse decodes the public key held by k.
+func (k *jwk) parse() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid Ed25519 key")
+		}
+		return ed25519.PublicKey(x), nil
+	}
+
+	return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+}
+
+// decodeInt decodes a base64url-encoded, big-endian integer.
+func decodeInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return nil, fmt.Errorf("empty integer")
+	}
+
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,252 @@
+// Package oidctest provides a mock OpenID Connect provider for tests of the
+// login flow. It serves discovery, its keys and a token endpoint checking PKCE,
+// like [httptest.Server] does for plain HTTP.
+package oidctest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// keyId is the id of the provider's only signing key.
+const keyId = "test"
+
+// Server is a mock identity provider. The application must be registered with
+// it as ClientId and ClientSecret.
+type Server struct {
+	*httptest.Server
+
+	ClientId     string
+	ClientSecret string
+
+	// Key signs ID tokens with RS256, and is published at the JWKS endpoint.
+	Key *rsa.PrivateKey
+
+	// Claims, if set, changes the claims of ID tokens before they are signed,
+	// for example to issue tokens the application must reject.
+	Claims func(claims jwt.MapClaims)
+
+	// Sign, if set, signs ID tokens instead of Key.
+	Sign func(claims jwt.MapClaims) (string, error)
+
+	mu sync.Mutex
+
+	// codes are the authorizations waiting to be exchanged, by code.
+	codes map[string]authorization
+}
+
+// authorization is a login the provider redirected back to the application.
+type authorization struct {
+	redirectUri string
+	challenge   string
+	nonce       string
+	email       string
+}
+
+// NewServer starts a mock identity provider, which is closed at the end of
+// the test.
+func NewServer(t *testing.T, clientId string, clientSecret string) *Server {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &Server{
+		ClientId:     clientId,
+		ClientSecret: clientSecret,
+		Key:          key,
+		codes:        map[string]authorization{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", s.discovery)
+	mux.HandleFunc("/authorize", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "use Server.Authorize to log in", http.StatusNotImplemented)
+	})
+	mux.HandleFunc("/token", s.token)
+	mux.HandleFunc("/jwks", s.jwks)
+
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// Issuer returns the issuer identifier of the provider.
+func (s *Server) Issuer() string {
+	return s.URL
+}
+
+// Authorize logs in the user with the given email at an authorization URL
+// the application sent them to, and returns the callback URL the provider
+// redirects them back to.
+func (s *Server) Authorize(authUrl string, email string) (*url.URL, error) {
+	u, err := url.Parse(authUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	q := u.Query()
+	if q.Get("client_id") != s.ClientId {
+		return nil, fmt.Errorf("unknown client %q", q.Get("client_id"))
+	}
+	if q.Get("response_type") != "code" {
+		return nil, fmt.Errorf("unsupported response type %q", q.Get("response_type"))
+	}
+	if q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") == "" {
+		return nil, errors.New("no S256 PKCE challenge")
+	}
+
+	code, err := randomString()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.codes[code] = authorization{
+		redirectUri: q.Get("redirect_uri"),
+		challenge:   q.Get("code_challenge"),
+		nonce:       q.Get("nonce"),
+		email:       email,
+	}
+	s.mu.Unlock()
+
+	callback, err := url.Parse(q.Get("redirect_uri"))
+	if err != nil {
+		return nil, err
+	}
+
+	values := callback.Query()
+	values.Set("code", code)
+	values.Set("state", q.Get("state"))
+	callback.RawQuery = values.Encode()
+
+	return callback, nil
+}
+
+func (s *Server) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJson(w, http.StatusOK, map[string]string{
+		"issuer":                 s.Issuer(),
+		"authorization_endpoint": s.URL + "/authorize",
+		"token_endpoint":         s.URL + "/token",
+		"jwks_uri":               s.URL + "/jwks",
+	})
+}
+
+func (s *Server) jwks(w http.ResponseWriter, r *http.Request) {
+	pub := s.Key.PublicKey
+	writeJson(w, http.StatusOK, map[string]any{
+		"keys": []map[string]string{{
+			"kid": keyId,
+			"kty": "RSA",
+			"alg": "RS256",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}},
+	})
+}
+
+// token exchanges codes for ID tokens. Each code works once, and only with
+// the verifier of the challenge it was issued for.
+func (s *Server) token(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	clientId, clientSecret, ok := r.BasicAuth()
+	if ok {
+		clientId, _ = url.QueryUnescape(clientId)
+		clientSecret, _ = url.QueryUnescape(clientSecret)
+	}
+	if !ok || clientId != s.ClientId || clientSecret != s.ClientSecret {
+		writeJson(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "authorization_code" {
+		writeJson(w, http.StatusBadRequest, map[string]string{"error": "unsupported_grant_type"})
+		return
+	}
+
+	s.mu.Lock()
+	a, ok := s.codes[r.PostForm.Get("code")]
+	delete(s.codes, r.PostForm.Get("code"))
+	s.mu.Unlock()
+
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if !ok || a.redirectUri != r.PostForm.Get("redirect_uri") || a.challenge != base64.RawURLEncoding.EncodeToString(sum[:]) {
+		writeJson(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":            s.Issuer(),
+		"sub":            "subject-" + a.email,
+		"aud":            s.ClientId,
+		"iat":            now.Unix(),
+		"exp":            now.Add(time.Minute).Unix(),
+		"nonce":          a.nonce,
+		"email":          a.email,
+		"email_verified": true,
+	}
+	if s.Claims != nil {
+		s.Claims(claims)
+	}
+
+	idToken, err := s.sign(claims)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeJson(w, http.StatusOK, map[string]string{
+		"access_token": "access",
+		"token_type":   "Bearer",
+		"id_token":     idToken,
+	})
+}
+
+func (s *Server) sign(claims jwt.MapClaims) (string, error) {
+	if s.Sign != nil {
+		return s.Sign(claims)
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = keyId
+
+	return token.SignedString(s.Key)
+}
+
+func writeJson(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func randomString() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
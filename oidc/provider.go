@@ -0,0 +1,282 @@
+// Package oidc logs users in with an external OpenID Connect identity
+// provider, using the authorization code flow with PKCE. It only implements
+// what that flow needs: discovery, the token exchange and ID token checks.
+package oidc
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+var (
+	// ProviderError is wrapped by errors caused by the identity provider
+	// failing or misbehaving, rather than by the user.
+	ProviderError = errors.New("identity provider error")
+
+	// InvalidIdToken is wrapped by errors about ID tokens that must not be
+	// trusted.
+	InvalidIdToken = errors.New("invalid ID token")
+
+	// InvalidGrant is returned when the provider refuses the code, because it
+	// expired or was used already.
+	InvalidGrant = errors.New("invalid or expired authorization code")
+)
+
+// requestTimeout bounds every request made to the identity provider.
+const requestTimeout = 10 * time.Second
+
+// Config describes the identity provider and how the application is
+// registered with it.
+type Config struct {
+	// Issuer is the URL of the provider. Its configuration is discovered at
+	// `/.well-known/openid-configuration` below it.
+	Issuer string
+
+	ClientId     string
+	ClientSecret string
+
+	// RedirectUrl is where the provider sends users back to, which must be
+	// the callback endpoint. It must be registered with the provider.
+	RedirectUrl string
+
+	// Scopes are requested in addition to `openid`.
+	Scopes []string
+}
+
+// Identity is what the provider asserts about a user who logged in.
+type Identity struct {
+	// Subject is the provider's id of the user.
+	Subject string
+
+	Email string
+
+	// EmailVerified is whether the provider checked the user owns the email,
+	// or nil if it didn't say.
+	EmailVerified *bool
+}
+
+// Provider talks to an OpenID Connect provider. Its configuration and keys
+// are fetched on first use, so the provider doesn't need to be up when the
+// application starts. It is safe for concurrent use.
+type Provider struct {
+	Config Config
+	Client *http.Client
+
+	mu        sync.Mutex
+	discovery *discovery
+	keys      *keySet
+}
+
+// discovery holds the fields of the provider's configuration document the
+// flow needs.
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JwksUri               string `json:"jwks_uri"`
+}
+
+// NewProvider creates a new instance of [Provider]
+func NewProvider(cfg Config) *Provider {
+	return &Provider{
+		Config: cfg,
+		Client: &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// AuthCodeURL returns where to send a user to log in. The state is echoed
+// back to the callback, the nonce ends up in the ID token, and the verifier
+// is kept secret until the code is exchanged, as PKCE requires.
+func (p *Provider) AuthCodeURL(ctx context.Context, state string, nonce string, verifier string) (string, error) {
+	d, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	q := url.Values{}
+	q.Set("response_type", "code")
+	q.Set("client_id", p.Config.ClientId)
+	q.Set("redirect_uri", p.Config.RedirectUrl)
+	q.Set("scope", strings.Join(append([]string{"openid"}, p.Config.Scopes...), " "))
+	q.Set("state", state)
+	q.Set("nonce", nonce)
+	q.Set("code_challenge", Challenge(verifier))
+	q.Set("code_challenge_method", "S256")
+
+	sep := "?"
+	if strings.Contains(d.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+
+	return d.AuthorizationEndpoint + sep + q.Encode(), nil
+}
+
+// Exchange trades the code the provider sent to the callback for an ID token,
+// and returns the identity it asserts once it has been checked.
+func (p *Provider) Exchange(ctx context.Context, code string, verifier string, nonce string) (*Identity, error) {
+	d, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.Config.RedirectUrl)
+	form.Set("client_id", p.Config.ClientId)
+	form.Set("code_verifier", verifier)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.Config.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.Config.ClientId), url.QueryEscape(p.Config.ClientSecret))
+	}
+
+	var token struct {
+		IdToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	status, err := p.do(req, &token)
+	if err != nil {
+		return nil, err
+	}
+	if status == http.StatusBadRequest && token.Error == "invalid_grant" {
+		return nil, InvalidGrant
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("%w: token endpoint answered %v: %v %v", ProviderError, status, token.Error, token.ErrorDescription)
+	}
+	if token.IdToken == "" {
+		return nil, fmt.Errorf("%w: token endpoint returned no id_token", ProviderError)
+	}
+
+	return p.verify(ctx, d, token.IdToken, nonce)
+}
+
+// idClaims are the claims of ID tokens the flow relies on.
+type idClaims struct {
+	jwt.RegisteredClaims
+
+	Nonce         string `json:"nonce"`
+	Email         string `json:"email"`
+	EmailVerified *bool  `json:"email_verified"`
+}
+
+// verify checks the signature, issuer, audience, lifetime and nonce of an ID
+// token.
+func (p *Provider) verify(ctx context.Context, d *discovery, idToken string, nonce string) (*Identity, error) {
+	parser := jwt.Parser{ValidMethods: supportedAlgorithms}
+
+	claims := idClaims{}
+	_, err := parser.ParseWithClaims(idToken, &claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		return p.key(ctx, d, kid, t.Method.Alg())
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", InvalidIdToken, err)
+	}
+
+	if claims.Issuer != d.Issuer {
+		return nil, fmt.Errorf("%w: issued by %q", InvalidIdToken, claims.Issuer)
+	}
+	if !claims.VerifyAudience(p.Config.ClientId, true) {
+		return nil, fmt.Errorf("%w: issued for another client", InvalidIdToken)
+	}
+	if claims.ExpiresAt == nil {
+		return nil, fmt.Errorf("%w: no expiry", InvalidIdToken)
+	}
+	if subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(nonce)) != 1 {
+		return nil, fmt.Errorf("%w: nonce mismatch", InvalidIdToken)
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: no subject", InvalidIdToken)
+	}
+
+	return &Identity{
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+	}, nil
+}
+
+// discover returns the provider's configuration, fetching it on first use.
+// A failed fetch is retried on the next call.
+func (p *Provider) discover(ctx context.Context) (*discovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(p.Config.Issuer, "/")+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &discovery{}
+	status, err := p.do(req, d)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("%w: discovery answered %v", ProviderError, status)
+	}
+
+	// The issuer must match exactly, or ID tokens could be checked against
+	// another provider's configuration.
+	if d.Issuer != p.Config.Issuer {
+		return nil, fmt.Errorf("%w: discovered issuer %q does not match %q", ProviderError, d.Issuer, p.Config.Issuer)
+	}
+	if d.AuthorizationEndpoint == "" || d.TokenEndpoint == "" || d.JwksUri == "" {
+		return nil, fmt.Errorf("%w: discovery document is incomplete", ProviderError)
+	}
+
+	p.discovery = d
+	return d, nil
+}
+
+// do sends a request to the provider and decodes its JSON response into v,
+// whatever the status.
+func (p *Provider) do(req *http.Request, v any) (int, error) {
+	res, err := p.Client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ProviderError, err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, 1<<20))
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ProviderError, err)
+	}
+
+	if err := json.Unmarshal(body, v); err != nil && res.StatusCode == http.StatusOK {
+		return 0, fmt.Errorf("%w: invalid response from %v: %v", ProviderError, req.URL, err)
+	}
+
+	return res.StatusCode, nil
+}
+
+// Challenge returns the S256 PKCE challenge of a verifier.
+func Challenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
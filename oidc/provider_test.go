@@ -0,0 +1,161 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/h4n-openschool/api/oidc/oidctest"
+)
+
+const (
+	testClientId     = "osapi"
+	testClientSecret = "secret"
+	testRedirectUrl  = "https://osapi.example/v1/auth/oidc/callback"
+)
+
+// login runs the flow against idp up to the token exchange, and returns the
+// code the provider sent back.
+func login(t *testing.T, p *Provider, idp *oidctest.Server, state string, nonce string, verifier string) string {
+	t.Helper()
+
+	authUrl, err := p.AuthCodeURL(context.Background(), state, nonce, verifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	callback, err := idp.Authorize(authUrl, "jane.doe@school.edu")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := callback.Query().Get("state"); got != state {
+		t.Fatalf("state = %q, want %q", got, state)
+	}
+
+	return callback.Query().Get("code")
+}
+
+func newTestProvider(t *testing.T) (*Provider, *oidctest.Server) {
+	idp := oidctest.NewServer(t, testClientId, testClientSecret)
+
+	return NewProvider(Config{
+		Issuer:       idp.Issuer(),
+		ClientId:     testClientId,
+		ClientSecret: testClientSecret,
+		RedirectUrl:  testRedirectUrl,
+	}), idp
+}
+
+func TestExchange(t *testing.T) {
+	p, idp := newTestProvider(t)
+	code := login(t, p, idp, "state", "nonce", "verifier")
+
+	identity, err := p.Exchange(context.Background(), code, "verifier", "nonce")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if identity.Email != "jane.doe@school.edu" {
+		t.Errorf("Email = %q, want %q", identity.Email, "jane.doe@school.edu")
+	}
+	if identity.Subject == "" {
+		t.Error("Subject is empty")
+	}
+	if identity.EmailVerified == nil || !*identity.EmailVerified {
+		t.Errorf("EmailVerified = %v, want true", identity.EmailVerified)
+	}
+
+	// Codes are single-use.
+	if _, err := p.Exchange(context.Background(), code, "verifier", "nonce"); !errors.Is(err, InvalidGrant) {
+		t.Errorf("exchanging a code twice returned %v, want %v", err, InvalidGrant)
+	}
+}
+
+func TestExchangeNeedsVerifier(t *testing.T) {
+	p, idp := newTestProvider(t)
+	code := login(t, p, idp, "state", "nonce", "verifier")
+
+	if _, err := p.Exchange(context.Background(), code, "another verifier", "nonce"); !errors.Is(err, InvalidGrant) {
+		t.Errorf("Exchange with the wrong verifier returned %v, want %v", err, InvalidGrant)
+	}
+}
+
+func TestExchangeRejectsInvalidIdTokens(t *testing.T) {
+	tests := []struct {
+		name   string
+		nonce  string
+		claims func(claims jwt.MapClaims)
+		sign   func(idp *oidctest.Server) func(claims jwt.MapClaims) (string, error)
+	}{
+		{
+			name:  "nonce mismatch",
+			nonce: "another nonce",
+		},
+		{
+			name:   "wrong issuer",
+			claims: func(claims jwt.MapClaims) { claims["iss"] = "https://evil.example" },
+		},
+		{
+			name:   "wrong audience",
+			claims: func(claims jwt.MapClaims) { claims["aud"] = "another-client" },
+		},
+		{
+			name:   "expired",
+			claims: func(claims jwt.MapClaims) { claims["exp"] = 1 },
+		},
+		{
+			name:   "no expiry",
+			claims: func(claims jwt.MapClaims) { delete(claims, "exp") },
+		},
+		{
+			// Anyone knowing the client secret could forge such tokens.
+			name: "signed with HS256",
+			sign: func(idp *oidctest.Server) func(claims jwt.MapClaims) (string, error) {
+				return func(claims jwt.MapClaims) (string, error) {
+					return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(idp.ClientSecret))
+				}
+			},
+		},
+		{
+			name: "signed with an unknown key",
+			sign: func(idp *oidctest.Server) func(claims jwt.MapClaims) (string, error) {
+				other := oidctest.NewServer(t, testClientId, testClientSecret)
+				return func(claims jwt.MapClaims) (string, error) {
+					token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+					token.Header["kid"] = "test"
+					return token.SignedString(other.Key)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, idp := newTestProvider(t)
+			idp.Claims = tt.claims
+			if tt.sign != nil {
+				idp.Sign = tt.sign(idp)
+			}
+
+			nonce := tt.nonce
+			if nonce == "" {
+				nonce = "nonce"
+			}
+
+			code := login(t, p, idp, "state", "nonce", "verifier")
+			if _, err := p.Exchange(context.Background(), code, "verifier", nonce); !errors.Is(err, InvalidIdToken) {
+				t.Errorf("Exchange returned %v, want %v", err, InvalidIdToken)
+			}
+		})
+	}
+}
+
+func TestDiscoveryChecksIssuer(t *testing.T) {
+	p, idp := newTestProvider(t)
+	p.Config.Issuer = idp.Issuer() + "/"
+
+	if _, err := p.AuthCodeURL(context.Background(), "state", "nonce", "verifier"); !errors.Is(err, ProviderError) {
+		t.Errorf("AuthCodeURL returned %v, want %v", err, ProviderError)
+	}
+}
@@ -0,0 +1,133 @@
+package outbox
+
+import (
+	"sync"
+	"time"
+
+	"github.com/h4n-openschool/api/events"
+)
+
+// InMemoryStore implements the [Store] interface using an in-memory slice of
+// [Message] items. It is safe for concurrent use.
+type InMemoryStore struct {
+	mu sync.Mutex
+
+	// items are the undelivered messages, ordered by sequence.
+	items []Message
+
+	// sequence is the sequence number of the last added message.
+	sequence int64
+}
+
+// NewInMemoryStore creates a new instance of [InMemoryStore]
+func NewInMemoryStore() *InMemoryStore {
+	return &InMemoryStore{}
+}
+
+// NewInMemoryStoreFrom creates a new instance of [InMemoryStore] holding the
+// given messages, for example ones restored from a snapshot.
+func NewInMemoryStoreFrom(messages []Message) *InMemoryStore {
+	s := &InMemoryStore{items: append([]Message(nil), messages...)}
+	for _, m := range messages {
+		if m.Sequence > s.sequence {
+			s.sequence = m.Sequence
+		}
+	}
+
+	return s
+}
+
+func (s *InMemoryStore) Add(e events.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sequence++
+	s.items = append(s.items, Message{
+		Sequence:      s.sequence,
+		Event:         e,
+		NextAttemptAt: time.Now(),
+	})
+
+	return nil
+}
+
+func (s *InMemoryStore) Pending(now time.Time, limit int) ([]Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var messages []Message
+	waiting := map[string]bool{}
+	for _, m := range s.items {
+		if len(messages) == limit {
+			break
+		}
+
+		if m.NextAttemptAt.After(now) {
+			waiting[m.Event.AggregateId] = true
+			continue
+		}
+		if waiting[m.Event.AggregateId] {
+			continue
+		}
+
+		messages = append(messages, m)
+	}
+
+	return messages, nil
+}
+
+func (s *InMemoryStore) All() ([]Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Message(nil), s.items...), nil
+}
+
+func (s *InMemoryStore) MarkSent(sequence int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for k, m := range s.items {
+		if m.Sequence == sequence {
+			s.items = append(s.items[:k], s.items[k+1:]...)
+			break
+		}
+	}
+
+	return nil
+}
+
+func (s *InMemoryStore) MarkFailed(sequence int64, nextAttemptAt time.Time, reason string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for k, m := range s.items {
+		if m.Sequence == sequence {
+			s.items[k].Attempts++
+			s.items[k].NextAttemptAt = nextAttemptAt
+			s.items[k].LastError = reason
+			break
+		}
+	}
+
+	return nil
+}
+
+func (s *InMemoryStore) Stats() (Stats, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := Stats{Pending: len(s.items)}
+	for _, m := range s.items {
+		if m.Attempts > 0 {
+			stats.Failing++
+		}
+	}
+
+	if len(s.items) > 0 {
+		oldest := s.items[0].Event.Timestamp
+		stats.Oldest = &oldest
+	}
+
+	return stats, nil
+}
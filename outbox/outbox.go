@@ -0,0 +1,102 @@
+// Package outbox keeps domain events in the data store until they have been
+// delivered to the message bus, so that events survive broker outages and
+// restarts.
+//
+// Handlers publish through an outbox [Publisher] in the transaction of the
+// repository write an event describes, see [PublisherIn], so the event is
+// recorded if and only if the write is. A [Relay] then
+// delivers recorded events in order, retrying with backoff until the broker
+// accepts them.
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/h4n-openschool/api/events"
+)
+
+// Message is an event waiting in the outbox.
+type Message struct {
+	// Sequence orders messages in the order they were added.
+	Sequence int64
+
+	// Event is the event to deliver.
+	Event events.Event
+
+	// Attempts is the number of failed delivery attempts so far.
+	Attempts int
+
+	// NextAttemptAt is the earliest time the message may be retried.
+	NextAttemptAt time.Time
+
+	// LastError describes why the last delivery attempt failed.
+	LastError string
+}
+
+// Stats summarises the content of an outbox.
+type Stats struct {
+	// Pending is the number of messages waiting to be delivered.
+	Pending int
+
+	// Failing is the number of pending messages with at least one failed
+	// delivery attempt.
+	Failing int
+
+	// Oldest is the time the oldest pending message was added, if any.
+	Oldest *time.Time
+}
+
+// Store persists outbox messages.
+type Store interface {
+	// Add records an event for delivery.
+	Add(e events.Event) error
+
+	// Pending returns up to limit undelivered messages due at now, ordered by
+	// sequence. Messages are left out while an earlier message about the same
+	// aggregate is waiting for a retry, so they are delivered in order.
+	Pending(now time.Time, limit int) ([]Message, error)
+
+	// MarkSent removes a delivered message from the outbox.
+	MarkSent(sequence int64) error
+
+	// MarkFailed records a failed delivery attempt and when to retry it.
+	MarkFailed(sequence int64, nextAttemptAt time.Time, reason string) error
+
+	// Stats summarises the messages waiting in the outbox.
+	Stats() (Stats, error)
+
+	// All returns every message, due or not, ordered by sequence.
+	All() ([]Message, error)
+}
+
+// Publisher implements the [events.Publisher] interface by adding events to
+// an outbox [Store] instead of sending them straight to the message bus.
+type Publisher struct {
+	Store Store
+}
+
+// NewPublisher creates a new instance of [Publisher]
+func NewPublisher(store Store) *Publisher {
+	return &Publisher{Store: store}
+}
+
+// PublisherIn returns a publisher adding events to store when p is a
+// [Publisher], so events can be added in the transaction of the write they
+// describe. Other publishers, which don't record events, are returned as they
+// are.
+func PublisherIn(p events.Publisher, store Store) events.Publisher {
+	if _, ok := p.(*Publisher); ok {
+		return NewPublisher(store)
+	}
+
+	return p
+}
+
+func (p *Publisher) Publish(ctx context.Context, e events.Event) error {
+	return p.Store.Add(e)
+}
+
+func (p *Publisher) Close() error {
+	return nil
+}
@@ -0,0 +1,141 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/h4n-openschool/api/events"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultInterval is how often the relay looks for messages to deliver.
+	DefaultInterval = time.Second
+
+	// DefaultBatchSize is how many messages the relay reads at a time.
+	DefaultBatchSize = 100
+
+	// DefaultMinBackoff is the delay before the first retry of a message.
+	DefaultMinBackoff = time.Second
+
+	// DefaultMaxBackoff caps the delay between retries of a message.
+	DefaultMaxBackoff = 5 * time.Minute
+)
+
+// Relay delivers the messages of an outbox [Store] to the message bus.
+//
+// Messages about the same aggregate are delivered in the order they were
+// added: once a message fails, later messages for its aggregate wait until it
+// has been delivered. Messages about other aggregates are not held up, neither
+// while it waits for its retry nor by its failure.
+type Relay struct {
+	Store     Store
+	Publisher events.Publisher
+	Logger    *zap.Logger
+
+	Interval   time.Duration
+	BatchSize  int
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// NewRelay creates a new instance of [Relay] with the default settings.
+func NewRelay(store Store, publisher events.Publisher, logger *zap.Logger) *Relay {
+	return &Relay{
+		Store:      store,
+		Publisher:  publisher,
+		Logger:     logger.Named("outbox"),
+		Interval:   DefaultInterval,
+		BatchSize:  DefaultBatchSize,
+		MinBackoff: DefaultMinBackoff,
+		MaxBackoff: DefaultMaxBackoff,
+	}
+}
+
+// Run delivers messages every interval until ctx is cancelled, then makes one
+// last attempt to deliver whatever is due before returning.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			r.Deliver(ctx)
+		case <-ctx.Done():
+			// The relay's own context is done, but the broker may still be
+			// reachable, so give the final delivery a short deadline of its own.
+			final, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			r.Deliver(final)
+			cancel()
+			return
+		}
+	}
+}
+
+// Deliver sends every message that is due, and returns how many were sent.
+func (r *Relay) Deliver(ctx context.Context) int {
+	now := time.Now()
+	messages, err := r.Store.Pending(now, r.BatchSize)
+	if err != nil {
+		r.Logger.Sugar().Errorw("failed to read outbox", "error", err)
+		return 0
+	}
+
+	blocked := map[string]bool{}
+	sent := 0
+
+	for _, m := range messages {
+		aggregate := m.Event.AggregateId
+		if blocked[aggregate] {
+			continue
+		}
+
+		if ctx.Err() != nil {
+			return sent
+		}
+
+		if err := r.Publisher.Publish(ctx, m.Event); err != nil {
+			next := now.Add(r.backoff(m.Attempts + 1))
+			if err := r.Store.MarkFailed(m.Sequence, next, err.Error()); err != nil {
+				r.Logger.Sugar().Errorw("failed to record delivery failure", "sequence", m.Sequence, "error", err)
+			}
+
+			r.Logger.Sugar().Warnw("failed to deliver event, will retry",
+				"type", m.Event.Type, "aggregateId", aggregate, "attempts", m.Attempts+1, "retryAt", next, "error", err)
+
+			// Later messages about the aggregate wait for this one, but the
+			// others may still go out, for example when the broker rejected
+			// only this event.
+			blocked[aggregate] = true
+			continue
+		}
+
+		if err := r.Store.MarkSent(m.Sequence); err != nil {
+			// The event went out but is still in the outbox, so it will be sent
+			// again. Consumers drop duplicates by event id.
+			r.Logger.Sugar().Errorw("failed to remove delivered event", "sequence", m.Sequence, "error", err)
+			blocked[aggregate] = true
+			continue
+		}
+
+		sent++
+	}
+
+	return sent
+}
+
+// backoff returns the delay before the given attempt, doubling from
+// MinBackoff up to MaxBackoff.
+func (r *Relay) backoff(attempt int) time.Duration {
+	d := r.MinBackoff
+	for i := 1; i < attempt && d < r.MaxBackoff; i++ {
+		d *= 2
+	}
+
+	if d > r.MaxBackoff {
+		d = r.MaxBackoff
+	}
+
+	return d
+}
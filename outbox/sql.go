@@ -0,0 +1,120 @@
+package outbox
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/h4n-openschool/api/events"
+	"github.com/h4n-openschool/api/utils"
+)
+
+// SqlStore implements the [Store] interface on top of the `outbox_events`
+// table. Queries are written to run on both PostgreSQL and SQLite.
+type SqlStore struct {
+	// DB is the database connection, or the transaction, used for every query.
+	DB utils.SqlConn
+}
+
+// NewSqlStore creates a new instance of [SqlStore]
+func NewSqlStore(db utils.SqlConn) *SqlStore {
+	return &SqlStore{DB: db}
+}
+
+func (s *SqlStore) Add(e events.Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	_, err = s.DB.Exec(
+		`INSERT INTO outbox_events (id, type, aggregate_id, body, next_attempt_at, created_at) VALUES ($1, $2, $3, $4, $5, $6)`,
+		e.Id, e.Type, e.AggregateId, string(body), now, now,
+	)
+
+	return err
+}
+
+func (s *SqlStore) Pending(now time.Time, limit int) ([]Message, error) {
+	rows, err := s.DB.Query(
+		`SELECT sequence, body, attempts, next_attempt_at, last_error FROM outbox_events o
+		WHERE next_attempt_at <= $1 AND NOT EXISTS (
+			SELECT 1 FROM outbox_events e
+			WHERE e.aggregate_id = o.aggregate_id AND e.sequence < o.sequence AND e.next_attempt_at > $1
+		)
+		ORDER BY sequence LIMIT $2`,
+		now.UTC(), limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanMessages(rows)
+}
+
+func (s *SqlStore) All() ([]Message, error) {
+	rows, err := s.DB.Query(`SELECT sequence, body, attempts, next_attempt_at, last_error FROM outbox_events ORDER BY sequence`)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanMessages(rows)
+}
+
+// scanMessages reads every message out of rows, and closes them.
+func scanMessages(rows *sql.Rows) ([]Message, error) {
+	defer rows.Close()
+
+	var messages []Message
+	for rows.Next() {
+		var m Message
+		var body string
+		if err := rows.Scan(&m.Sequence, &body, &m.Attempts, &m.NextAttemptAt, &m.LastError); err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal([]byte(body), &m.Event); err != nil {
+			return nil, err
+		}
+
+		messages = append(messages, m)
+	}
+
+	return messages, rows.Err()
+}
+
+func (s *SqlStore) MarkSent(sequence int64) error {
+	_, err := s.DB.Exec(`DELETE FROM outbox_events WHERE sequence = $1`, sequence)
+	return err
+}
+
+func (s *SqlStore) MarkFailed(sequence int64, nextAttemptAt time.Time, reason string) error {
+	_, err := s.DB.Exec(
+		`UPDATE outbox_events SET attempts = attempts + 1, next_attempt_at = $1, last_error = $2 WHERE sequence = $3`,
+		nextAttemptAt.UTC(), reason, sequence,
+	)
+	return err
+}
+
+func (s *SqlStore) Stats() (Stats, error) {
+	var stats Stats
+
+	err := s.DB.QueryRow(
+		`SELECT COUNT(*), COUNT(CASE WHEN attempts > 0 THEN 1 END) FROM outbox_events`,
+	).Scan(&stats.Pending, &stats.Failing)
+	if err != nil {
+		return stats, err
+	}
+
+	if stats.Pending > 0 {
+		var oldest time.Time
+		err := s.DB.QueryRow(`SELECT created_at FROM outbox_events ORDER BY sequence LIMIT 1`).Scan(&oldest)
+		if err != nil {
+			return stats, err
+		}
+		stats.Oldest = &oldest
+	}
+
+	return stats, nil
+}
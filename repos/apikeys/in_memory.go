@@ -0,0 +1,138 @@
+package apikeys
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/h4n-openschool/api/models"
+	"github.com/lucsky/cuid"
+)
+
+// InMemoryApiKeyRepository implements the [ApiKeyRepository] interface using
+// an in-memory map. It is safe for concurrent use.
+type InMemoryApiKeyRepository struct {
+	mu sync.Mutex
+
+	// items maps the id of every key to the key.
+	items map[string]models.ApiKey
+}
+
+// NewInMemoryApiKeyRepository creates a new instance of
+// [InMemoryApiKeyRepository]
+func NewInMemoryApiKeyRepository() *InMemoryApiKeyRepository {
+	return &InMemoryApiKeyRepository{items: map[string]models.ApiKey{}}
+}
+
+// NewInMemoryApiKeyRepositoryFrom creates a new instance of
+// [InMemoryApiKeyRepository] holding the given items, for example ones
+// restored from a snapshot.
+func NewInMemoryApiKeyRepositoryFrom(items []models.ApiKey) *InMemoryApiKeyRepository {
+	r := NewInMemoryApiKeyRepository()
+	for _, item := range items {
+		r.items[item.Id] = copyApiKey(item)
+	}
+
+	return r
+}
+
+func (r *InMemoryApiKeyRepository) All() ([]models.ApiKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.filter(func(models.ApiKey) bool { return true }), nil
+}
+
+func (r *InMemoryApiKeyRepository) List(userId string) ([]models.ApiKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.filter(func(k models.ApiKey) bool { return k.UserId == userId }), nil
+}
+
+func (r *InMemoryApiKeyRepository) Get(id string) (*models.ApiKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	item, ok := r.items[id]
+	if !ok {
+		return nil, nil
+	}
+
+	found := copyApiKey(item)
+	return &found, nil
+}
+
+func (r *InMemoryApiKeyRepository) Create(key models.ApiKey) (*models.ApiKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key.Id = cuid.New()
+	key.CreatedAt = time.Now()
+	key.RevokedAt = nil
+	key = copyApiKey(key)
+
+	r.items[key.Id] = key
+
+	created := copyApiKey(key)
+	return &created, nil
+}
+
+func (r *InMemoryApiKeyRepository) Revoke(id string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	item, ok := r.items[id]
+	if !ok || item.RevokedAt != nil {
+		return nil
+	}
+
+	item.RevokedAt = &at
+	r.items[id] = item
+
+	return nil
+}
+
+func (r *InMemoryApiKeyRepository) LookupApiKey(hash string) (string, string, []string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, item := range r.items {
+		if item.Hash != hash || item.RevokedAt != nil {
+			continue
+		}
+
+		scopes := make([]string, 0, len(item.Scopes))
+		for _, s := range item.Scopes {
+			scopes = append(scopes, string(s))
+		}
+
+		return item.Id, item.UserId, scopes, nil
+	}
+
+	return "", "", nil, nil
+}
+
+// filter returns copies of the keys matching keep, oldest first. The caller
+// must hold the lock.
+func (r *InMemoryApiKeyRepository) filter(keep func(models.ApiKey) bool) []models.ApiKey {
+	items := []models.ApiKey{}
+	for _, item := range r.items {
+		if keep(item) {
+			items = append(items, copyApiKey(item))
+		}
+	}
+
+	sort.Slice(items, func(a, b int) bool {
+		return items[a].CreatedAt.Before(items[b].CreatedAt)
+	})
+
+	return items
+}
+
+// copyApiKey copies the scopes of item, so the stored ones can't be changed
+// through a returned item.
+func copyApiKey(item models.ApiKey) models.ApiKey {
+	item.Scopes = append([]models.Scope(nil), item.Scopes...)
+	return item
+}
@@ -0,0 +1,30 @@
+package apikeys
+
+import (
+	"time"
+
+	"github.com/h4n-openschool/api/models"
+)
+
+// ApiKeyRepository defines a common interface for storing API keys.
+type ApiKeyRepository interface {
+	// List returns every key owned by a teacher, oldest first, including
+	// revoked ones.
+	List(userId string) ([]models.ApiKey, error)
+
+	// Get returns a single key by its id, or nil if there is none.
+	Get(id string) (*models.ApiKey, error)
+
+	// Create stores a new key, assigning its id and creation time.
+	Create(key models.ApiKey) (*models.ApiKey, error)
+
+	// Revoke revokes a key. Revoking a key twice keeps the first time.
+	Revoke(id string, at time.Time) error
+
+	// LookupApiKey returns the id, owner and scopes of the unrevoked key with
+	// the given hash. The ids are empty if there is no such key.
+	LookupApiKey(hash string) (keyId string, userId string, scopes []string, err error)
+
+	// All returns every key, oldest first.
+	All() ([]models.ApiKey, error)
+}
@@ -0,0 +1,136 @@
+package apikeys
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/h4n-openschool/api/models"
+	"github.com/h4n-openschool/api/utils"
+	"github.com/lucsky/cuid"
+)
+
+// SqlApiKeyRepository implements the [ApiKeyRepository] interface on top of
+// the `api_keys` table. Queries are written to run on both PostgreSQL and
+// SQLite.
+type SqlApiKeyRepository struct {
+	// DB is the database connection, or the transaction, used for every query.
+	DB utils.SqlConn
+}
+
+// NewSqlApiKeyRepository creates a new instance of [SqlApiKeyRepository]
+func NewSqlApiKeyRepository(db utils.SqlConn) *SqlApiKeyRepository {
+	return &SqlApiKeyRepository{DB: db}
+}
+
+// apiKeyColumns lists the columns scanned by scanApiKey, in order.
+const apiKeyColumns = `id, teacher_id, name, prefix, hash, scopes, created_at, revoked_at`
+
+// scanner is satisfied by both [sql.Row] and [sql.Rows].
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// scanApiKey reads a key selected with apiKeyColumns. Scopes are stored
+// space-separated, like in OAuth.
+func scanApiKey(row scanner) (*models.ApiKey, error) {
+	var key models.ApiKey
+	var scopes string
+	var revokedAt sql.NullTime
+
+	err := row.Scan(&key.Id, &key.UserId, &key.Name, &key.Prefix, &key.Hash, &scopes, &key.CreatedAt, &revokedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range strings.Fields(scopes) {
+		key.Scopes = append(key.Scopes, models.Scope(s))
+	}
+
+	if revokedAt.Valid {
+		key.RevokedAt = &revokedAt.Time
+	}
+
+	return &key, nil
+}
+
+func (r *SqlApiKeyRepository) List(userId string) ([]models.ApiKey, error) {
+	return r.list(`WHERE teacher_id = $1`, userId)
+}
+
+func (r *SqlApiKeyRepository) All() ([]models.ApiKey, error) {
+	return r.list(``)
+}
+
+// list returns the keys selected by the where clause, oldest first.
+func (r *SqlApiKeyRepository) list(where string, args ...any) ([]models.ApiKey, error) {
+	rows, err := r.DB.Query(`SELECT `+apiKeyColumns+` FROM api_keys `+where+` ORDER BY created_at, id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []models.ApiKey{}
+	for rows.Next() {
+		key, err := scanApiKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *key)
+	}
+
+	return items, rows.Err()
+}
+
+func (r *SqlApiKeyRepository) Get(id string) (*models.ApiKey, error) {
+	key, err := scanApiKey(r.DB.QueryRow(`SELECT `+apiKeyColumns+` FROM api_keys WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	return key, err
+}
+
+func (r *SqlApiKeyRepository) Create(key models.ApiKey) (*models.ApiKey, error) {
+	key.Id = cuid.New()
+	key.CreatedAt = time.Now()
+	key.RevokedAt = nil
+
+	scopes := make([]string, 0, len(key.Scopes))
+	for _, s := range key.Scopes {
+		scopes = append(scopes, string(s))
+	}
+
+	_, err := r.DB.Exec(
+		`INSERT INTO api_keys (id, teacher_id, name, prefix, hash, scopes, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		key.Id, key.UserId, key.Name, key.Prefix, key.Hash, strings.Join(scopes, " "), key.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &key, nil
+}
+
+func (r *SqlApiKeyRepository) Revoke(id string, at time.Time) error {
+	_, err := r.DB.Exec(`UPDATE api_keys SET revoked_at = $1 WHERE id = $2 AND revoked_at IS NULL`, at, id)
+	return err
+}
+
+func (r *SqlApiKeyRepository) LookupApiKey(hash string) (string, string, []string, error) {
+	var id, userId, scopes string
+
+	err := r.DB.QueryRow(
+		`SELECT id, teacher_id, scopes FROM api_keys WHERE hash = $1 AND revoked_at IS NULL`,
+		hash,
+	).Scan(&id, &userId, &scopes)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", "", nil, nil
+	}
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	return id, userId, strings.Fields(scopes), nil
+}
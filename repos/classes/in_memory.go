@@ -3,9 +3,11 @@ package classes
 import (
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/h4n-openschool/api/models"
+	"github.com/h4n-openschool/api/repos/teachers"
 	"github.com/h4n-openschool/api/utils"
 	"github.com/lucsky/cuid"
 )
@@ -15,17 +17,26 @@ var (
 )
 
 // InMemoryClassRepository implements the [ClassRepository] interface using an
-// in-memory slice of [models.Class] items.
+// in-memory slice of [models.Class] items. It is safe for concurrent use.
 type InMemoryClassRepository struct {
-	// Items is the slice of [models.Class] items stored in memory.
-	Items []models.Class
+	mu sync.RWMutex
+
+	// items is the slice of [models.Class] items stored in memory, in the order
+	// they were created.
+	items []models.Class
+
+	// index maps the id of every class to its position in items.
+	index map[string]int
 }
 
 // NewInMemoryClassRepository creates a new instance of
-// [NewInMemoryClassRepository]
-func NewInMemoryClassRepository(itemCount int) InMemoryClassRepository {
+// [InMemoryClassRepository], assigning the teachers of tr to the generated
+// classes in turn.
+func NewInMemoryClassRepository(tr teachers.TeacherRepository, itemCount int) *InMemoryClassRepository {
 	var items []models.Class
 
+	teacherList, _ := tr.GetAll(utils.NewPaginationQuery())
+
 	// Generate classes in-memory to use with repo methods.
 	for i := 0; i < itemCount; i++ {
 		id := cuid.New()
@@ -43,73 +54,108 @@ func NewInMemoryClassRepository(itemCount int) InMemoryClassRepository {
 			StartDate:   time.Now(),
 			EndDate:     time.Now().Add((24 * time.Hour) * 4),
 		})
+
+		if len(teacherList) > 0 {
+			items[i].TeacherIds = []string{teacherList[i%len(teacherList)].Id}
+		}
 	}
 
 	// Return the new repository to the caller
-	return InMemoryClassRepository{Items: items}
+	r := &InMemoryClassRepository{items: items}
+	r.reindex(0)
+
+	return r
+}
+
+// NewInMemoryClassRepositoryFrom creates a new instance of
+// [InMemoryClassRepository] holding the given items, for example ones
+// restored from a snapshot.
+func NewInMemoryClassRepositoryFrom(items []models.Class) *InMemoryClassRepository {
+	r := &InMemoryClassRepository{items: append([]models.Class(nil), items...)}
+	r.reindex(0)
+
+	return r
+}
+
+// All returns every stored class in the order they were created.
+func (r *InMemoryClassRepository) All() []models.Class {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	items := make([]models.Class, 0, len(r.items))
+	for i := range r.items {
+		items = append(items, copyClass(r.items[i]))
+	}
+
+	return items
 }
 
 func (r *InMemoryClassRepository) GetAll(pq utils.PaginationQuery) ([]models.Class, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	var items []models.Class
 
 	offset := pq.Offset()
-	for i := offset; i < (offset + pq.PerPage); i++ {
-		item := r.Items[i]
-		items = append(items, item)
+	for i := offset; i < (offset+pq.PerPage) && i < len(r.items); i++ {
+		items = append(items, copyClass(r.items[i]))
 	}
 
 	return items, nil
 }
 
 func (r *InMemoryClassRepository) Get(id string) (*models.Class, error) {
-	var found *models.Class
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 
-	for _, v := range r.Items {
-		if v.Id == id {
-			found = &v
-			break
-		}
+	k, ok := r.index[id]
+	if !ok {
+		return nil, nil
 	}
 
-	return found, nil
+	found := copyClass(r.items[k])
+	return &found, nil
 }
 
 func (r *InMemoryClassRepository) Update(class *models.Class) (*models.Class, error) {
-	var found *models.Class
-
-	for k, v := range r.Items {
-		if v.Id == class.Id {
-			if class.Name != "" && class.Name != v.Name {
-				return nil, errors.New("you cannot update Name after creation")
-			}
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
-			if v.DisplayName != "" {
-				v.DisplayName = class.DisplayName
-			}
-
-			if v.Description != nil {
-				v.Description = class.Description
-			}
+	k, ok := r.index[class.Id]
+	if !ok {
+		return nil, ClassDoesNotExist
+	}
+	v := r.items[k]
 
-      v.StartDate = class.StartDate
-      v.EndDate = class.EndDate
+	if class.Name != "" && class.Name != v.Name {
+		return nil, errors.New("you cannot update Name after creation")
+	}
 
-			v.StudentIds = class.StudentIds
-			v.UpdatedAt = time.Now()
+	if class.DisplayName != "" {
+		v.DisplayName = class.DisplayName
+	}
 
-			found = &v
+	if class.Description != nil {
+		v.Description = class.Description
+	}
 
-			r.Items[k] = *found
+	v.StartDate = class.StartDate
+	v.EndDate = class.EndDate
 
-			break
-		}
+	// A nil slice means the caller did not touch the enrolments or the
+	// assigned teachers, so only replace them when a list was actually given.
+	if class.StudentIds != nil {
+		v.StudentIds = append([]string(nil), class.StudentIds...)
 	}
-
-	if found == nil {
-		return nil, ClassDoesNotExist
+	if class.TeacherIds != nil {
+		v.TeacherIds = append([]string(nil), class.TeacherIds...)
 	}
+	v.UpdatedAt = time.Now()
 
-	return found, nil
+	r.items[k] = v
+
+	found := copyClass(v)
+	return &found, nil
 }
 
 func (r *InMemoryClassRepository) Create(class models.Class) (*models.Class, error) {
@@ -122,38 +168,87 @@ func (r *InMemoryClassRepository) Create(class models.Class) (*models.Class, err
 		Name:        class.Name,
 		DisplayName: class.DisplayName,
 		Description: class.Description,
+		StudentIds:  append([]string(nil), class.StudentIds...),
+		TeacherIds:  append([]string(nil), class.TeacherIds...),
+		StartDate:   class.StartDate,
+		EndDate:     class.EndDate,
 	}
 
-	r.Items = append(r.Items, model)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.items = append(r.items, model)
+	r.index[model.Id] = len(r.items) - 1
 
-	return &model, nil
+	found := copyClass(model)
+	return &found, nil
 }
 
-func (r *InMemoryClassRepository) Delete(class models.Class) error {
-	var newItems []models.Class
+func (r *InMemoryClassRepository) Enroll(classId string, studentId string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
-	var found *models.Class
-	for _, c := range r.Items {
-		if c.Id == class.Id {
-			found = &c
-			break
-		}
-	}
-	if found == nil {
-		return ClassDoesNotExist
+	k, ok := r.index[classId]
+	if !ok {
+		return false, ClassDoesNotExist
 	}
 
-	for _, c := range r.Items {
-		if c.Id != class.Id {
-			newItems = append(newItems, c)
+	for _, id := range r.items[k].StudentIds {
+		if id == studentId {
+			return false, nil
 		}
 	}
 
-	r.Items = newItems
+	r.items[k].StudentIds = append(append([]string(nil), r.items[k].StudentIds...), studentId)
+	r.items[k].UpdatedAt = time.Now()
+
+	return true, nil
+}
+
+func (r *InMemoryClassRepository) Delete(class models.Class) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	k, ok := r.index[class.Id]
+	if !ok {
+		return ClassDoesNotExist
+	}
+
+	r.items = append(r.items[:k], r.items[k+1:]...)
+	delete(r.index, class.Id)
+	r.reindex(k)
 
 	return nil
 }
 
 func (r *InMemoryClassRepository) Count() (int, error) {
-	return len(r.Items), nil
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.items), nil
+}
+
+// reindex rebuilds the positions of every item from position k onwards. The
+// caller must hold the write lock, or be the only user of the repository.
+func (r *InMemoryClassRepository) reindex(k int) {
+	if r.index == nil {
+		r.index = make(map[string]int, len(r.items))
+	}
+
+	for i := k; i < len(r.items); i++ {
+		r.index[r.items[i].Id] = i
+	}
+}
+
+// copyClass returns a copy of a class that shares no slices with the stored
+// item, so callers can't mutate the repository without holding the lock.
+func copyClass(c models.Class) models.Class {
+	if c.StudentIds != nil {
+		c.StudentIds = append([]string(nil), c.StudentIds...)
+	}
+	if c.TeacherIds != nil {
+		c.TeacherIds = append([]string(nil), c.TeacherIds...)
+	}
+
+	return c
 }
@@ -0,0 +1,95 @@
+package classes
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/h4n-openschool/api/models"
+	"github.com/h4n-openschool/api/repos/racetest"
+	"github.com/h4n-openschool/api/repos/teachers"
+	"github.com/h4n-openschool/api/utils"
+)
+
+// TestInMemoryClassRepositoryConcurrentUse calls every method with
+// [racetest.Run], with every worker enrolling students in the same class.
+func TestInMemoryClassRepositoryConcurrentUse(t *testing.T) {
+	r := NewInMemoryClassRepository(teachers.NewInMemoryTeacherRepository(2), 10)
+
+	// Every worker enrolls students in this class.
+	shared := r.All()[0].Id
+
+	racetest.Run(func(w int, round int) {
+		created, err := r.Create(models.Class{
+			Name:        fmt.Sprintf("concurrent-%v", w),
+			DisplayName: fmt.Sprintf("Concurrent %v", w),
+			StudentIds:  []string{"student"},
+			StartDate:   time.Now(),
+			EndDate:     time.Now(),
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		// Returned classes must not share their slices with the stored ones.
+		created.StudentIds[0] = "changed"
+
+		if _, err := r.Update(&models.Class{
+			BaseMetadata: models.BaseMetadata{Id: created.Id},
+			DisplayName:  fmt.Sprintf("Updated %v", w),
+			StudentIds:   []string{"student", "other"},
+		}); err != nil {
+			t.Error(err)
+		}
+
+		got, err := r.Get(created.Id)
+		if err != nil || got == nil {
+			t.Errorf("Get(%v) = %v, %v", created.Id, got, err)
+		} else if len(got.StudentIds) != 2 || got.StudentIds[0] != "student" {
+			t.Errorf("Get(%v).StudentIds = %v", created.Id, got.StudentIds)
+		}
+
+		studentId := fmt.Sprintf("student-%v-%v", w, round)
+		for i := 0; i < 2; i++ {
+			enrolled, err := r.Enroll(shared, studentId)
+			if err != nil {
+				t.Error(err)
+			}
+			if want := i == 0; enrolled != want {
+				t.Errorf("Enroll(%v) = %v, want %v", studentId, enrolled, want)
+			}
+		}
+
+		if _, err := r.GetAll(utils.NewPaginationQuery()); err != nil {
+			t.Error(err)
+		}
+		if _, err := r.Count(); err != nil {
+			t.Error(err)
+		}
+		_ = r.All()
+
+		if err := r.Delete(*created); err != nil {
+			t.Error(err)
+		}
+	})
+
+	count, err := r.Count()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 10 {
+		t.Errorf("Count() = %v, want %v", count, 10)
+	}
+
+	// Concurrent enrolments must not undo each other.
+	class, err := r.Get(shared)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(class.StudentIds) != racetest.Workers*racetest.Rounds {
+		t.Errorf("%v students enrolled, want %v", len(class.StudentIds), racetest.Workers*racetest.Rounds)
+	}
+
+	racetest.CheckIndexed(t, r.All(), func(c models.Class) string { return c.Id }, r.Get)
+}
@@ -23,6 +23,12 @@ type ClassRepository interface {
 	// possibly an error.
 	Create(class models.Class) (*models.Class, error)
 
+	// Enroll adds a student to the students of a class, reporting whether
+	// they weren't enrolled yet. Unlike updating the class with a new list of
+	// students, concurrent enrolments can't undo each other. It returns
+	// [ClassDoesNotExist] if there is no such class.
+	Enroll(classId string, studentId string) (bool, error)
+
 	// Delete takes a class object that includes at least an ID and deletes the
 	// relevant record for it in the data store.
 	Delete(class models.Class) error
@@ -0,0 +1,19 @@
+package classes_test
+
+import (
+	"testing"
+
+	"github.com/h4n-openschool/api/repos/repostest"
+)
+
+func TestInMemoryClassRepository(t *testing.T) {
+	repostest.TestClassRepository(t, repostest.Memory)
+}
+
+func TestSqliteClassRepository(t *testing.T) {
+	repostest.TestClassRepository(t, repostest.Sqlite)
+}
+
+func TestPostgresClassRepository(t *testing.T) {
+	repostest.TestClassRepository(t, repostest.Postgres)
+}
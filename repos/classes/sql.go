@@ -0,0 +1,321 @@
+package classes
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/h4n-openschool/api/models"
+	"github.com/h4n-openschool/api/utils"
+	"github.com/lucsky/cuid"
+)
+
+const classColumns = `id, name, display_name, description, start_date, end_date, created_at, updated_at`
+
+// SqlClassRepository implements the [ClassRepository] interface on top of a
+// [sql.DB] connection or [sql.Tx]. Queries are written to run on both
+// PostgreSQL and SQLite.
+type SqlClassRepository struct {
+	// DB is the database connection, or the transaction, used for every query.
+	DB utils.SqlConn
+}
+
+// NewSqlClassRepository creates a new instance of [SqlClassRepository]
+func NewSqlClassRepository(db utils.SqlConn) *SqlClassRepository {
+	return &SqlClassRepository{DB: db}
+}
+
+func (r *SqlClassRepository) GetAll(pq utils.PaginationQuery) ([]models.Class, error) {
+	rows, err := r.DB.Query(
+		`SELECT `+classColumns+` FROM classes ORDER BY created_at, id LIMIT $1 OFFSET $2`,
+		pq.PerPage, pq.Offset(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []models.Class
+	for rows.Next() {
+		class, err := scanClass(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *class)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for k := range items {
+		if err := r.loadMembers(&items[k]); err != nil {
+			return nil, err
+		}
+	}
+
+	return items, nil
+}
+
+func (r *SqlClassRepository) Get(id string) (*models.Class, error) {
+	row := r.DB.QueryRow(`SELECT `+classColumns+` FROM classes WHERE id = $1`, id)
+
+	class, err := scanClass(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.loadMembers(class); err != nil {
+		return nil, err
+	}
+
+	return class, nil
+}
+
+func (r *SqlClassRepository) Update(class *models.Class) (*models.Class, error) {
+	existing, err := r.Get(class.Id)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, ClassDoesNotExist
+	}
+
+	if class.Name != "" && class.Name != existing.Name {
+		return nil, errors.New("you cannot update Name after creation")
+	}
+
+	if class.DisplayName != "" {
+		existing.DisplayName = class.DisplayName
+	}
+
+	if class.Description != nil {
+		existing.Description = class.Description
+	}
+
+	existing.StartDate = class.StartDate
+	existing.EndDate = class.EndDate
+	existing.UpdatedAt = time.Now()
+
+	tx, err := utils.BeginSql(r.DB)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	_, err = tx.Exec(
+		`UPDATE classes SET display_name = $1, description = $2, start_date = $3, end_date = $4, updated_at = $5 WHERE id = $6`,
+		existing.DisplayName, existing.Description, existing.StartDate, existing.EndDate, existing.UpdatedAt, existing.Id,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// A nil slice means the caller did not touch the enrolments or the
+	// assigned teachers, so only replace them when a list was actually given.
+	if class.StudentIds != nil {
+		if err := replaceMemberIds(tx, `class_students`, `student_id`, existing.Id, class.StudentIds); err != nil {
+			return nil, err
+		}
+		existing.StudentIds = class.StudentIds
+	}
+
+	if class.TeacherIds != nil {
+		if err := replaceMemberIds(tx, `class_teachers`, `teacher_id`, existing.Id, class.TeacherIds); err != nil {
+			return nil, err
+		}
+		existing.TeacherIds = class.TeacherIds
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return existing, nil
+}
+
+func (r *SqlClassRepository) Create(class models.Class) (*models.Class, error) {
+	model := models.Class{
+		BaseMetadata: models.BaseMetadata{
+			Id:        cuid.New(),
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		},
+		Name:        class.Name,
+		DisplayName: class.DisplayName,
+		Description: class.Description,
+		StudentIds:  class.StudentIds,
+		TeacherIds:  class.TeacherIds,
+		StartDate:   class.StartDate,
+		EndDate:     class.EndDate,
+	}
+
+	tx, err := utils.BeginSql(r.DB)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	_, err = tx.Exec(
+		`INSERT INTO classes (`+classColumns+`) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		model.Id, model.Name, model.DisplayName, model.Description,
+		model.StartDate, model.EndDate, model.CreatedAt, model.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := replaceMemberIds(tx, `class_students`, `student_id`, model.Id, model.StudentIds); err != nil {
+		return nil, err
+	}
+
+	if err := replaceMemberIds(tx, `class_teachers`, `teacher_id`, model.Id, model.TeacherIds); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &model, nil
+}
+
+func (r *SqlClassRepository) Enroll(classId string, studentId string) (bool, error) {
+	var exists int
+	err := r.DB.QueryRow(`SELECT COUNT(*) FROM classes WHERE id = $1`, classId).Scan(&exists)
+	if err != nil {
+		return false, err
+	}
+	if exists == 0 {
+		return false, ClassDoesNotExist
+	}
+
+	tx, err := utils.BeginSql(r.DB)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.Exec(
+		`INSERT INTO class_students (class_id, student_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`,
+		classId, studentId,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	// Students enrolled already leave the class untouched.
+	if affected, err := res.RowsAffected(); err != nil || affected == 0 {
+		return false, err
+	}
+
+	if _, err := tx.Exec(`UPDATE classes SET updated_at = $1 WHERE id = $2`, time.Now(), classId); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+func (r *SqlClassRepository) Delete(class models.Class) error {
+	res, err := r.DB.Exec(`DELETE FROM classes WHERE id = $1`, class.Id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ClassDoesNotExist
+	}
+
+	return nil
+}
+
+func (r *SqlClassRepository) Count() (int, error) {
+	var count int
+	err := r.DB.QueryRow(`SELECT COUNT(*) FROM classes`).Scan(&count)
+	return count, err
+}
+
+// loadMembers fills in the enrolled students and assigned teachers of the
+// class.
+func (r *SqlClassRepository) loadMembers(class *models.Class) error {
+	var err error
+	if class.StudentIds, err = r.memberIds(`class_students`, `student_id`, class.Id); err != nil {
+		return err
+	}
+
+	class.TeacherIds, err = r.memberIds(`class_teachers`, `teacher_id`, class.Id)
+	return err
+}
+
+// memberIds returns the ids a join table holds for the class. The table and
+// column are always constants chosen by this file, never user input.
+func (r *SqlClassRepository) memberIds(table string, column string, classId string) ([]string, error) {
+	rows, err := r.DB.Query(
+		`SELECT `+column+` FROM `+table+` WHERE class_id = $1 ORDER BY `+column,
+		classId,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// replaceMemberIds swaps the ids a join table holds for the class for the
+// given ones. The table and column are always constants chosen by this file.
+func replaceMemberIds(tx utils.SqlConn, table string, column string, classId string, ids []string) error {
+	if _, err := tx.Exec(`DELETE FROM `+table+` WHERE class_id = $1`, classId); err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		if _, err := tx.Exec(
+			`INSERT INTO `+table+` (class_id, `+column+`) VALUES ($1, $2)`,
+			classId, id,
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// scanner is satisfied by both [sql.Row] and [sql.Rows].
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanClass(s scanner) (*models.Class, error) {
+	var class models.Class
+	var description sql.NullString
+
+	err := s.Scan(
+		&class.Id, &class.Name, &class.DisplayName, &description,
+		&class.StartDate, &class.EndDate, &class.CreatedAt, &class.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if description.Valid {
+		class.Description = &description.String
+	}
+
+	return &class, nil
+}
@@ -3,6 +3,7 @@ package grades
 import (
 	"errors"
 	"math/rand"
+	"sync"
 	"time"
 
 	"github.com/h4n-openschool/api/models"
@@ -16,139 +17,246 @@ var (
 )
 
 // InMemoryGradeRepository implements the [GradeRepository] interface using an
-// in-memory slice of [models.Grade] items.
+// in-memory slice of [models.Grade] items. It is safe for concurrent use.
 type InMemoryGradeRepository struct {
-	// Items is the slice of [models.Grade] items stored in memory.
-	Items []models.Grade
-}
+	mu sync.RWMutex
 
-// NewInMemoryGradeRepository creates a new instance of
-// [NewInMemoryGradeRepository]
-func NewInMemoryGradeRepository(cr classes.ClassRepository) InMemoryGradeRepository {
-	var items []models.Grade
+	// items is the slice of [models.Grade] items stored in memory, in the order
+	// they were created.
+	items []models.Grade
 
-  pq := utils.NewPaginationQuery()
-  c, _ := cr.GetAll(pq)
+	// index maps the id of every grade to its position in items.
+	index map[string]int
 
-  for _, class := range c {
-    for _, stu := range class.StudentIds {
-      // Generate grades in-memory to use with repo methods.
-      id := cuid.New()
+	// versions holds the versions of every grade by its id, oldest first.
+	versions map[string][]models.GradeVersion
+}
 
-      grade := rand.Intn(9)
+// NewInMemoryGradeRepository creates a new instance of
+// [InMemoryGradeRepository]
+func NewInMemoryGradeRepository(cr classes.ClassRepository) *InMemoryGradeRepository {
+	var items []models.Grade
 
-      items = append(items, models.Grade{
-        BaseMetadata: models.BaseMetadata{
-          Id:        id,
-          CreatedAt: time.Now(),
-          UpdatedAt: time.Now(),
-        },
-        StudentId: stu,
-        Value: grade,
-      })
-    }
-  }
+	pq := utils.NewPaginationQuery()
+	c, _ := cr.GetAll(pq)
+
+	for _, class := range c {
+		for _, stu := range class.StudentIds {
+			// Generate grades in-memory to use with repo methods.
+			id := cuid.New()
+
+			grade := rand.Intn(9)
+
+			items = append(items, models.Grade{
+				BaseMetadata: models.BaseMetadata{
+					Id:        id,
+					CreatedAt: time.Now(),
+					UpdatedAt: time.Now(),
+				},
+				ClassId:   class.Id,
+				StudentId: stu,
+				Value:     grade,
+			})
+		}
+	}
 
 	// Return the new repository to the caller
-	return InMemoryGradeRepository{Items: items}
-}
+	r := &InMemoryGradeRepository{items: items, versions: map[string][]models.GradeVersion{}}
+	r.reindex(0)
 
-func (r *InMemoryGradeRepository) GetAll(classId string, pq utils.PaginationQuery) ([]models.Grade, error) {
-	var items []models.Grade
-
-	offset := pq.Offset()
-	for i := offset; i < (offset + pq.PerPage); i++ {
-    if r.Items[i].ClassId == classId {
-      item := r.Items[i]
-      items = append(items, item)
-    }
+	for _, grade := range items {
+		r.versions[grade.Id] = []models.GradeVersion{models.NewGradeVersion(grade, 1, models.GradeChange{})}
 	}
 
-	return items, nil
+	return r
 }
 
-func (r *InMemoryGradeRepository) Get(id string) (*models.Grade, error) {
-	var found *models.Grade
+// NewInMemoryGradeRepositoryFrom creates a new instance of
+// [InMemoryGradeRepository] holding the given items and versions, for example
+// ones restored from a snapshot. Grades without any version, such as those
+// from snapshots taken before versions were kept, start at version 1.
+func NewInMemoryGradeRepositoryFrom(items []models.Grade, versions []models.GradeVersion) *InMemoryGradeRepository {
+	r := &InMemoryGradeRepository{
+		items:    append([]models.Grade(nil), items...),
+		versions: map[string][]models.GradeVersion{},
+	}
+	r.reindex(0)
 
-	for _, v := range r.Items {
-		if v.Id == id {
-			found = &v
-			break
+	for _, v := range versions {
+		r.versions[v.GradeId] = append(r.versions[v.GradeId], v)
+	}
+	for _, grade := range r.items {
+		if len(r.versions[grade.Id]) == 0 {
+			r.versions[grade.Id] = []models.GradeVersion{models.NewGradeVersion(grade, 1, models.GradeChange{})}
 		}
 	}
 
-	return found, nil
+	return r
 }
 
-func (r *InMemoryGradeRepository) Update(grade *models.Grade) (*models.Grade, error) {
-	var found *models.Grade
+// All returns every stored grade in the order they were created.
+func (r *InMemoryGradeRepository) All() []models.Grade {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 
-	for k, v := range r.Items {
-		if v.Id == grade.Id {
-			if grade.StudentId != "" && grade.StudentId != v.StudentId {
-				return nil, errors.New("you cannot update StudentId after creation")
-			}
+	return append([]models.Grade(nil), r.items...)
+}
 
-      v.Value = grade.Value
+// AllVersions returns the versions of every stored grade, in the order the
+// grades were created.
+func (r *InMemoryGradeRepository) AllVersions() []models.GradeVersion {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 
-			v.UpdatedAt = time.Now()
+	var versions []models.GradeVersion
+	for _, grade := range r.items {
+		versions = append(versions, r.versions[grade.Id]...)
+	}
 
-			found = &v
+	return versions
+}
 
-			r.Items[k] = *found
+func (r *InMemoryGradeRepository) GetAll(classId string, pq utils.PaginationQuery) ([]models.Grade, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 
+	var items []models.Grade
+
+	offset := pq.Offset()
+	seen := 0
+	for _, item := range r.items {
+		if item.ClassId != classId {
+			continue
+		}
+
+		if seen >= offset {
+			items = append(items, item)
+		}
+		seen++
+
+		if len(items) >= pq.PerPage {
 			break
 		}
 	}
 
-	if found == nil {
+	return items, nil
+}
+
+func (r *InMemoryGradeRepository) Get(id string) (*models.Grade, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	k, ok := r.index[id]
+	if !ok {
+		return nil, nil
+	}
+
+	found := r.items[k]
+	return &found, nil
+}
+
+func (r *InMemoryGradeRepository) Update(grade *models.Grade, change models.GradeChange) (*models.Grade, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	k, ok := r.index[grade.Id]
+	if !ok {
 		return nil, GradeDoesNotExist
 	}
+	v := r.items[k]
+
+	if grade.StudentId != "" && grade.StudentId != v.StudentId {
+		return nil, errors.New("you cannot update StudentId after creation")
+	}
+
+	v.Value = grade.Value
+	v.UpdatedAt = time.Now()
+
+	r.items[k] = v
+	r.versions[v.Id] = append(r.versions[v.Id], models.NewGradeVersion(v, len(r.versions[v.Id])+1, change))
 
-	return found, nil
+	return &v, nil
 }
 
-func (r *InMemoryGradeRepository) Create(grade models.Grade) (*models.Grade, error) {
+func (r *InMemoryGradeRepository) Create(grade models.Grade, change models.GradeChange) (*models.Grade, error) {
 	model := models.Grade{
 		BaseMetadata: models.BaseMetadata{
 			Id:        cuid.New(),
 			CreatedAt: time.Now(),
 			UpdatedAt: time.Now(),
 		},
+		ClassId:   grade.ClassId,
 		StudentId: grade.StudentId,
 		Value:     grade.Value,
 	}
 
-	r.Items = append(r.Items, model)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.items = append(r.items, model)
+	r.index[model.Id] = len(r.items) - 1
+	r.versions[model.Id] = []models.GradeVersion{models.NewGradeVersion(model, 1, change)}
 
 	return &model, nil
 }
 
 func (r *InMemoryGradeRepository) Delete(grade models.Grade) error {
-	var newItems []models.Grade
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
-	var found *models.Grade
-	for _, c := range r.Items {
-		if c.Id == grade.Id {
-			found = &c
-			break
-		}
-	}
-	if found == nil {
+	k, ok := r.index[grade.Id]
+	if !ok {
 		return GradeDoesNotExist
 	}
 
-	for _, c := range r.Items {
-		if c.Id != grade.Id {
-			newItems = append(newItems, c)
-		}
-	}
-
-	r.Items = newItems
+	r.items = append(r.items[:k], r.items[k+1:]...)
+	delete(r.index, grade.Id)
+	delete(r.versions, grade.Id)
+	r.reindex(k)
 
 	return nil
 }
 
+func (r *InMemoryGradeRepository) History(id string) ([]models.GradeVersion, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if _, ok := r.index[id]; !ok {
+		return nil, GradeDoesNotExist
+	}
+
+	return append([]models.GradeVersion(nil), r.versions[id]...), nil
+}
+
 func (r *InMemoryGradeRepository) Count() (int, error) {
-	return len(r.Items), nil
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.items), nil
+}
+
+func (r *InMemoryGradeRepository) CountForClass(classId string) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	count := 0
+	for _, item := range r.items {
+		if item.ClassId == classId {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// reindex rebuilds the positions of every item from position k onwards. The
+// caller must hold the write lock, or be the only user of the repository.
+func (r *InMemoryGradeRepository) reindex(k int) {
+	if r.index == nil {
+		r.index = make(map[string]int, len(r.items))
+	}
+
+	for i := k; i < len(r.items); i++ {
+		r.index[r.items[i].Id] = i
+	}
 }
@@ -0,0 +1,75 @@
+package grades
+
+import (
+	"testing"
+
+	"github.com/h4n-openschool/api/models"
+	"github.com/h4n-openschool/api/repos/classes"
+	"github.com/h4n-openschool/api/repos/racetest"
+	"github.com/h4n-openschool/api/repos/teachers"
+	"github.com/h4n-openschool/api/utils"
+)
+
+// TestInMemoryGradeRepositoryConcurrentUse calls every method with
+// [racetest.Run], every worker grading in the same class.
+func TestInMemoryGradeRepositoryConcurrentUse(t *testing.T) {
+	cr := classes.NewInMemoryClassRepository(teachers.NewInMemoryTeacherRepository(2), 2)
+	r := NewInMemoryGradeRepository(cr)
+
+	initial, err := r.Count()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	classId := cr.All()[0].Id
+
+	racetest.Run(func(w int, round int) {
+		created, err := r.Create(models.Grade{ClassId: classId, StudentId: "student", Value: w}, models.GradeChange{ChangedBy: "teacher"})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		update := *created
+		update.Value = w + 1
+		if _, err := r.Update(&update, models.GradeChange{ChangedBy: "teacher"}); err != nil {
+			t.Error(err)
+		}
+
+		got, err := r.Get(created.Id)
+		if err != nil || got == nil || got.Value != w+1 {
+			t.Errorf("Get(%v) = %v, %v", created.Id, got, err)
+		}
+
+		versions, err := r.History(created.Id)
+		if err != nil || len(versions) != 2 {
+			t.Errorf("History(%v) = %v, %v", created.Id, versions, err)
+		}
+
+		if _, err := r.GetAll(classId, utils.NewPaginationQuery()); err != nil {
+			t.Error(err)
+		}
+		if _, err := r.Count(); err != nil {
+			t.Error(err)
+		}
+		if _, err := r.CountForClass(classId); err != nil {
+			t.Error(err)
+		}
+		_ = r.All()
+		_ = r.AllVersions()
+
+		if err := r.Delete(*created); err != nil {
+			t.Error(err)
+		}
+	})
+
+	count, err := r.Count()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != initial {
+		t.Errorf("Count() = %v, want %v", count, initial)
+	}
+
+	racetest.CheckIndexed(t, r.All(), func(g models.Grade) string { return g.Id }, r.Get)
+}
@@ -15,13 +15,18 @@ type GradeRepository interface {
 	Get(id string) (*models.Grade, error)
 
 	// Update takes a grade object that has been mutated and persists it to the
-	// data store, returning the modified object and possibly an error.
-	Update(grade *models.Grade) (*models.Grade, error)
+	// data store, returning the modified object and possibly an error. The new
+	// value is recorded as the next version of the grade.
+	Update(grade *models.Grade, change models.GradeChange) (*models.Grade, error)
 
 	// Create takes a grade object that has been populated with data and creates
 	// a record for it in the data store, returning the filled record and
-	// possibly an error.
-	Create(grade models.Grade) (*models.Grade, error)
+	// possibly an error. Its value is recorded as version 1 of the grade.
+	Create(grade models.Grade, change models.GradeChange) (*models.Grade, error)
+
+	// History returns every version of the grade with the given id, oldest
+	// first. It returns [GradeDoesNotExist] if there is no such grade.
+	History(id string) ([]models.GradeVersion, error)
 
 	// Delete takes a grade object that includes at least an ID and deletes the
 	// relevant record for it in the data store.
@@ -29,4 +34,7 @@ type GradeRepository interface {
 
 	// Count returns the total number of grades in the datastore.
 	Count() (int, error)
+
+	// CountForClass returns the number of grades given in a class.
+	CountForClass(classId string) (int, error)
 }
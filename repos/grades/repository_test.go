@@ -0,0 +1,19 @@
+package grades_test
+
+import (
+	"testing"
+
+	"github.com/h4n-openschool/api/repos/repostest"
+)
+
+func TestInMemoryGradeRepository(t *testing.T) {
+	repostest.TestGradeRepository(t, repostest.Memory)
+}
+
+func TestSqliteGradeRepository(t *testing.T) {
+	repostest.TestGradeRepository(t, repostest.Sqlite)
+}
+
+func TestPostgresGradeRepository(t *testing.T) {
+	repostest.TestGradeRepository(t, repostest.Postgres)
+}
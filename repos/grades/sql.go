@@ -0,0 +1,244 @@
+package grades
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/h4n-openschool/api/models"
+	"github.com/h4n-openschool/api/utils"
+	"github.com/lucsky/cuid"
+)
+
+const gradeColumns = `id, class_id, student_id, value, created_at, updated_at`
+
+// SqlGradeRepository implements the [GradeRepository] interface on top of a
+// [sql.DB] connection or [sql.Tx]. Queries are written to run on both
+// PostgreSQL and SQLite.
+type SqlGradeRepository struct {
+	// DB is the database connection, or the transaction, used for every query.
+	DB utils.SqlConn
+}
+
+// NewSqlGradeRepository creates a new instance of [SqlGradeRepository]
+func NewSqlGradeRepository(db utils.SqlConn) *SqlGradeRepository {
+	return &SqlGradeRepository{DB: db}
+}
+
+func (r *SqlGradeRepository) GetAll(classId string, pq utils.PaginationQuery) ([]models.Grade, error) {
+	rows, err := r.DB.Query(
+		`SELECT `+gradeColumns+` FROM grades WHERE class_id = $1 ORDER BY created_at, id LIMIT $2 OFFSET $3`,
+		classId, pq.PerPage, pq.Offset(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []models.Grade
+	for rows.Next() {
+		grade, err := scanGrade(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *grade)
+	}
+
+	return items, rows.Err()
+}
+
+func (r *SqlGradeRepository) Get(id string) (*models.Grade, error) {
+	row := r.DB.QueryRow(`SELECT `+gradeColumns+` FROM grades WHERE id = $1`, id)
+
+	grade, err := scanGrade(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	return grade, err
+}
+
+func (r *SqlGradeRepository) Update(grade *models.Grade, change models.GradeChange) (*models.Grade, error) {
+	existing, err := r.Get(grade.Id)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, GradeDoesNotExist
+	}
+
+	if grade.StudentId != "" && grade.StudentId != existing.StudentId {
+		return nil, errors.New("you cannot update StudentId after creation")
+	}
+
+	existing.Value = grade.Value
+	existing.UpdatedAt = time.Now()
+
+	tx, err := utils.BeginSql(r.DB)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Updating the grade first locks its row on PostgreSQL, so concurrent
+	// updates can't both claim the next version.
+	res, err := tx.Exec(
+		`UPDATE grades SET value = $1, updated_at = $2 WHERE id = $3`,
+		existing.Value, existing.UpdatedAt, existing.Id,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, GradeDoesNotExist
+	}
+
+	var version int
+	err = tx.QueryRow(`SELECT COALESCE(MAX(version), 0) + 1 FROM grade_versions WHERE grade_id = $1`, existing.Id).Scan(&version)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := insertVersion(tx, models.NewGradeVersion(*existing, version, change)); err != nil {
+		return nil, err
+	}
+
+	return existing, tx.Commit()
+}
+
+func (r *SqlGradeRepository) Create(grade models.Grade, change models.GradeChange) (*models.Grade, error) {
+	model := models.Grade{
+		BaseMetadata: models.BaseMetadata{
+			Id:        cuid.New(),
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		},
+		ClassId:   grade.ClassId,
+		StudentId: grade.StudentId,
+		Value:     grade.Value,
+	}
+
+	tx, err := utils.BeginSql(r.DB)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(
+		`INSERT INTO grades (`+gradeColumns+`) VALUES ($1, $2, $3, $4, $5, $6)`,
+		model.Id, model.ClassId, model.StudentId, model.Value, model.CreatedAt, model.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := insertVersion(tx, models.NewGradeVersion(model, 1, change)); err != nil {
+		return nil, err
+	}
+
+	return &model, tx.Commit()
+}
+
+func (r *SqlGradeRepository) Delete(grade models.Grade) error {
+	res, err := r.DB.Exec(`DELETE FROM grades WHERE id = $1`, grade.Id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return GradeDoesNotExist
+	}
+
+	return nil
+}
+
+func (r *SqlGradeRepository) History(id string) ([]models.GradeVersion, error) {
+	grade, err := r.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if grade == nil {
+		return nil, GradeDoesNotExist
+	}
+
+	rows, err := r.DB.Query(
+		`SELECT grade_id, version, value, changed_by, reason, revert_of, changed_at FROM grade_versions WHERE grade_id = $1 ORDER BY version`,
+		id,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var versions []models.GradeVersion
+	for rows.Next() {
+		var v models.GradeVersion
+		var reason sql.NullString
+		var revertOf sql.NullInt64
+
+		err := rows.Scan(&v.GradeId, &v.Version, &v.Value, &v.ChangedBy, &reason, &revertOf, &v.ChangedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		if reason.Valid {
+			v.Reason = &reason.String
+		}
+		if revertOf.Valid {
+			version := int(revertOf.Int64)
+			v.RevertOf = &version
+		}
+
+		versions = append(versions, v)
+	}
+
+	return versions, rows.Err()
+}
+
+func (r *SqlGradeRepository) Count() (int, error) {
+	var count int
+	err := r.DB.QueryRow(`SELECT COUNT(*) FROM grades`).Scan(&count)
+	return count, err
+}
+
+func (r *SqlGradeRepository) CountForClass(classId string) (int, error) {
+	var count int
+	err := r.DB.QueryRow(`SELECT COUNT(*) FROM grades WHERE class_id = $1`, classId).Scan(&count)
+	return count, err
+}
+
+// scanner is satisfied by both [sql.Row] and [sql.Rows].
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanGrade(s scanner) (*models.Grade, error) {
+	var grade models.Grade
+
+	err := s.Scan(
+		&grade.Id, &grade.ClassId, &grade.StudentId, &grade.Value,
+		&grade.CreatedAt, &grade.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &grade, nil
+}
+
+func insertVersion(tx utils.SqlConn, v models.GradeVersion) error {
+	_, err := tx.Exec(
+		`INSERT INTO grade_versions (grade_id, version, value, changed_by, reason, revert_of, changed_at) VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		v.GradeId, v.Version, v.Value, v.ChangedBy, v.Reason, v.RevertOf, v.ChangedAt,
+	)
+	return err
+}
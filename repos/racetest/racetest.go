@@ -0,0 +1,54 @@
+// Package racetest calls the methods of a repository from many goroutines at
+// once, so `go test -race` catches unsynchronised access.
+package racetest
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+)
+
+const (
+	// Workers is the number of goroutines [Run] calls the round function from.
+	Workers = 20
+
+	// Rounds is the number of times each worker calls the round function.
+	Rounds = 50
+)
+
+// Run calls round Rounds times from each of Workers goroutines, all started
+// at once, and returns when every call returned. round is given the number of
+// the worker and of the round, for example to create distinct records.
+func Run(round func(worker int, round int)) {
+	// Run the workers in parallel even on a single CPU, so their calls
+	// actually overlap.
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for w := 0; w < Workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			<-start
+
+			for r := 0; r < Rounds; r++ {
+				round(w, r)
+			}
+		}(w)
+	}
+	close(start)
+	wg.Wait()
+}
+
+// CheckIndexed checks that every item left in a repository after concurrent
+// deletes is still found by get at its indexed position.
+func CheckIndexed[T any](t *testing.T, items []T, id func(T) string, get func(id string) (*T, error)) {
+	t.Helper()
+
+	for _, item := range items {
+		if got, _ := get(id(item)); got == nil || id(*got) != id(item) {
+			t.Errorf("Get(%v) = %v after concurrent deletes", id(item), got)
+		}
+	}
+}
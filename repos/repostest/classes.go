@@ -0,0 +1,194 @@
+package repostest
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/h4n-openschool/api/models"
+	classRepos "github.com/h4n-openschool/api/repos/classes"
+	"github.com/h4n-openschool/api/storage"
+)
+
+// TestClassRepository checks the behaviour of the class repository opened by
+// open.
+func TestClassRepository(t *testing.T, open Open) {
+	t.Run("get unknown", func(t *testing.T) {
+		r := open(t).Classes
+
+		class, err := r.Get("unknown")
+		if err != nil || class != nil {
+			t.Errorf("Get(unknown) = %v, %v, want nil, nil", class, err)
+		}
+	})
+
+	t.Run("create and get", func(t *testing.T) {
+		repos := open(t)
+		teacher := createTeacher(t, repos, "teacher@example.com")
+
+		desc := "Counting to ten"
+		created, err := repos.Classes.Create(models.Class{
+			Name:        "maths",
+			DisplayName: "Maths",
+			Description: &desc,
+			TeacherIds:  []string{teacher.Id},
+			StartDate:   time.Now(),
+			EndDate:     time.Now().Add(24 * time.Hour),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if created.Id == "" {
+			t.Fatal("Create() returned a class without an id")
+		}
+
+		got := getClass(t, repos, created.Id)
+		if got.Name != "maths" || got.DisplayName != "Maths" || got.Description == nil || *got.Description != desc {
+			t.Errorf("Get() = %+v, want the created class", got)
+		}
+		if len(got.TeacherIds) != 1 || got.TeacherIds[0] != teacher.Id {
+			t.Errorf("Get().TeacherIds = %v, want [%v]", got.TeacherIds, teacher.Id)
+		}
+		if len(got.StudentIds) != 0 {
+			t.Errorf("Get().StudentIds = %v, want none", got.StudentIds)
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		repos := open(t)
+		class := createClass(t, repos, "maths")
+		student := createStudent(t, repos, "Ada")
+		enroll(t, repos, class.Id, student.Id, true)
+
+		// Without a list of students the enrolments are kept.
+		_, err := repos.Classes.Update(&models.Class{
+			BaseMetadata: models.BaseMetadata{Id: class.Id},
+			DisplayName:  "Advanced maths",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got := getClass(t, repos, class.Id)
+		if got.DisplayName != "Advanced maths" {
+			t.Errorf("Get().DisplayName = %q, want %q", got.DisplayName, "Advanced maths")
+		}
+		if len(got.StudentIds) != 1 || got.StudentIds[0] != student.Id {
+			t.Errorf("Get().StudentIds = %v, want [%v]", got.StudentIds, student.Id)
+		}
+
+		_, err = repos.Classes.Update(&models.Class{
+			BaseMetadata: models.BaseMetadata{Id: class.Id},
+			Name:         "renamed",
+		})
+		if err == nil {
+			t.Error("Update() renamed the class")
+		}
+	})
+
+	t.Run("unknown class", func(t *testing.T) {
+		r := open(t).Classes
+
+		if _, err := r.Update(&models.Class{BaseMetadata: models.BaseMetadata{Id: "unknown"}}); !errors.Is(err, classRepos.ClassDoesNotExist) {
+			t.Errorf("Update(unknown) = %v, want %v", err, classRepos.ClassDoesNotExist)
+		}
+		if _, err := r.Enroll("unknown", "student"); !errors.Is(err, classRepos.ClassDoesNotExist) {
+			t.Errorf("Enroll(unknown) = %v, want %v", err, classRepos.ClassDoesNotExist)
+		}
+		if err := r.Delete(models.Class{BaseMetadata: models.BaseMetadata{Id: "unknown"}}); !errors.Is(err, classRepos.ClassDoesNotExist) {
+			t.Errorf("Delete(unknown) = %v, want %v", err, classRepos.ClassDoesNotExist)
+		}
+	})
+
+	t.Run("enroll is idempotent", func(t *testing.T) {
+		repos := open(t)
+		class := createClass(t, repos, "maths")
+		ada := createStudent(t, repos, "Ada")
+		alan := createStudent(t, repos, "Alan")
+
+		enroll(t, repos, class.Id, ada.Id, true)
+		enroll(t, repos, class.Id, ada.Id, false)
+		enroll(t, repos, class.Id, alan.Id, true)
+
+		got := getClass(t, repos, class.Id)
+		if len(got.StudentIds) != 2 {
+			t.Errorf("Get().StudentIds = %v, want Ada and Alan once each", got.StudentIds)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		repos := open(t)
+		class := createClass(t, repos, "maths")
+		other := createClass(t, repos, "history")
+
+		if err := repos.Classes.Delete(*class); err != nil {
+			t.Fatal(err)
+		}
+
+		if got, err := repos.Classes.Get(class.Id); err != nil || got != nil {
+			t.Errorf("Get() after Delete() = %v, %v, want nil, nil", got, err)
+		}
+		getClass(t, repos, other.Id)
+		testCount(t, repos.Classes.Count, 1)
+	})
+
+	t.Run("count and paginate", func(t *testing.T) {
+		repos := open(t)
+		testCount(t, repos.Classes.Count, 0)
+
+		for i := 0; i < 5; i++ {
+			createClass(t, repos, fmt.Sprintf("class-%v", i))
+		}
+
+		testCount(t, repos.Classes.Count, 5)
+		testPagination(t, 5, repos.Classes.GetAll, func(c models.Class) string { return c.Id })
+	})
+}
+
+// createClass creates a class with the given name.
+func createClass(t *testing.T, repos *storage.Repositories, name string) *models.Class {
+	t.Helper()
+
+	class, err := repos.Classes.Create(models.Class{
+		Name:        name,
+		DisplayName: name,
+		StartDate:   time.Now(),
+		EndDate:     time.Now().Add(24 * time.Hour),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return class
+}
+
+// getClass returns the class with the given id, failing the test if there is
+// none.
+func getClass(t *testing.T, repos *storage.Repositories, id string) *models.Class {
+	t.Helper()
+
+	class, err := repos.Classes.Get(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if class == nil {
+		t.Fatalf("Get(%v) = nil, want the class", id)
+	}
+
+	return class
+}
+
+// enroll enrolls a student in a class, checking whether it reports they
+// weren't enrolled yet.
+func enroll(t *testing.T, repos *storage.Repositories, classId string, studentId string, want bool) {
+	t.Helper()
+
+	enrolled, err := repos.Classes.Enroll(classId, studentId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if enrolled != want {
+		t.Errorf("Enroll(%v, %v) = %v, want %v", classId, studentId, enrolled, want)
+	}
+}
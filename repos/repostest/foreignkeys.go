@@ -0,0 +1,134 @@
+package repostest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/h4n-openschool/api/models"
+	"github.com/h4n-openschool/api/storage"
+)
+
+// TestForeignKeys checks that the SQL database opened by open keeps the links
+// between records consistent: records can't point at missing ones, and
+// deleting a record deletes those that depend on it.
+func TestForeignKeys(t *testing.T, open Open) {
+	t.Run("missing records", func(t *testing.T) {
+		repos := open(t)
+		class := createClass(t, repos, "maths")
+		student := createStudent(t, repos, "Ada")
+
+		if _, err := repos.Grades.Create(models.Grade{ClassId: "unknown", StudentId: student.Id}, models.GradeChange{}); err == nil {
+			t.Error("created a grade in a missing class")
+		}
+		if _, err := repos.Grades.Create(models.Grade{ClassId: class.Id, StudentId: "unknown"}, models.GradeChange{}); err == nil {
+			t.Error("created a grade for a missing student")
+		}
+		if _, err := repos.Classes.Enroll(class.Id, "unknown"); err == nil {
+			t.Error("enrolled a missing student")
+		}
+		if _, err := repos.Classes.Create(models.Class{
+			Name:       "history",
+			TeacherIds: []string{"unknown"},
+			StartDate:  time.Now(),
+			EndDate:    time.Now(),
+		}); err == nil {
+			t.Error("assigned a missing teacher to a class")
+		}
+
+		testCount(t, repos.Grades.Count, 0)
+		testCount(t, repos.Classes.Count, 1)
+	})
+
+	t.Run("deleting a class", func(t *testing.T) {
+		repos := open(t)
+		maths := createClass(t, repos, "maths")
+		history := createClass(t, repos, "history")
+		student := createStudent(t, repos, "Ada")
+		enroll(t, repos, maths.Id, student.Id, true)
+		grade := createGrade(t, repos, maths.Id, student.Id, 12)
+		kept := createGrade(t, repos, history.Id, student.Id, 15)
+
+		if err := repos.Classes.Delete(*maths); err != nil {
+			t.Fatal(err)
+		}
+
+		if got, err := repos.Grades.Get(grade.Id); err != nil || got != nil {
+			t.Errorf("grade of the deleted class = %v, %v, want nil, nil", got, err)
+		}
+		testCount(t, func() (int, error) { return countRows(repos, `grade_versions`, `grade_id`, grade.Id) }, 0)
+		testCount(t, func() (int, error) { return countRows(repos, `class_students`, `class_id`, maths.Id) }, 0)
+		getGrade(t, repos, kept.Id)
+		getStudent(t, repos, student.Id)
+	})
+
+	t.Run("deleting a grade", func(t *testing.T) {
+		repos := open(t)
+		class := createClass(t, repos, "maths")
+		student := createStudent(t, repos, "Ada")
+		grade := createGrade(t, repos, class.Id, student.Id, 12)
+
+		update := *grade
+		update.Value = 14
+		if _, err := repos.Grades.Update(&update, models.GradeChange{ChangedBy: "teacher"}); err != nil {
+			t.Fatal(err)
+		}
+		testCount(t, func() (int, error) { return countRows(repos, `grade_versions`, `grade_id`, grade.Id) }, 2)
+
+		if err := repos.Grades.Delete(*grade); err != nil {
+			t.Fatal(err)
+		}
+
+		testCount(t, func() (int, error) { return countRows(repos, `grade_versions`, `grade_id`, grade.Id) }, 0)
+		getClass(t, repos, class.Id)
+	})
+
+	t.Run("deleting a student", func(t *testing.T) {
+		repos := open(t)
+		class := createClass(t, repos, "maths")
+		ada := createStudent(t, repos, "Ada")
+		alan := createStudent(t, repos, "Alan")
+		enroll(t, repos, class.Id, ada.Id, true)
+		enroll(t, repos, class.Id, alan.Id, true)
+		createGrade(t, repos, class.Id, ada.Id, 12)
+		createGrade(t, repos, class.Id, alan.Id, 15)
+
+		if err := repos.Students.Delete(*ada); err != nil {
+			t.Fatal(err)
+		}
+
+		got := getClass(t, repos, class.Id)
+		if len(got.StudentIds) != 1 || got.StudentIds[0] != alan.Id {
+			t.Errorf("StudentIds = %v after deleting a student, want [%v]", got.StudentIds, alan.Id)
+		}
+		testCount(t, func() (int, error) { return repos.Grades.CountForClass(class.Id) }, 1)
+	})
+
+	t.Run("deleting a teacher", func(t *testing.T) {
+		repos := open(t)
+		teacher := createTeacher(t, repos, "ada@example.com")
+		class, err := repos.Classes.Create(models.Class{
+			Name:       "maths",
+			TeacherIds: []string{teacher.Id},
+			StartDate:  time.Now(),
+			EndDate:    time.Now(),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := repos.Teachers.Delete(*teacher); err != nil {
+			t.Fatal(err)
+		}
+
+		if got := getClass(t, repos, class.Id); len(got.TeacherIds) != 0 {
+			t.Errorf("TeacherIds = %v after deleting the teacher, want none", got.TeacherIds)
+		}
+	})
+}
+
+// countRows counts the rows of a table whose column holds value.
+func countRows(repos *storage.Repositories, table string, column string, value string) (int, error) {
+	var count int
+	err := repos.DB.QueryRow(`SELECT COUNT(*) FROM `+table+` WHERE `+column+` = $1`, value).Scan(&count)
+	return count, err
+}
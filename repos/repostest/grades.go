@@ -0,0 +1,149 @@
+package repostest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/h4n-openschool/api/models"
+	gradeRepos "github.com/h4n-openschool/api/repos/grades"
+	"github.com/h4n-openschool/api/storage"
+	"github.com/h4n-openschool/api/utils"
+)
+
+// TestGradeRepository checks the behaviour of the grade repository opened by
+// open.
+func TestGradeRepository(t *testing.T, open Open) {
+	t.Run("get unknown", func(t *testing.T) {
+		r := open(t).Grades
+
+		grade, err := r.Get("unknown")
+		if err != nil || grade != nil {
+			t.Errorf("Get(unknown) = %v, %v, want nil, nil", grade, err)
+		}
+	})
+
+	t.Run("create, update and history", func(t *testing.T) {
+		repos := open(t)
+		class := createClass(t, repos, "maths")
+		student := createStudent(t, repos, "Ada")
+		created := createGrade(t, repos, class.Id, student.Id, 12)
+
+		got := getGrade(t, repos, created.Id)
+		if got.ClassId != class.Id || got.StudentId != student.Id || got.Value != 12 {
+			t.Errorf("Get() = %+v, want the created grade", got)
+		}
+
+		reason := "Re-marked"
+		update := *got
+		update.Value = 14
+		if _, err := repos.Grades.Update(&update, models.GradeChange{ChangedBy: "teacher", Reason: &reason}); err != nil {
+			t.Fatal(err)
+		}
+
+		if got := getGrade(t, repos, created.Id); got.Value != 14 {
+			t.Errorf("Get().Value = %v after Update(), want 14", got.Value)
+		}
+
+		versions, err := repos.Grades.History(created.Id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(versions) != 2 {
+			t.Fatalf("History() has %v versions, want 2", len(versions))
+		}
+		if v := versions[0]; v.Version != 1 || v.Value != 12 || v.ChangedBy != "teacher" || v.Reason != nil {
+			t.Errorf("History()[0] = %+v, want version 1 of value 12", v)
+		}
+		if v := versions[1]; v.Version != 2 || v.Value != 14 || v.Reason == nil || *v.Reason != reason {
+			t.Errorf("History()[1] = %+v, want version 2 of value 14", v)
+		}
+
+		update.StudentId = "another"
+		if _, err := repos.Grades.Update(&update, models.GradeChange{ChangedBy: "teacher"}); err == nil {
+			t.Error("Update() moved the grade to another student")
+		}
+	})
+
+	t.Run("unknown grade", func(t *testing.T) {
+		r := open(t).Grades
+
+		if _, err := r.Update(&models.Grade{BaseMetadata: models.BaseMetadata{Id: "unknown"}}, models.GradeChange{}); !errors.Is(err, gradeRepos.GradeDoesNotExist) {
+			t.Errorf("Update(unknown) = %v, want %v", err, gradeRepos.GradeDoesNotExist)
+		}
+		if _, err := r.History("unknown"); !errors.Is(err, gradeRepos.GradeDoesNotExist) {
+			t.Errorf("History(unknown) = %v, want %v", err, gradeRepos.GradeDoesNotExist)
+		}
+		if err := r.Delete(models.Grade{BaseMetadata: models.BaseMetadata{Id: "unknown"}}); !errors.Is(err, gradeRepos.GradeDoesNotExist) {
+			t.Errorf("Delete(unknown) = %v, want %v", err, gradeRepos.GradeDoesNotExist)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		repos := open(t)
+		class := createClass(t, repos, "maths")
+		student := createStudent(t, repos, "Ada")
+		grade := createGrade(t, repos, class.Id, student.Id, 12)
+		other := createGrade(t, repos, class.Id, student.Id, 15)
+
+		if err := repos.Grades.Delete(*grade); err != nil {
+			t.Fatal(err)
+		}
+
+		if got, err := repos.Grades.Get(grade.Id); err != nil || got != nil {
+			t.Errorf("Get() after Delete() = %v, %v, want nil, nil", got, err)
+		}
+		if _, err := repos.Grades.History(grade.Id); !errors.Is(err, gradeRepos.GradeDoesNotExist) {
+			t.Errorf("History() after Delete() = %v, want %v", err, gradeRepos.GradeDoesNotExist)
+		}
+		getGrade(t, repos, other.Id)
+		testCount(t, repos.Grades.Count, 1)
+	})
+
+	t.Run("count and paginate by class", func(t *testing.T) {
+		repos := open(t)
+		maths := createClass(t, repos, "maths")
+		history := createClass(t, repos, "history")
+		student := createStudent(t, repos, "Ada")
+		testCount(t, repos.Grades.Count, 0)
+
+		for i := 0; i < 5; i++ {
+			createGrade(t, repos, maths.Id, student.Id, i)
+		}
+		createGrade(t, repos, history.Id, student.Id, 20)
+
+		testCount(t, repos.Grades.Count, 6)
+		testCount(t, func() (int, error) { return repos.Grades.CountForClass(maths.Id) }, 5)
+		testCount(t, func() (int, error) { return repos.Grades.CountForClass("unknown") }, 0)
+		testPagination(t, 5, func(pq utils.PaginationQuery) ([]models.Grade, error) {
+			return repos.Grades.GetAll(maths.Id, pq)
+		}, func(g models.Grade) string { return g.Id })
+	})
+}
+
+// createGrade gives a student a grade in a class.
+func createGrade(t *testing.T, repos *storage.Repositories, classId string, studentId string, value int) *models.Grade {
+	t.Helper()
+
+	grade, err := repos.Grades.Create(models.Grade{ClassId: classId, StudentId: studentId, Value: value}, models.GradeChange{ChangedBy: "teacher"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return grade
+}
+
+// getGrade returns the grade with the given id, failing the test if there is
+// none.
+func getGrade(t *testing.T, repos *storage.Repositories, id string) *models.Grade {
+	t.Helper()
+
+	grade, err := repos.Grades.Get(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if grade == nil {
+		t.Fatalf("Get(%v) = nil, want the grade", id)
+	}
+
+	return grade
+}
@@ -0,0 +1,167 @@
+// Package repostest holds the behaviour every implementation of the class,
+// student, teacher and grade repositories must share, as tests that run
+// against any data store. Each repository package runs them against every
+// storage driver, so the drivers can't drift apart.
+package repostest
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/h4n-openschool/api/migrations"
+	classRepos "github.com/h4n-openschool/api/repos/classes"
+	gradeRepos "github.com/h4n-openschool/api/repos/grades"
+	studentRepos "github.com/h4n-openschool/api/repos/students"
+	teacherRepos "github.com/h4n-openschool/api/repos/teachers"
+	"github.com/h4n-openschool/api/storage"
+	"github.com/h4n-openschool/api/utils"
+	"github.com/lucsky/cuid"
+)
+
+// PostgresDsnEnv names the environment variable holding the connection string
+// of a PostgreSQL server to run the tests against. Tests using [Postgres] are
+// skipped when it is unset.
+const PostgresDsnEnv = "OPENSCHOOL_TEST_POSTGRES_DSN"
+
+// Open creates an empty data store for a single test, which is released once
+// the test finishes.
+type Open func(t *testing.T) *storage.Repositories
+
+// Memory opens empty in-memory repositories.
+func Memory(t *testing.T) *storage.Repositories {
+	return &storage.Repositories{
+		Classes:  classRepos.NewInMemoryClassRepositoryFrom(nil),
+		Students: studentRepos.NewInMemoryStudentRepositoryFrom(nil),
+		Teachers: teacherRepos.NewInMemoryTeacherRepositoryFrom(nil),
+		Grades:   gradeRepos.NewInMemoryGradeRepositoryFrom(nil, nil),
+		Driver:   storage.DriverMemory,
+	}
+}
+
+// Sqlite opens repositories on a new SQLite database file, migrated to the
+// latest version.
+func Sqlite(t *testing.T) *storage.Repositories {
+	t.Helper()
+
+	return migrated(t, storage.Config{Driver: storage.DriverSqlite, Path: filepath.Join(t.TempDir(), "openschool.db")})
+}
+
+// Postgres opens repositories on a new schema of the PostgreSQL server named
+// by [PostgresDsnEnv], migrated to the latest version. The schema is dropped
+// once the test finishes.
+func Postgres(t *testing.T) *storage.Repositories {
+	t.Helper()
+
+	dsn := os.Getenv(PostgresDsnEnv)
+	if dsn == "" {
+		t.Skipf("%v is not set", PostgresDsnEnv)
+	}
+
+	admin, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = admin.Close() })
+
+	schema := "test_" + cuid.New()
+	if _, err := admin.Exec(`CREATE SCHEMA ` + schema); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if _, err := admin.Exec(`DROP SCHEMA ` + schema + ` CASCADE`); err != nil {
+			t.Error(err)
+		}
+	})
+
+	// Unknown connection parameters are passed on as session settings.
+	if strings.Contains(dsn, "://") {
+		if strings.Contains(dsn, "?") {
+			dsn += "&search_path=" + schema
+		} else {
+			dsn += "?search_path=" + schema
+		}
+	} else {
+		dsn += " search_path=" + schema
+	}
+
+	return migrated(t, storage.Config{Driver: storage.DriverPostgres, DSN: dsn})
+}
+
+// migrated opens the repositories of a SQL driver and applies every
+// migration to their database.
+func migrated(t *testing.T, cfg storage.Config) *storage.Repositories {
+	t.Helper()
+
+	repos, err := storage.Open(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = repos.Close() })
+
+	m, err := migrations.NewMigrator(repos.DB, cfg.Driver)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Up(); err != nil {
+		t.Fatal(err)
+	}
+
+	return repos
+}
+
+// testPagination pages through count records with getAll, two at a time, and
+// checks every record is returned exactly once.
+func testPagination[T any](t *testing.T, count int, getAll func(pq utils.PaginationQuery) ([]T, error), id func(T) string) {
+	t.Helper()
+
+	pq := utils.NewPaginationQuery()
+	pq.PerPage = 2
+
+	seen := map[string]bool{}
+	for {
+		items, err := getAll(pq)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := count - len(seen)
+		if want > pq.PerPage {
+			want = pq.PerPage
+		}
+		if len(items) != want {
+			t.Fatalf("page %v has %v records, want %v", pq.Page, len(items), want)
+		}
+
+		for _, item := range items {
+			if seen[id(item)] {
+				t.Errorf("record %v returned again on page %v", id(item), pq.Page)
+			}
+			seen[id(item)] = true
+		}
+
+		if len(items) < pq.PerPage {
+			break
+		}
+		pq.Page++
+	}
+
+	if len(seen) != count {
+		t.Errorf("paged through %v records, want %v", len(seen), count)
+	}
+}
+
+// testCount checks that count reports want records.
+func testCount(t *testing.T, count func() (int, error), want int) {
+	t.Helper()
+
+	got, err := count()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("Count() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,111 @@
+package repostest
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/h4n-openschool/api/models"
+	studentRepos "github.com/h4n-openschool/api/repos/students"
+	"github.com/h4n-openschool/api/storage"
+)
+
+// TestStudentRepository checks the behaviour of the student repository opened
+// by open.
+func TestStudentRepository(t *testing.T, open Open) {
+	t.Run("get unknown", func(t *testing.T) {
+		r := open(t).Students
+
+		student, err := r.Get("unknown")
+		if err != nil || student != nil {
+			t.Errorf("Get(unknown) = %v, %v, want nil, nil", student, err)
+		}
+	})
+
+	t.Run("create, get and update", func(t *testing.T) {
+		repos := open(t)
+		created := createStudent(t, repos, "Ada")
+
+		got := getStudent(t, repos, created.Id)
+		if got.FullName != "Ada" {
+			t.Errorf("Get().FullName = %q, want %q", got.FullName, "Ada")
+		}
+
+		_, err := repos.Students.Update(&models.Student{BaseMetadata: models.BaseMetadata{Id: created.Id}, FullName: "Ada Lovelace"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got = getStudent(t, repos, created.Id)
+		if got.FullName != "Ada Lovelace" {
+			t.Errorf("Get().FullName = %q after Update(), want %q", got.FullName, "Ada Lovelace")
+		}
+	})
+
+	t.Run("unknown student", func(t *testing.T) {
+		r := open(t).Students
+
+		if _, err := r.Update(&models.Student{BaseMetadata: models.BaseMetadata{Id: "unknown"}}); !errors.Is(err, studentRepos.StudentDoesNotExist) {
+			t.Errorf("Update(unknown) = %v, want %v", err, studentRepos.StudentDoesNotExist)
+		}
+		if err := r.Delete(models.Student{BaseMetadata: models.BaseMetadata{Id: "unknown"}}); !errors.Is(err, studentRepos.StudentDoesNotExist) {
+			t.Errorf("Delete(unknown) = %v, want %v", err, studentRepos.StudentDoesNotExist)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		repos := open(t)
+		student := createStudent(t, repos, "Ada")
+		other := createStudent(t, repos, "Alan")
+
+		if err := repos.Students.Delete(*student); err != nil {
+			t.Fatal(err)
+		}
+
+		if got, err := repos.Students.Get(student.Id); err != nil || got != nil {
+			t.Errorf("Get() after Delete() = %v, %v, want nil, nil", got, err)
+		}
+		getStudent(t, repos, other.Id)
+		testCount(t, repos.Students.Count, 1)
+	})
+
+	t.Run("count and paginate", func(t *testing.T) {
+		repos := open(t)
+		testCount(t, repos.Students.Count, 0)
+
+		for i := 0; i < 5; i++ {
+			createStudent(t, repos, fmt.Sprintf("Student %v", i))
+		}
+
+		testCount(t, repos.Students.Count, 5)
+		testPagination(t, 5, repos.Students.GetAll, func(s models.Student) string { return s.Id })
+	})
+}
+
+// createStudent creates a student with the given name.
+func createStudent(t *testing.T, repos *storage.Repositories, name string) *models.Student {
+	t.Helper()
+
+	student, err := repos.Students.Create(models.Student{FullName: name})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return student
+}
+
+// getStudent returns the student with the given id, failing the test if there
+// is none.
+func getStudent(t *testing.T, repos *storage.Repositories, id string) *models.Student {
+	t.Helper()
+
+	student, err := repos.Students.Get(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if student == nil {
+		t.Fatalf("Get(%v) = nil, want the student", id)
+	}
+
+	return student
+}
@@ -0,0 +1,181 @@
+package repostest
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/h4n-openschool/api/models"
+	teacherRepos "github.com/h4n-openschool/api/repos/teachers"
+	"github.com/h4n-openschool/api/storage"
+)
+
+// TestTeacherRepository checks the behaviour of the teacher repository opened
+// by open.
+func TestTeacherRepository(t *testing.T, open Open) {
+	t.Run("get unknown", func(t *testing.T) {
+		r := open(t).Teachers
+
+		teacher, err := r.Get("unknown")
+		if err != nil || teacher != nil {
+			t.Errorf("Get(unknown) = %v, %v, want nil, nil", teacher, err)
+		}
+
+		teacher, err = r.GetByEmail("unknown@example.com")
+		if err != nil || teacher != nil {
+			t.Errorf("GetByEmail(unknown) = %v, %v, want nil, nil", teacher, err)
+		}
+	})
+
+	t.Run("create with defaults", func(t *testing.T) {
+		repos := open(t)
+		created := createTeacher(t, repos, "ada@example.com")
+
+		got, err := repos.Teachers.GetByEmail("ada@example.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got == nil || got.Id != created.Id {
+			t.Fatalf("GetByEmail() = %v, want the created teacher", got)
+		}
+		if got.Role != models.RoleTeacher || got.Status != models.TeacherActive {
+			t.Errorf("created teacher has role %q and status %q, want %q and %q", got.Role, got.Status, models.RoleTeacher, models.TeacherActive)
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		repos := open(t)
+		created := createTeacher(t, repos, "ada@example.com")
+
+		// An empty role and status leave the stored ones unchanged.
+		_, err := repos.Teachers.Update(&models.Teacher{
+			BaseMetadata: models.BaseMetadata{Id: created.Id},
+			FullName:     "Ada Lovelace",
+			Email:        "lovelace@example.com",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got := getTeacher(t, repos, created.Id)
+		if got.FullName != "Ada Lovelace" || got.Email != "lovelace@example.com" || got.Role != models.RoleTeacher || got.Status != models.TeacherActive {
+			t.Errorf("Get() = %+v after Update()", got)
+		}
+
+		if old, err := repos.Teachers.GetByEmail("ada@example.com"); err != nil || old != nil {
+			t.Errorf("GetByEmail(old email) = %v, %v, want nil, nil", old, err)
+		}
+	})
+
+	t.Run("set password hash", func(t *testing.T) {
+		repos := open(t)
+		created := createTeacher(t, repos, "ada@example.com")
+
+		if err := repos.Teachers.SetPasswordHash(created.Id, "hash"); err != nil {
+			t.Fatal(err)
+		}
+		if got := getTeacher(t, repos, created.Id); got.PasswordHash != "hash" {
+			t.Errorf("Get().PasswordHash = %q, want %q", got.PasswordHash, "hash")
+		}
+	})
+
+	t.Run("activate", func(t *testing.T) {
+		repos := open(t)
+		pending, err := repos.Teachers.Create(models.Teacher{FullName: "Ada", Email: "ada@example.com", Status: models.TeacherPending})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for i, want := range []bool{true, false} {
+			activated, err := repos.Teachers.Activate(pending.Id, fmt.Sprintf("hash-%v", i))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if activated != want {
+				t.Errorf("Activate() #%v = %v, want %v", i+1, activated, want)
+			}
+		}
+
+		got := getTeacher(t, repos, pending.Id)
+		if got.Status != models.TeacherActive || got.PasswordHash != "hash-0" {
+			t.Errorf("Get() = status %q, hash %q, want %q, %q", got.Status, got.PasswordHash, models.TeacherActive, "hash-0")
+		}
+
+		if activated, err := repos.Teachers.Activate("unknown", "hash"); err != nil || activated {
+			t.Errorf("Activate(unknown) = %v, %v, want false, nil", activated, err)
+		}
+	})
+
+	t.Run("unknown teacher", func(t *testing.T) {
+		r := open(t).Teachers
+
+		if _, err := r.Update(&models.Teacher{BaseMetadata: models.BaseMetadata{Id: "unknown"}}); !errors.Is(err, teacherRepos.TeacherDoesNotExist) {
+			t.Errorf("Update(unknown) = %v, want %v", err, teacherRepos.TeacherDoesNotExist)
+		}
+		if err := r.SetPasswordHash("unknown", "hash"); !errors.Is(err, teacherRepos.TeacherDoesNotExist) {
+			t.Errorf("SetPasswordHash(unknown) = %v, want %v", err, teacherRepos.TeacherDoesNotExist)
+		}
+		if err := r.Delete(models.Teacher{BaseMetadata: models.BaseMetadata{Id: "unknown"}}); !errors.Is(err, teacherRepos.TeacherDoesNotExist) {
+			t.Errorf("Delete(unknown) = %v, want %v", err, teacherRepos.TeacherDoesNotExist)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		repos := open(t)
+		teacher := createTeacher(t, repos, "ada@example.com")
+		other := createTeacher(t, repos, "alan@example.com")
+
+		if err := repos.Teachers.Delete(*teacher); err != nil {
+			t.Fatal(err)
+		}
+
+		if got, err := repos.Teachers.Get(teacher.Id); err != nil || got != nil {
+			t.Errorf("Get() after Delete() = %v, %v, want nil, nil", got, err)
+		}
+		if got, err := repos.Teachers.GetByEmail(teacher.Email); err != nil || got != nil {
+			t.Errorf("GetByEmail() after Delete() = %v, %v, want nil, nil", got, err)
+		}
+		getTeacher(t, repos, other.Id)
+		testCount(t, repos.Teachers.Count, 1)
+	})
+
+	t.Run("count and paginate", func(t *testing.T) {
+		repos := open(t)
+		testCount(t, repos.Teachers.Count, 0)
+
+		for i := 0; i < 5; i++ {
+			createTeacher(t, repos, fmt.Sprintf("teacher-%v@example.com", i))
+		}
+
+		testCount(t, repos.Teachers.Count, 5)
+		testPagination(t, 5, repos.Teachers.GetAll, func(teacher models.Teacher) string { return teacher.Id })
+	})
+}
+
+// createTeacher creates an active teacher with the given email.
+func createTeacher(t *testing.T, repos *storage.Repositories, email string) *models.Teacher {
+	t.Helper()
+
+	teacher, err := repos.Teachers.Create(models.Teacher{FullName: email, Email: email})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return teacher
+}
+
+// getTeacher returns the teacher with the given id, failing the test if there
+// is none.
+func getTeacher(t *testing.T, repos *storage.Repositories, id string) *models.Teacher {
+	t.Helper()
+
+	teacher, err := repos.Teachers.Get(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if teacher == nil {
+		t.Fatalf("Get(%v) = nil, want the teacher", id)
+	}
+
+	return teacher
+}
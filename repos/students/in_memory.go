@@ -2,6 +2,7 @@ package students
 
 import (
 	"errors"
+	"sync"
 	"time"
 
 	"github.com/go-faker/faker/v4"
@@ -15,92 +16,116 @@ var (
 	StudentDoesNotExist = errors.New("no existing student found by that id")
 )
 
-// InMemoryStudentRepository implements the [studentRepository] interface using an
-// in-memory slice of [models.Student] items.
+// InMemoryStudentRepository implements the [StudentRepository] interface using
+// an in-memory slice of [models.Student] items. It is safe for concurrent use.
 type InMemoryStudentRepository struct {
-	// Items is the slice of [models.Student] items stored in memory.
-	Items []models.Student
+	mu sync.RWMutex
+
+	// items is the slice of [models.Student] items stored in memory, in the
+	// order they were created.
+	items []models.Student
+
+	// index maps the id of every student to its position in items.
+	index map[string]int
 }
 
 // NewInMemoryStudentRepository creates a new instance of
-// [NewInMemoryStudentRepository]
-func NewInMemoryStudentRepository(cr classes.ClassRepository, itemCount int) InMemoryStudentRepository {
-  classes, _ := cr.GetAll(utils.NewPaginationQuery())
-
-  var items []models.Student
-  for _, c := range classes {
-    var classStudents []string
-
-    // Generate studentes in-memory to use with repo methods.
-    for i := 0; i < itemCount; i++ {
-      id := cuid.New()
-
-      items = append(items, models.Student{
-        BaseMetadata: models.BaseMetadata{
-          Id:        id,
-          CreatedAt: time.Now(),
-          UpdatedAt: time.Now(),
-        },
-        FullName: faker.FirstName() + " " + faker.LastName(),
-      })
-
-      classStudents = append(classStudents, id)
-    }
-
-    c.StudentIds = classStudents
-    _, _ = cr.Update(&c)
-  }
+// [InMemoryStudentRepository]
+func NewInMemoryStudentRepository(cr classes.ClassRepository, itemCount int) *InMemoryStudentRepository {
+	classes, _ := cr.GetAll(utils.NewPaginationQuery())
+
+	var items []models.Student
+	for _, c := range classes {
+		var classStudents []string
+
+		// Generate students in-memory to use with repo methods.
+		for i := 0; i < itemCount; i++ {
+			id := cuid.New()
+
+			items = append(items, models.Student{
+				BaseMetadata: models.BaseMetadata{
+					Id:        id,
+					CreatedAt: time.Now(),
+					UpdatedAt: time.Now(),
+				},
+				FullName: faker.FirstName() + " " + faker.LastName(),
+			})
+
+			classStudents = append(classStudents, id)
+		}
+
+		c.StudentIds = classStudents
+		_, _ = cr.Update(&c)
+	}
 
 	// Return the new repository to the caller
-	return InMemoryStudentRepository{Items: items}
+	r := &InMemoryStudentRepository{items: items}
+	r.reindex(0)
+
+	return r
+}
+
+// NewInMemoryStudentRepositoryFrom creates a new instance of
+// [InMemoryStudentRepository] holding the given items, for example ones
+// restored from a snapshot.
+func NewInMemoryStudentRepositoryFrom(items []models.Student) *InMemoryStudentRepository {
+	r := &InMemoryStudentRepository{items: append([]models.Student(nil), items...)}
+	r.reindex(0)
+
+	return r
+}
+
+// All returns every stored student in the order they were created.
+func (r *InMemoryStudentRepository) All() []models.Student {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]models.Student(nil), r.items...)
 }
 
 func (r *InMemoryStudentRepository) GetAll(pq utils.PaginationQuery) ([]models.Student, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	var items []models.Student
 
 	offset := pq.Offset()
-	for i := offset; i < (offset + pq.PerPage); i++ {
-		item := r.Items[i]
-		items = append(items, item)
+	for i := offset; i < (offset+pq.PerPage) && i < len(r.items); i++ {
+		items = append(items, r.items[i])
 	}
 
 	return items, nil
 }
 
 func (r *InMemoryStudentRepository) Get(id string) (*models.Student, error) {
-	var found *models.Student
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 
-	for _, v := range r.Items {
-		if v.Id == id {
-			found = &v
-			break
-		}
+	k, ok := r.index[id]
+	if !ok {
+		return nil, nil
 	}
 
-	return found, nil
+	found := r.items[k]
+	return &found, nil
 }
 
 func (r *InMemoryStudentRepository) Update(student *models.Student) (*models.Student, error) {
-	var found *models.Student
-
-	for k, v := range r.Items {
-		if v.Id == student.Id {
-      v.FullName = student.FullName
-			v.UpdatedAt = time.Now()
-
-			found = &v
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
-			r.Items[k] = *found
-
-			break
-		}
-	}
-
-	if found == nil {
+	k, ok := r.index[student.Id]
+	if !ok {
 		return nil, StudentDoesNotExist
 	}
 
-	return found, nil
+	v := r.items[k]
+	v.FullName = student.FullName
+	v.UpdatedAt = time.Now()
+
+	r.items[k] = v
+
+	return &v, nil
 }
 
 func (r *InMemoryStudentRepository) Create(student models.Student) (*models.Student, error) {
@@ -110,39 +135,49 @@ func (r *InMemoryStudentRepository) Create(student models.Student) (*models.Stud
 			CreatedAt: time.Now(),
 			UpdatedAt: time.Now(),
 		},
-    FullName: student.FullName,
+		FullName: student.FullName,
 	}
 
-	r.Items = append(r.Items, model)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.items = append(r.items, model)
+	r.index[model.Id] = len(r.items) - 1
 
 	return &model, nil
 }
 
 func (r *InMemoryStudentRepository) Delete(student models.Student) error {
-	var newItems []models.Student
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
-	var found *models.Student
-	for _, c := range r.Items {
-		if c.Id == student.Id {
-			found = &c
-			break
-		}
-	}
-	if found == nil {
+	k, ok := r.index[student.Id]
+	if !ok {
 		return StudentDoesNotExist
 	}
 
-	for _, c := range r.Items {
-		if c.Id != student.Id {
-			newItems = append(newItems, c)
-		}
-	}
-
-	r.Items = newItems
+	r.items = append(r.items[:k], r.items[k+1:]...)
+	delete(r.index, student.Id)
+	r.reindex(k)
 
 	return nil
 }
 
 func (r *InMemoryStudentRepository) Count() (int, error) {
-	return len(r.Items), nil
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.items), nil
+}
+
+// reindex rebuilds the positions of every item from position k onwards. The
+// caller must hold the write lock, or be the only user of the repository.
+func (r *InMemoryStudentRepository) reindex(k int) {
+	if r.index == nil {
+		r.index = make(map[string]int, len(r.items))
+	}
+
+	for i := k; i < len(r.items); i++ {
+		r.index[r.items[i].Id] = i
+	}
 }
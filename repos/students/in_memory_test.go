@@ -0,0 +1,67 @@
+package students
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/h4n-openschool/api/models"
+	"github.com/h4n-openschool/api/repos/classes"
+	"github.com/h4n-openschool/api/repos/racetest"
+	"github.com/h4n-openschool/api/repos/teachers"
+	"github.com/h4n-openschool/api/utils"
+)
+
+// TestInMemoryStudentRepositoryConcurrentUse calls every method with
+// [racetest.Run], each worker deleting the students it created.
+func TestInMemoryStudentRepositoryConcurrentUse(t *testing.T) {
+	cr := classes.NewInMemoryClassRepository(teachers.NewInMemoryTeacherRepository(2), 2)
+	r := NewInMemoryStudentRepository(cr, 5)
+
+	initial, err := r.Count()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	racetest.Run(func(w int, round int) {
+		created, err := r.Create(models.Student{FullName: fmt.Sprintf("Student %v", w)})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		name := fmt.Sprintf("Updated %v", w)
+		if _, err := r.Update(&models.Student{
+			BaseMetadata: models.BaseMetadata{Id: created.Id},
+			FullName:     name,
+		}); err != nil {
+			t.Error(err)
+		}
+
+		got, err := r.Get(created.Id)
+		if err != nil || got == nil || got.FullName != name {
+			t.Errorf("Get(%v) = %v, %v", created.Id, got, err)
+		}
+
+		if _, err := r.GetAll(utils.NewPaginationQuery()); err != nil {
+			t.Error(err)
+		}
+		if _, err := r.Count(); err != nil {
+			t.Error(err)
+		}
+		_ = r.All()
+
+		if err := r.Delete(*created); err != nil {
+			t.Error(err)
+		}
+	})
+
+	count, err := r.Count()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != initial {
+		t.Errorf("Count() = %v, want %v", count, initial)
+	}
+
+	racetest.CheckIndexed(t, r.All(), func(s models.Student) string { return s.Id }, r.Get)
+}
@@ -0,0 +1,19 @@
+package students_test
+
+import (
+	"testing"
+
+	"github.com/h4n-openschool/api/repos/repostest"
+)
+
+func TestInMemoryStudentRepository(t *testing.T) {
+	repostest.TestStudentRepository(t, repostest.Memory)
+}
+
+func TestSqliteStudentRepository(t *testing.T) {
+	repostest.TestStudentRepository(t, repostest.Sqlite)
+}
+
+func TestPostgresStudentRepository(t *testing.T) {
+	repostest.TestStudentRepository(t, repostest.Postgres)
+}
@@ -0,0 +1,141 @@
+package students
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/h4n-openschool/api/models"
+	"github.com/h4n-openschool/api/utils"
+	"github.com/lucsky/cuid"
+)
+
+const studentColumns = `id, full_name, created_at, updated_at`
+
+// SqlStudentRepository implements the [StudentRepository] interface on top of a
+// [sql.DB] connection or [sql.Tx]. Queries are written to run on both
+// PostgreSQL and SQLite.
+type SqlStudentRepository struct {
+	// DB is the database connection, or the transaction, used for every query.
+	DB utils.SqlConn
+}
+
+// NewSqlStudentRepository creates a new instance of [SqlStudentRepository]
+func NewSqlStudentRepository(db utils.SqlConn) *SqlStudentRepository {
+	return &SqlStudentRepository{DB: db}
+}
+
+func (r *SqlStudentRepository) GetAll(pq utils.PaginationQuery) ([]models.Student, error) {
+	rows, err := r.DB.Query(
+		`SELECT `+studentColumns+` FROM students ORDER BY created_at, id LIMIT $1 OFFSET $2`,
+		pq.PerPage, pq.Offset(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []models.Student
+	for rows.Next() {
+		student, err := scanStudent(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *student)
+	}
+
+	return items, rows.Err()
+}
+
+func (r *SqlStudentRepository) Get(id string) (*models.Student, error) {
+	row := r.DB.QueryRow(`SELECT `+studentColumns+` FROM students WHERE id = $1`, id)
+
+	student, err := scanStudent(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	return student, err
+}
+
+func (r *SqlStudentRepository) Update(student *models.Student) (*models.Student, error) {
+	now := time.Now()
+
+	res, err := r.DB.Exec(
+		`UPDATE students SET full_name = $1, updated_at = $2 WHERE id = $3`,
+		student.FullName, now, student.Id,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, StudentDoesNotExist
+	}
+
+	return r.Get(student.Id)
+}
+
+func (r *SqlStudentRepository) Create(student models.Student) (*models.Student, error) {
+	model := models.Student{
+		BaseMetadata: models.BaseMetadata{
+			Id:        cuid.New(),
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		},
+		FullName: student.FullName,
+	}
+
+	_, err := r.DB.Exec(
+		`INSERT INTO students (`+studentColumns+`) VALUES ($1, $2, $3, $4)`,
+		model.Id, model.FullName, model.CreatedAt, model.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model, nil
+}
+
+func (r *SqlStudentRepository) Delete(student models.Student) error {
+	res, err := r.DB.Exec(`DELETE FROM students WHERE id = $1`, student.Id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return StudentDoesNotExist
+	}
+
+	return nil
+}
+
+func (r *SqlStudentRepository) Count() (int, error) {
+	var count int
+	err := r.DB.QueryRow(`SELECT COUNT(*) FROM students`).Scan(&count)
+	return count, err
+}
+
+// scanner is satisfied by both [sql.Row] and [sql.Rows].
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanStudent(s scanner) (*models.Student, error) {
+	var student models.Student
+
+	err := s.Scan(&student.Id, &student.FullName, &student.CreatedAt, &student.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &student, nil
+}
@@ -3,37 +3,46 @@ package teachers
 import (
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/go-faker/faker/v4"
 	"github.com/h4n-openschool/api/models"
 	"github.com/h4n-openschool/api/utils"
 	"github.com/lucsky/cuid"
-	"golang.org/x/crypto/bcrypt"
 )
 
 var (
 	TeacherDoesNotExist = errors.New("no existing class found by that id")
 )
 
-// InMemoryTeacherRepository implements the [TeacherRepository] interface using an
-// in-memory slice of [models.Teacher] items.
+// InMemoryTeacherRepository implements the [TeacherRepository] interface using
+// an in-memory slice of [models.Teacher] items. It is safe for concurrent use.
 type InMemoryTeacherRepository struct {
-	// Items is the slice of [models.Teacher] items stored in memory.
-	Items []models.Teacher
+	mu sync.RWMutex
+
+	// items is the slice of [models.Teacher] items stored in memory, in the
+	// order they were created.
+	items []models.Teacher
+
+	// index maps the id of every teacher to its position in items.
+	index map[string]int
+
+	// emailIndex maps the email of every teacher to its position in items.
+	emailIndex map[string]int
 }
 
 // NewInMemoryTeacherRepository creates a new instance of
-// [NewInMemoryTeacherRepository]
-func NewInMemoryTeacherRepository(itemCount int) InMemoryTeacherRepository {
+// [InMemoryTeacherRepository]
+func NewInMemoryTeacherRepository(itemCount int) *InMemoryTeacherRepository {
 	var items []models.Teacher
 
-	password, err := bcrypt.GenerateFromPassword([]byte("password"), 10)
+	password, err := utils.HashPassword("password")
 	if err != nil {
 		panic(err)
 	}
 
-	// Generate classes in-memory to use with repo methods.
+	// Generate teachers in-memory to use with repo methods.
 	for i := 0; i < itemCount; i++ {
 		id := cuid.New()
 
@@ -45,88 +54,158 @@ func NewInMemoryTeacherRepository(itemCount int) InMemoryTeacherRepository {
 			},
 			FullName:     fmt.Sprintf("%v %v", faker.FirstName(), faker.LastName()),
 			Email:        faker.Email(),
-			PasswordHash: string(password),
+			PasswordHash: password,
+			Role:         models.RoleTeacher,
+			Status:       models.TeacherActive,
 		})
 	}
 
-  items = append(items, models.Teacher{
-    BaseMetadata: models.BaseMetadata{
-      Id: "clb3x2ugq0004txk80dyoemxa",
-      CreatedAt: time.Now(),
-      UpdatedAt: time.Now(),
-    },
-    FullName: "John Doe",
-    Email: "john.doe@school.edu",
-    PasswordHash: string(password),
-  })
+	items = append(items, models.Teacher{
+		BaseMetadata: models.BaseMetadata{
+			Id:        "clb3x2ugq0004txk80dyoemxa",
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		},
+		FullName:     "John Doe",
+		Email:        "john.doe@school.edu",
+		PasswordHash: password,
+		Role:         models.RoleAdmin,
+		Status:       models.TeacherActive,
+	})
 
 	// Return the new repository to the caller
-	return InMemoryTeacherRepository{Items: items}
+	r := &InMemoryTeacherRepository{items: items}
+	r.reindex(0)
+
+	return r
+}
+
+// NewInMemoryTeacherRepositoryFrom creates a new instance of
+// [InMemoryTeacherRepository] holding the given items, for example ones
+// restored from a snapshot.
+func NewInMemoryTeacherRepositoryFrom(items []models.Teacher) *InMemoryTeacherRepository {
+	r := &InMemoryTeacherRepository{items: append([]models.Teacher(nil), items...)}
+	r.reindex(0)
+
+	// Snapshots taken before roles and statuses existed only hold active
+	// teachers.
+	for k := range r.items {
+		if r.items[k].Role == "" {
+			r.items[k].Role = models.RoleTeacher
+		}
+		if r.items[k].Status == "" {
+			r.items[k].Status = models.TeacherActive
+		}
+	}
+
+	return r
+}
+
+// All returns every stored teacher in the order they were created.
+func (r *InMemoryTeacherRepository) All() []models.Teacher {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]models.Teacher(nil), r.items...)
 }
 
 func (r *InMemoryTeacherRepository) GetAll(pq utils.PaginationQuery) ([]models.Teacher, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	var items []models.Teacher
 
 	offset := pq.Offset()
-	for i := offset; i < (offset + pq.PerPage); i++ {
-		if len(r.Items) <= i {
-			break
-		}
-		item := r.Items[i]
-		items = append(items, item)
+	for i := offset; i < (offset+pq.PerPage) && i < len(r.items); i++ {
+		items = append(items, r.items[i])
 	}
 
 	return items, nil
 }
 
 func (r *InMemoryTeacherRepository) Get(id string) (*models.Teacher, error) {
-	var found *models.Teacher
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 
-	for _, v := range r.Items {
-		if v.Id == id {
-			found = &v
-			break
-		}
+	k, ok := r.index[id]
+	if !ok {
+		return nil, nil
 	}
 
-	return found, nil
+	found := r.items[k]
+	return &found, nil
 }
 
 func (r *InMemoryTeacherRepository) GetByEmail(email string) (*models.Teacher, error) {
-	var found *models.Teacher
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 
-	for _, v := range r.Items {
-		if v.Email == email {
-			found = &v
-			break
-		}
+	k, ok := r.emailIndex[email]
+	if !ok {
+		return nil, nil
 	}
 
-	return found, nil
+	found := r.items[k]
+	return &found, nil
 }
 
 func (r *InMemoryTeacherRepository) Update(teacher *models.Teacher) (*models.Teacher, error) {
-	var found *models.Teacher
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
-	for k, v := range r.Items {
-		if v.Id == teacher.Id {
-			v.FullName = teacher.FullName
-			v.Email = teacher.Email
-			v.UpdatedAt = time.Now()
+	k, ok := r.index[teacher.Id]
+	if !ok {
+		return nil, TeacherDoesNotExist
+	}
+
+	v := r.items[k]
+	delete(r.emailIndex, v.Email)
 
-			found = &v
+	v.FullName = teacher.FullName
+	v.Email = teacher.Email
+	if teacher.Role != "" {
+		v.Role = teacher.Role
+	}
+	if teacher.Status != "" {
+		v.Status = teacher.Status
+	}
+	v.UpdatedAt = time.Now()
 
-			r.Items[k] = *found
+	r.items[k] = v
+	r.emailIndex[v.Email] = k
 
-			break
-		}
+	return &v, nil
+}
+
+func (r *InMemoryTeacherRepository) SetPasswordHash(id string, hash string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	k, ok := r.index[id]
+	if !ok {
+		return TeacherDoesNotExist
 	}
 
-	if found == nil {
-		return nil, TeacherDoesNotExist
+	r.items[k].PasswordHash = hash
+	r.items[k].UpdatedAt = time.Now()
+
+	return nil
+}
+
+func (r *InMemoryTeacherRepository) Activate(id string, hash string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	k, ok := r.index[id]
+	if !ok || r.items[k].Status != models.TeacherPending {
+		return false, nil
 	}
 
-	return found, nil
+	r.items[k].PasswordHash = hash
+	r.items[k].Status = models.TeacherActive
+	r.items[k].UpdatedAt = time.Now()
+
+	return true, nil
 }
 
 func (r *InMemoryTeacherRepository) Create(teacher models.Teacher) (*models.Teacher, error) {
@@ -136,40 +215,65 @@ func (r *InMemoryTeacherRepository) Create(teacher models.Teacher) (*models.Teac
 			CreatedAt: time.Now(),
 			UpdatedAt: time.Now(),
 		},
-		FullName: teacher.FullName,
-		Email:    teacher.Email,
+		FullName:     teacher.FullName,
+		Email:        teacher.Email,
+		PasswordHash: teacher.PasswordHash,
+		Role:         teacher.Role,
+		Status:       teacher.Status,
+	}
+	if model.Role == "" {
+		model.Role = models.RoleTeacher
+	}
+	if model.Status == "" {
+		model.Status = models.TeacherActive
 	}
 
-	r.Items = append(r.Items, model)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.items = append(r.items, model)
+	r.index[model.Id] = len(r.items) - 1
+	r.emailIndex[model.Email] = len(r.items) - 1
 
 	return &model, nil
 }
 
 func (r *InMemoryTeacherRepository) Delete(teacher models.Teacher) error {
-	var newItems []models.Teacher
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
-	var found *models.Teacher
-	for _, c := range r.Items {
-		if c.Id == teacher.Id {
-			found = &c
-			break
-		}
-	}
-	if found == nil {
+	k, ok := r.index[teacher.Id]
+	if !ok {
 		return TeacherDoesNotExist
 	}
 
-	for _, c := range r.Items {
-		if c.Id != teacher.Id {
-			newItems = append(newItems, c)
-		}
-	}
-
-	r.Items = newItems
+	delete(r.index, teacher.Id)
+	delete(r.emailIndex, r.items[k].Email)
+	r.items = append(r.items[:k], r.items[k+1:]...)
+	r.reindex(k)
 
 	return nil
 }
 
 func (r *InMemoryTeacherRepository) Count() (int, error) {
-	return len(r.Items), nil
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.items), nil
+}
+
+// reindex rebuilds the positions of every item from position k onwards. The
+// caller must hold the write lock, or be the only user of the repository.
+func (r *InMemoryTeacherRepository) reindex(k int) {
+	if r.index == nil {
+		r.index = make(map[string]int, len(r.items))
+	}
+	if r.emailIndex == nil {
+		r.emailIndex = make(map[string]int, len(r.items))
+	}
+
+	for i := k; i < len(r.items); i++ {
+		r.index[r.items[i].Id] = i
+		r.emailIndex[r.items[i].Email] = i
+	}
 }
@@ -0,0 +1,106 @@
+package teachers
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/h4n-openschool/api/models"
+	"github.com/h4n-openschool/api/repos/racetest"
+	"github.com/h4n-openschool/api/utils"
+)
+
+// TestInMemoryTeacherRepositoryConcurrentUse calls every method with
+// [racetest.Run], activating every new teacher twice at once.
+func TestInMemoryTeacherRepositoryConcurrentUse(t *testing.T) {
+	r := NewInMemoryTeacherRepository(5)
+
+	initial, err := r.Count()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var activations int32
+	racetest.Run(func(w int, round int) {
+		created, err := r.Create(models.Teacher{
+			FullName: fmt.Sprintf("Teacher %v", w),
+			Email:    fmt.Sprintf("teacher-%v@school.edu", w),
+			Status:   models.TeacherPending,
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		// Activating twice at once must only succeed once.
+		var activating sync.WaitGroup
+		for i := 0; i < 2; i++ {
+			activating.Add(1)
+			go func() {
+				defer activating.Done()
+				activated, err := r.Activate(created.Id, "hash")
+				if err != nil {
+					t.Error(err)
+				}
+				if activated {
+					atomic.AddInt32(&activations, 1)
+				}
+			}()
+		}
+		activating.Wait()
+
+		if err := r.SetPasswordHash(created.Id, "other"); err != nil {
+			t.Error(err)
+		}
+
+		email := fmt.Sprintf("updated-%v@school.edu", w)
+		if _, err := r.Update(&models.Teacher{
+			BaseMetadata: models.BaseMetadata{Id: created.Id},
+			FullName:     created.FullName,
+			Email:        email,
+		}); err != nil {
+			t.Error(err)
+		}
+
+		got, err := r.GetByEmail(email)
+		if err != nil || got == nil || got.Id != created.Id {
+			t.Errorf("GetByEmail(%v) = %v, %v", email, got, err)
+		}
+		if got, err := r.Get(created.Id); err != nil || got == nil || got.PasswordHash != "other" {
+			t.Errorf("Get(%v) = %v, %v", created.Id, got, err)
+		}
+
+		if _, err := r.GetAll(utils.NewPaginationQuery()); err != nil {
+			t.Error(err)
+		}
+		if _, err := r.Count(); err != nil {
+			t.Error(err)
+		}
+		_ = r.All()
+
+		if err := r.Delete(*created); err != nil {
+			t.Error(err)
+		}
+	})
+
+	if activations != racetest.Workers*racetest.Rounds {
+		t.Errorf("%v activations succeeded, want %v", activations, racetest.Workers*racetest.Rounds)
+	}
+
+	count, err := r.Count()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != initial {
+		t.Errorf("Count() = %v, want %v", count, initial)
+	}
+
+	// Teachers are indexed by email too.
+	racetest.CheckIndexed(t, r.All(), func(teacher models.Teacher) string { return teacher.Id }, r.Get)
+	for _, teacher := range r.All() {
+		if got, _ := r.GetByEmail(teacher.Email); got == nil || got.Id != teacher.Id {
+			t.Errorf("GetByEmail(%v) = %v after concurrent deletes", teacher.Email, got)
+		}
+	}
+}
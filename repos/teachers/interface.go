@@ -18,12 +18,23 @@ type TeacherRepository interface {
 	GetByEmail(email string) (*models.Teacher, error)
 
 	// Update takes a teacher object that has been mutated and persists it to the
-	// data store, returning the modified object and possibly an error.
+	// data store, returning the modified object and possibly an error. An empty
+	// Role or Status leaves the stored one unchanged.
 	Update(teacher *models.Teacher) (*models.Teacher, error)
 
+	// SetPasswordHash replaces the password hash of the teacher with the given
+	// ID. Hashes are created with [utils.HashPassword].
+	SetPasswordHash(id string, hash string) error
+
+	// Activate sets the password hash of a pending teacher and makes them
+	// active. It reports false if the teacher does not exist or is not
+	// pending, in which case nothing is changed.
+	Activate(id string, hash string) (bool, error)
+
 	// Create takes a teacher object that has been populated with data and creates
 	// a record for it in the data store, returning the filled record and
-	// possibly an error.
+	// possibly an error. An empty Role defaults to [models.RoleTeacher], and an
+	// empty Status to [models.TeacherActive].
 	Create(teacher models.Teacher) (*models.Teacher, error)
 
 	// Delete takes a teacher object that includes at least an ID and deletes the
@@ -0,0 +1,19 @@
+package teachers_test
+
+import (
+	"testing"
+
+	"github.com/h4n-openschool/api/repos/repostest"
+)
+
+func TestInMemoryTeacherRepository(t *testing.T) {
+	repostest.TestTeacherRepository(t, repostest.Memory)
+}
+
+func TestSqliteTeacherRepository(t *testing.T) {
+	repostest.TestTeacherRepository(t, repostest.Sqlite)
+}
+
+func TestPostgresTeacherRepository(t *testing.T) {
+	repostest.TestTeacherRepository(t, repostest.Postgres)
+}
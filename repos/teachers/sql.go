@@ -0,0 +1,202 @@
+package teachers
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/h4n-openschool/api/models"
+	"github.com/h4n-openschool/api/utils"
+	"github.com/lucsky/cuid"
+)
+
+const teacherColumns = `id, full_name, email, password_hash, role, status, created_at, updated_at`
+
+// SqlTeacherRepository implements the [TeacherRepository] interface on top of a
+// [sql.DB] connection or [sql.Tx]. Queries are written to run on both
+// PostgreSQL and SQLite.
+type SqlTeacherRepository struct {
+	// DB is the database connection, or the transaction, used for every query.
+	DB utils.SqlConn
+}
+
+// NewSqlTeacherRepository creates a new instance of [SqlTeacherRepository]
+func NewSqlTeacherRepository(db utils.SqlConn) *SqlTeacherRepository {
+	return &SqlTeacherRepository{DB: db}
+}
+
+func (r *SqlTeacherRepository) GetAll(pq utils.PaginationQuery) ([]models.Teacher, error) {
+	rows, err := r.DB.Query(
+		`SELECT `+teacherColumns+` FROM teachers ORDER BY created_at, id LIMIT $1 OFFSET $2`,
+		pq.PerPage, pq.Offset(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []models.Teacher
+	for rows.Next() {
+		teacher, err := scanTeacher(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *teacher)
+	}
+
+	return items, rows.Err()
+}
+
+func (r *SqlTeacherRepository) Get(id string) (*models.Teacher, error) {
+	return r.getBy(`id`, id)
+}
+
+func (r *SqlTeacherRepository) GetByEmail(email string) (*models.Teacher, error) {
+	return r.getBy(`email`, email)
+}
+
+func (r *SqlTeacherRepository) Update(teacher *models.Teacher) (*models.Teacher, error) {
+	now := time.Now()
+
+	// An empty role or status leaves the stored one untouched.
+	res, err := r.DB.Exec(
+		`UPDATE teachers SET full_name = $1, email = $2, role = COALESCE(NULLIF($3, ''), role), status = COALESCE(NULLIF($4, ''), status), updated_at = $5 WHERE id = $6`,
+		teacher.FullName, teacher.Email, string(teacher.Role), string(teacher.Status), now, teacher.Id,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, TeacherDoesNotExist
+	}
+
+	return r.Get(teacher.Id)
+}
+
+func (r *SqlTeacherRepository) SetPasswordHash(id string, hash string) error {
+	res, err := r.DB.Exec(
+		`UPDATE teachers SET password_hash = $1, updated_at = $2 WHERE id = $3`,
+		hash, time.Now(), id,
+	)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return TeacherDoesNotExist
+	}
+
+	return nil
+}
+
+func (r *SqlTeacherRepository) Activate(id string, hash string) (bool, error) {
+	res, err := r.DB.Exec(
+		`UPDATE teachers SET password_hash = $1, status = $2, updated_at = $3 WHERE id = $4 AND status = $5`,
+		hash, string(models.TeacherActive), time.Now(), id, string(models.TeacherPending),
+	)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
+func (r *SqlTeacherRepository) Create(teacher models.Teacher) (*models.Teacher, error) {
+	model := models.Teacher{
+		BaseMetadata: models.BaseMetadata{
+			Id:        cuid.New(),
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		},
+		FullName:     teacher.FullName,
+		Email:        teacher.Email,
+		PasswordHash: teacher.PasswordHash,
+		Role:         teacher.Role,
+		Status:       teacher.Status,
+	}
+	if model.Role == "" {
+		model.Role = models.RoleTeacher
+	}
+	if model.Status == "" {
+		model.Status = models.TeacherActive
+	}
+
+	_, err := r.DB.Exec(
+		`INSERT INTO teachers (`+teacherColumns+`) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		model.Id, model.FullName, model.Email, model.PasswordHash, string(model.Role), string(model.Status), model.CreatedAt, model.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model, nil
+}
+
+func (r *SqlTeacherRepository) Delete(teacher models.Teacher) error {
+	res, err := r.DB.Exec(`DELETE FROM teachers WHERE id = $1`, teacher.Id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return TeacherDoesNotExist
+	}
+
+	return nil
+}
+
+func (r *SqlTeacherRepository) Count() (int, error) {
+	var count int
+	err := r.DB.QueryRow(`SELECT COUNT(*) FROM teachers`).Scan(&count)
+	return count, err
+}
+
+// getBy returns the single teacher whose column matches value. The column is
+// always a constant chosen by this file, never user input.
+func (r *SqlTeacherRepository) getBy(column string, value string) (*models.Teacher, error) {
+	row := r.DB.QueryRow(`SELECT `+teacherColumns+` FROM teachers WHERE `+column+` = $1`, value)
+
+	teacher, err := scanTeacher(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	return teacher, err
+}
+
+// scanner is satisfied by both [sql.Row] and [sql.Rows].
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanTeacher(s scanner) (*models.Teacher, error) {
+	var teacher models.Teacher
+
+	err := s.Scan(
+		&teacher.Id, &teacher.FullName, &teacher.Email, &teacher.PasswordHash,
+		&teacher.Role, &teacher.Status, &teacher.CreatedAt, &teacher.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &teacher, nil
+}
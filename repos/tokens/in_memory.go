@@ -0,0 +1,258 @@
+package tokens
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/h4n-openschool/api/models"
+)
+
+// InMemoryTokenRepository implements the [TokenRepository] interface using
+// in-memory maps. It is safe for concurrent use.
+type InMemoryTokenRepository struct {
+	mu sync.Mutex
+
+	// refreshTokens maps the hash of every refresh token to the token.
+	refreshTokens map[string]models.RefreshToken
+
+	// revoked maps revoked access token and family ids to the time until
+	// which they stay revoked.
+	revoked map[string]time.Time
+
+	// oneTimeTokens maps the hash of every one-time token to the token.
+	oneTimeTokens map[string]models.OneTimeToken
+}
+
+// NewInMemoryTokenRepository creates a new instance of
+// [InMemoryTokenRepository]
+func NewInMemoryTokenRepository() *InMemoryTokenRepository {
+	return NewInMemoryTokenRepositoryFrom(State{})
+}
+
+// NewInMemoryTokenRepositoryFrom creates a new instance of
+// [InMemoryTokenRepository] holding the given state, such as that of a
+// snapshot.
+func NewInMemoryTokenRepositoryFrom(state State) *InMemoryTokenRepository {
+	r := &InMemoryTokenRepository{
+		refreshTokens: map[string]models.RefreshToken{},
+		revoked:       map[string]time.Time{},
+		oneTimeTokens: map[string]models.OneTimeToken{},
+	}
+
+	for _, token := range state.RefreshTokens {
+		r.refreshTokens[token.Hash] = token
+	}
+	for id, until := range state.Revoked {
+		r.revoked[id] = until
+	}
+	for _, token := range state.OneTimeTokens {
+		r.oneTimeTokens[token.Hash] = token
+	}
+
+	return r
+}
+
+func (r *InMemoryTokenRepository) CreateRefreshToken(token models.RefreshToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.prune(time.Now())
+	r.refreshTokens[token.Hash] = token
+
+	return nil
+}
+
+func (r *InMemoryTokenRepository) GetRefreshToken(hash string) (*models.RefreshToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	token, ok := r.refreshTokens[hash]
+	if !ok {
+		return nil, nil
+	}
+
+	return &token, nil
+}
+
+func (r *InMemoryTokenRepository) UseRefreshToken(hash string, at time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	token, ok := r.refreshTokens[hash]
+	if !ok || token.UsedAt != nil || token.RevokedAt != nil {
+		return false, nil
+	}
+
+	token.UsedAt = &at
+	r.refreshTokens[hash] = token
+
+	return true, nil
+}
+
+func (r *InMemoryTokenRepository) RevokeFamily(familyId string, until time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.revokeFamilies(func(token models.RefreshToken) bool {
+		return token.FamilyId == familyId
+	}, until)
+	r.revoke(familyId, until)
+
+	return nil
+}
+
+func (r *InMemoryTokenRepository) RevokeUserSessions(userId string, until time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.revokeFamilies(func(token models.RefreshToken) bool {
+		return token.UserId == userId
+	}, until)
+
+	return nil
+}
+
+func (r *InMemoryTokenRepository) RevokeAccessToken(tokenId string, until time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.revoke(tokenId, until)
+	return nil
+}
+
+func (r *InMemoryTokenRepository) UseAccessToken(tokenId string, until time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if current, ok := r.revoked[tokenId]; ok && time.Now().Before(current) {
+		return false, nil
+	}
+
+	r.revoke(tokenId, until)
+	return true, nil
+}
+
+func (r *InMemoryTokenRepository) IsRevoked(tokenId string, familyId string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for _, id := range []string{tokenId, familyId} {
+		if until, ok := r.revoked[id]; ok && id != "" && now.Before(until) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (r *InMemoryTokenRepository) CreateOneTimeToken(token models.OneTimeToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.prune(time.Now())
+	r.oneTimeTokens[token.Hash] = token
+
+	return nil
+}
+
+func (r *InMemoryTokenRepository) UseOneTimeToken(hash string, purpose string, at time.Time) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	token, ok := r.oneTimeTokens[hash]
+	if !ok || token.Purpose != purpose || token.UsedAt != nil || !at.Before(token.ExpiresAt) {
+		return "", nil
+	}
+
+	token.UsedAt = &at
+	r.oneTimeTokens[hash] = token
+
+	return token.UserId, nil
+}
+
+func (r *InMemoryTokenRepository) State() (State, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	state := State{
+		RefreshTokens: make([]models.RefreshToken, 0, len(r.refreshTokens)),
+		Revoked:       make(map[string]time.Time, len(r.revoked)),
+		OneTimeTokens: make([]models.OneTimeToken, 0, len(r.oneTimeTokens)),
+	}
+
+	for _, token := range r.refreshTokens {
+		state.RefreshTokens = append(state.RefreshTokens, token)
+	}
+	for id, until := range r.revoked {
+		state.Revoked[id] = until
+	}
+	for _, token := range r.oneTimeTokens {
+		state.OneTimeTokens = append(state.OneTimeTokens, token)
+	}
+
+	sort.Slice(state.RefreshTokens, func(i, j int) bool {
+		return state.RefreshTokens[i].Hash < state.RefreshTokens[j].Hash
+	})
+	sort.Slice(state.OneTimeTokens, func(i, j int) bool {
+		return state.OneTimeTokens[i].Hash < state.OneTimeTokens[j].Hash
+	})
+
+	return state, nil
+}
+
+// revokeFamilies revokes the refresh tokens matching match, along with every
+// other token of their families. The caller must hold the lock.
+func (r *InMemoryTokenRepository) revokeFamilies(match func(models.RefreshToken) bool, until time.Time) {
+	families := map[string]bool{}
+	for _, token := range r.refreshTokens {
+		if match(token) {
+			families[token.FamilyId] = true
+		}
+	}
+
+	now := time.Now()
+	for hash, token := range r.refreshTokens {
+		if families[token.FamilyId] && token.RevokedAt == nil {
+			token.RevokedAt = &now
+			r.refreshTokens[hash] = token
+		}
+	}
+
+	for familyId := range families {
+		r.revoke(familyId, until)
+	}
+}
+
+// revoke records id as revoked until the given time, keeping the later time
+// if it already was. The caller must hold the lock.
+func (r *InMemoryTokenRepository) revoke(id string, until time.Time) {
+	if current, ok := r.revoked[id]; !ok || until.After(current) {
+		r.revoked[id] = until
+	}
+
+	r.prune(time.Now())
+}
+
+// prune drops expired tokens and revocations, which can no longer affect any
+// request. The caller must hold the lock.
+func (r *InMemoryTokenRepository) prune(now time.Time) {
+	for hash, token := range r.refreshTokens {
+		if now.After(token.ExpiresAt) {
+			delete(r.refreshTokens, hash)
+		}
+	}
+
+	for hash, token := range r.oneTimeTokens {
+		if now.After(token.ExpiresAt) {
+			delete(r.oneTimeTokens, hash)
+		}
+	}
+
+	for id, until := range r.revoked {
+		if now.After(until) {
+			delete(r.revoked, id)
+		}
+	}
+}
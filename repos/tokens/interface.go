@@ -0,0 +1,73 @@
+package tokens
+
+import (
+	"time"
+
+	"github.com/h4n-openschool/api/models"
+)
+
+// TokenRepository defines a common interface for storing refresh tokens,
+// revoked access tokens and one-time tokens.
+type TokenRepository interface {
+	// CreateRefreshToken stores a newly issued refresh token.
+	CreateRefreshToken(token models.RefreshToken) error
+
+	// GetRefreshToken returns a single refresh token by its hash, or nil if
+	// there is none.
+	GetRefreshToken(hash string) (*models.RefreshToken, error)
+
+	// UseRefreshToken marks a refresh token as exchanged. It reports false if
+	// the token had already been used or revoked, in which case nothing is
+	// changed. Checking and marking happen atomically, so a token can't be
+	// exchanged twice by concurrent requests.
+	UseRefreshToken(hash string, at time.Time) (bool, error)
+
+	// RevokeFamily revokes every refresh token of a family, and the access
+	// tokens issued for it until the given time.
+	RevokeFamily(familyId string, until time.Time) error
+
+	// RevokeUserSessions revokes every family of refresh tokens issued to a
+	// teacher, like [TokenRepository.RevokeFamily] does for a single one.
+	RevokeUserSessions(userId string, until time.Time) error
+
+	// RevokeAccessToken revokes a single access token by its `jti` claim until
+	// the given time, which should be when the token expires.
+	RevokeAccessToken(tokenId string, until time.Time) error
+
+	// UseAccessToken revokes a single access token like
+	// [TokenRepository.RevokeAccessToken], for tokens that may only be used
+	// once. It reports false if the token had already been revoked, in which
+	// case nothing is changed. Like [TokenRepository.UseRefreshToken],
+	// checking and revoking happen atomically.
+	UseAccessToken(tokenId string, until time.Time) (bool, error)
+
+	// IsRevoked reports whether an access token has been revoked, either by
+	// its own id or by the family it was issued for.
+	IsRevoked(tokenId string, familyId string) (bool, error)
+
+	// CreateOneTimeToken stores a newly issued one-time token.
+	CreateOneTimeToken(token models.OneTimeToken) error
+
+	// UseOneTimeToken marks the one-time token with the given hash and purpose
+	// as used, and returns the id of the teacher it was issued to. It returns
+	// an empty id if there is no such token, or it has expired or been used
+	// already. Like [TokenRepository.UseRefreshToken], checking and marking
+	// happen atomically.
+	UseOneTimeToken(hash string, purpose string, at time.Time) (string, error)
+
+	// State returns every stored token and revocation.
+	State() (State, error)
+}
+
+// State is everything a [TokenRepository] stores, such as for a snapshot.
+type State struct {
+	// RefreshTokens are the issued refresh tokens.
+	RefreshTokens []models.RefreshToken
+
+	// Revoked maps revoked access token and family ids to the time until which
+	// they stay revoked.
+	Revoked map[string]time.Time
+
+	// OneTimeTokens are the issued one-time tokens.
+	OneTimeTokens []models.OneTimeToken
+}
@@ -0,0 +1,320 @@
+package tokens
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/h4n-openschool/api/models"
+	"github.com/h4n-openschool/api/utils"
+)
+
+// SqlTokenRepository implements the [TokenRepository] interface on top of the
+// `refresh_tokens` and `revoked_tokens` tables. Queries are written to run on
+// both PostgreSQL and SQLite.
+type SqlTokenRepository struct {
+	// DB is the database connection, or the transaction, used for every query.
+	DB utils.SqlConn
+}
+
+// NewSqlTokenRepository creates a new instance of [SqlTokenRepository]
+func NewSqlTokenRepository(db utils.SqlConn) *SqlTokenRepository {
+	return &SqlTokenRepository{DB: db}
+}
+
+func (r *SqlTokenRepository) CreateRefreshToken(token models.RefreshToken) error {
+	tx, err := utils.BeginSql(r.DB)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Expired rows can no longer affect any request, so clear them out while
+	// we are writing anyway.
+	now := time.Now()
+	if _, err := tx.Exec(`DELETE FROM refresh_tokens WHERE expires_at < $1`, now); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM revoked_tokens WHERE revoked_until < $1`, now); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(
+		`INSERT INTO refresh_tokens (hash, family_id, user_id, expires_at, created_at) VALUES ($1, $2, $3, $4, $5)`,
+		token.Hash, token.FamilyId, token.UserId, token.ExpiresAt, token.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *SqlTokenRepository) GetRefreshToken(hash string) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+	var usedAt, revokedAt sql.NullTime
+
+	err := r.DB.QueryRow(
+		`SELECT hash, family_id, user_id, expires_at, used_at, revoked_at, created_at FROM refresh_tokens WHERE hash = $1`,
+		hash,
+	).Scan(&token.Hash, &token.FamilyId, &token.UserId, &token.ExpiresAt, &usedAt, &revokedAt, &token.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if usedAt.Valid {
+		token.UsedAt = &usedAt.Time
+	}
+	if revokedAt.Valid {
+		token.RevokedAt = &revokedAt.Time
+	}
+
+	return &token, nil
+}
+
+func (r *SqlTokenRepository) UseRefreshToken(hash string, at time.Time) (bool, error) {
+	res, err := r.DB.Exec(
+		`UPDATE refresh_tokens SET used_at = $1 WHERE hash = $2 AND used_at IS NULL AND revoked_at IS NULL`,
+		at, hash,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
+func (r *SqlTokenRepository) RevokeFamily(familyId string, until time.Time) error {
+	tx, err := utils.BeginSql(r.DB)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(
+		`UPDATE refresh_tokens SET revoked_at = $1 WHERE family_id = $2 AND revoked_at IS NULL`,
+		time.Now(), familyId,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := revoke(tx, familyId, until); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *SqlTokenRepository) RevokeUserSessions(userId string, until time.Time) error {
+	tx, err := utils.BeginSql(r.DB)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`SELECT DISTINCT family_id FROM refresh_tokens WHERE user_id = $1`, userId)
+	if err != nil {
+		return err
+	}
+
+	var families []string
+	for rows.Next() {
+		var familyId string
+		if err := rows.Scan(&familyId); err != nil {
+			rows.Close()
+			return err
+		}
+		families = append(families, familyId)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(
+		`UPDATE refresh_tokens SET revoked_at = $1 WHERE user_id = $2 AND revoked_at IS NULL`,
+		time.Now(), userId,
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, familyId := range families {
+		if err := revoke(tx, familyId, until); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *SqlTokenRepository) RevokeAccessToken(tokenId string, until time.Time) error {
+	return revoke(r.DB, tokenId, until)
+}
+
+func (r *SqlTokenRepository) UseAccessToken(tokenId string, until time.Time) (bool, error) {
+	// Revocations which have run out may not have been cleared yet, and don't
+	// count as a use.
+	res, err := r.DB.Exec(
+		`INSERT INTO revoked_tokens (id, revoked_until) VALUES ($1, $2)
+		ON CONFLICT (id) DO UPDATE SET revoked_until = excluded.revoked_until
+		WHERE revoked_tokens.revoked_until <= $3`,
+		tokenId, until, time.Now(),
+	)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
+func (r *SqlTokenRepository) IsRevoked(tokenId string, familyId string) (bool, error) {
+	var count int
+	err := r.DB.QueryRow(
+		`SELECT COUNT(*) FROM revoked_tokens WHERE id IN ($1, $2) AND id <> '' AND revoked_until > $3`,
+		tokenId, familyId, time.Now(),
+	).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (r *SqlTokenRepository) CreateOneTimeToken(token models.OneTimeToken) error {
+	tx, err := utils.BeginSql(r.DB)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM one_time_tokens WHERE expires_at < $1`, time.Now()); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(
+		`INSERT INTO one_time_tokens (hash, purpose, user_id, expires_at, created_at) VALUES ($1, $2, $3, $4, $5)`,
+		token.Hash, token.Purpose, token.UserId, token.ExpiresAt, token.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *SqlTokenRepository) UseOneTimeToken(hash string, purpose string, at time.Time) (string, error) {
+	var userId string
+	err := r.DB.QueryRow(
+		`UPDATE one_time_tokens SET used_at = $1
+		WHERE hash = $2 AND purpose = $3 AND used_at IS NULL AND expires_at > $1
+		RETURNING user_id`,
+		at, hash, purpose,
+	).Scan(&userId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+
+	return userId, err
+}
+
+func (r *SqlTokenRepository) State() (State, error) {
+	state := State{Revoked: map[string]time.Time{}}
+
+	rows, err := r.DB.Query(
+		`SELECT hash, family_id, user_id, expires_at, used_at, revoked_at, created_at FROM refresh_tokens ORDER BY hash`,
+	)
+	if err != nil {
+		return State{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var token models.RefreshToken
+		var usedAt, revokedAt sql.NullTime
+		if err := rows.Scan(&token.Hash, &token.FamilyId, &token.UserId, &token.ExpiresAt, &usedAt, &revokedAt, &token.CreatedAt); err != nil {
+			return State{}, err
+		}
+		if usedAt.Valid {
+			token.UsedAt = &usedAt.Time
+		}
+		if revokedAt.Valid {
+			token.RevokedAt = &revokedAt.Time
+		}
+		state.RefreshTokens = append(state.RefreshTokens, token)
+	}
+	if err := rows.Err(); err != nil {
+		return State{}, err
+	}
+
+	rows, err = r.DB.Query(`SELECT id, revoked_until FROM revoked_tokens`)
+	if err != nil {
+		return State{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		var until time.Time
+		if err := rows.Scan(&id, &until); err != nil {
+			return State{}, err
+		}
+		state.Revoked[id] = until
+	}
+	if err := rows.Err(); err != nil {
+		return State{}, err
+	}
+
+	rows, err = r.DB.Query(
+		`SELECT hash, purpose, user_id, expires_at, used_at, created_at FROM one_time_tokens ORDER BY hash`,
+	)
+	if err != nil {
+		return State{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var token models.OneTimeToken
+		var usedAt sql.NullTime
+		if err := rows.Scan(&token.Hash, &token.Purpose, &token.UserId, &token.ExpiresAt, &usedAt, &token.CreatedAt); err != nil {
+			return State{}, err
+		}
+		if usedAt.Valid {
+			token.UsedAt = &usedAt.Time
+		}
+		state.OneTimeTokens = append(state.OneTimeTokens, token)
+	}
+
+	return state, rows.Err()
+}
+
+// execer is satisfied by both [sql.DB] and [sql.Tx].
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+// revoke records id as revoked until the given time, keeping the later time
+// if it already was.
+func revoke(db execer, id string, until time.Time) error {
+	_, err := db.Exec(
+		`INSERT INTO revoked_tokens (id, revoked_until) VALUES ($1, $2)
+		ON CONFLICT (id) DO UPDATE SET revoked_until = excluded.revoked_until
+		WHERE revoked_tokens.revoked_until < excluded.revoked_until`,
+		id, until,
+	)
+	return err
+}
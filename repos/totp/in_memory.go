@@ -0,0 +1,140 @@
+package totp
+
+import (
+	"sync"
+	"time"
+
+	"github.com/h4n-openschool/api/models"
+)
+
+// InMemoryTotpRepository implements the [TotpRepository] interface using an
+// in-memory map. It is safe for concurrent use.
+type InMemoryTotpRepository struct {
+	mu sync.Mutex
+
+	// items maps the id of every enrolled teacher to their settings.
+	items map[string]models.Totp
+}
+
+// NewInMemoryTotpRepository creates a new instance of
+// [InMemoryTotpRepository]
+func NewInMemoryTotpRepository() *InMemoryTotpRepository {
+	return &InMemoryTotpRepository{items: map[string]models.Totp{}}
+}
+
+// NewInMemoryTotpRepositoryFrom creates a new instance of
+// [InMemoryTotpRepository] holding the given items, for example ones restored
+// from a snapshot.
+func NewInMemoryTotpRepositoryFrom(items []models.Totp) *InMemoryTotpRepository {
+	r := NewInMemoryTotpRepository()
+	for _, item := range items {
+		r.items[item.UserId] = copyTotp(item)
+	}
+
+	return r
+}
+
+func (r *InMemoryTotpRepository) All() ([]models.Totp, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	items := make([]models.Totp, 0, len(r.items))
+	for _, item := range r.items {
+		items = append(items, copyTotp(item))
+	}
+
+	return items, nil
+}
+
+func (r *InMemoryTotpRepository) Get(userId string) (*models.Totp, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	item, ok := r.items[userId]
+	if !ok {
+		return nil, nil
+	}
+
+	found := copyTotp(item)
+	return &found, nil
+}
+
+func (r *InMemoryTotpRepository) Enroll(userId string, secret string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if item, ok := r.items[userId]; ok && item.Enabled() {
+		return TotpAlreadyEnabled
+	}
+
+	r.items[userId] = models.Totp{UserId: userId, Secret: secret, CreatedAt: at}
+	return nil
+}
+
+func (r *InMemoryTotpRepository) Confirm(userId string, recoveryHashes []string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	item, ok := r.items[userId]
+	if !ok {
+		return TotpNotEnrolled
+	}
+
+	item.ConfirmedAt = &at
+	item.RecoveryCodes = nil
+	for _, hash := range recoveryHashes {
+		item.RecoveryCodes = append(item.RecoveryCodes, models.RecoveryCode{Hash: hash})
+	}
+
+	r.items[userId] = item
+	return nil
+}
+
+func (r *InMemoryTotpRepository) UseStep(userId string, step int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	item, ok := r.items[userId]
+	if !ok || step <= item.LastStep {
+		return false, nil
+	}
+
+	item.LastStep = step
+	r.items[userId] = item
+
+	return true, nil
+}
+
+func (r *InMemoryTotpRepository) UseRecoveryCode(userId string, hash string, at time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	item, ok := r.items[userId]
+	if !ok {
+		return false, nil
+	}
+
+	for k, code := range item.RecoveryCodes {
+		if code.Hash == hash && code.UsedAt == nil {
+			item.RecoveryCodes[k].UsedAt = &at
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (r *InMemoryTotpRepository) Delete(userId string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.items, userId)
+	return nil
+}
+
+// copyTotp copies the recovery codes of item, so the stored ones can't be
+// changed through a returned item.
+func copyTotp(item models.Totp) models.Totp {
+	item.RecoveryCodes = append([]models.RecoveryCode(nil), item.RecoveryCodes...)
+	return item
+}
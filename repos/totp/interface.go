@@ -0,0 +1,46 @@
+package totp
+
+import (
+	"errors"
+	"time"
+
+	"github.com/h4n-openschool/api/models"
+)
+
+var (
+	TotpAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	TotpNotEnrolled    = errors.New("two-factor authentication has not been set up")
+)
+
+// TotpRepository defines a common interface for querying the two-factor
+// authentication settings of teachers.
+type TotpRepository interface {
+	// Get returns the settings of a teacher, or nil if they never enrolled.
+	Get(userId string) (*models.Totp, error)
+
+	// Enroll stores a new, unconfirmed secret for a teacher, replacing any
+	// earlier unconfirmed one. It returns [TotpAlreadyEnabled] if the teacher
+	// has confirmed a secret already.
+	Enroll(userId string, secret string, at time.Time) error
+
+	// Confirm enforces two-factor authentication for a teacher who enrolled,
+	// replacing their recovery codes with the given hashes. It returns
+	// [TotpNotEnrolled] if there is nothing to confirm.
+	Confirm(userId string, recoveryHashes []string, at time.Time) error
+
+	// UseStep records that the code of a time step was used. It reports false
+	// if the step is not later than the last one used, in which case nothing
+	// is changed. Checking and recording happen atomically.
+	UseStep(userId string, step int64) (bool, error)
+
+	// UseRecoveryCode marks the unused recovery code with the given hash as
+	// used. It reports false if there is no such code.
+	UseRecoveryCode(userId string, hash string, at time.Time) (bool, error)
+
+	// Delete removes the settings and recovery codes of a teacher, disabling
+	// two-factor authentication.
+	Delete(userId string) error
+
+	// All returns the settings of every enrolled teacher.
+	All() ([]models.Totp, error)
+}
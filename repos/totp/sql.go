@@ -0,0 +1,192 @@
+package totp
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/h4n-openschool/api/models"
+	"github.com/h4n-openschool/api/utils"
+)
+
+// SqlTotpRepository implements the [TotpRepository] interface on top of the
+// `teacher_totp` and `recovery_codes` tables. Queries are written to run on
+// both PostgreSQL and SQLite.
+type SqlTotpRepository struct {
+	// DB is the database connection, or the transaction, used for every query.
+	DB utils.SqlConn
+}
+
+// NewSqlTotpRepository creates a new instance of [SqlTotpRepository]
+func NewSqlTotpRepository(db utils.SqlConn) *SqlTotpRepository {
+	return &SqlTotpRepository{DB: db}
+}
+
+func (r *SqlTotpRepository) Get(userId string) (*models.Totp, error) {
+	item := models.Totp{UserId: userId}
+	var confirmedAt sql.NullTime
+
+	err := r.DB.QueryRow(
+		`SELECT secret, confirmed_at, last_step, created_at FROM teacher_totp WHERE teacher_id = $1`,
+		userId,
+	).Scan(&item.Secret, &confirmedAt, &item.LastStep, &item.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if confirmedAt.Valid {
+		item.ConfirmedAt = &confirmedAt.Time
+	}
+
+	rows, err := r.DB.Query(`SELECT hash, used_at FROM recovery_codes WHERE teacher_id = $1`, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var code models.RecoveryCode
+		var usedAt sql.NullTime
+		if err := rows.Scan(&code.Hash, &usedAt); err != nil {
+			return nil, err
+		}
+		if usedAt.Valid {
+			code.UsedAt = &usedAt.Time
+		}
+		item.RecoveryCodes = append(item.RecoveryCodes, code)
+	}
+
+	return &item, rows.Err()
+}
+
+func (r *SqlTotpRepository) All() ([]models.Totp, error) {
+	rows, err := r.DB.Query(`SELECT teacher_id FROM teacher_totp ORDER BY teacher_id`)
+	if err != nil {
+		return nil, err
+	}
+
+	var userIds []string
+	for rows.Next() {
+		var userId string
+		if err := rows.Scan(&userId); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		userIds = append(userIds, userId)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	items := make([]models.Totp, 0, len(userIds))
+	for _, userId := range userIds {
+		item, err := r.Get(userId)
+		if err != nil {
+			return nil, err
+		}
+		if item != nil {
+			items = append(items, *item)
+		}
+	}
+
+	return items, nil
+}
+
+func (r *SqlTotpRepository) Enroll(userId string, secret string, at time.Time) error {
+	// Confirmed secrets are left alone: the upsert changes nothing for them.
+	res, err := r.DB.Exec(
+		`INSERT INTO teacher_totp (teacher_id, secret, created_at) VALUES ($1, $2, $3)
+		ON CONFLICT (teacher_id) DO UPDATE SET secret = excluded.secret, last_step = 0, created_at = excluded.created_at
+		WHERE teacher_totp.confirmed_at IS NULL`,
+		userId, secret, at,
+	)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return TotpAlreadyEnabled
+	}
+
+	return nil
+}
+
+func (r *SqlTotpRepository) Confirm(userId string, recoveryHashes []string, at time.Time) error {
+	tx, err := utils.BeginSql(r.DB)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`UPDATE teacher_totp SET confirmed_at = $1 WHERE teacher_id = $2`, at, userId)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return TotpNotEnrolled
+	}
+
+	if _, err := tx.Exec(`DELETE FROM recovery_codes WHERE teacher_id = $1`, userId); err != nil {
+		return err
+	}
+
+	for _, hash := range recoveryHashes {
+		if _, err := tx.Exec(`INSERT INTO recovery_codes (teacher_id, hash) VALUES ($1, $2)`, userId, hash); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *SqlTotpRepository) UseStep(userId string, step int64) (bool, error) {
+	res, err := r.DB.Exec(
+		`UPDATE teacher_totp SET last_step = $1 WHERE teacher_id = $2 AND last_step < $1`,
+		step, userId,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
+func (r *SqlTotpRepository) UseRecoveryCode(userId string, hash string, at time.Time) (bool, error) {
+	res, err := r.DB.Exec(
+		`UPDATE recovery_codes SET used_at = $1 WHERE teacher_id = $2 AND hash = $3 AND used_at IS NULL`,
+		at, userId, hash,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
+func (r *SqlTotpRepository) Delete(userId string) error {
+	_, err := r.DB.Exec(`DELETE FROM teacher_totp WHERE teacher_id = $1`, userId)
+	return err
+}
@@ -0,0 +1,133 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// CertificateReloader serves a certificate loaded from files, and reloads it
+// once they change, so renewed certificates are picked up without a restart.
+// Use its GetCertificate method in a [tls.Config].
+type CertificateReloader struct {
+	certFile string
+	keyFile  string
+	logger   *zap.Logger
+
+	mu   sync.RWMutex
+	cert *tls.Certificate
+
+	// stamp identifies the versions of the files the certificate was loaded
+	// from.
+	stamp string
+}
+
+// NewCertificateReloader loads the certificate and private key from PEM
+// files, failing if they can't be used.
+func NewCertificateReloader(certFile string, keyFile string, logger *zap.Logger) (*CertificateReloader, error) {
+	r := &CertificateReloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+		logger:   logger.Named("tls"),
+	}
+
+	if _, err := r.reload(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// GetCertificate returns the certificate most recently loaded.
+func (r *CertificateReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.cert, nil
+}
+
+// Run checks the files for changes every interval until ctx is cancelled.
+// When the new files can't be used, for example because only one of them has
+// been replaced yet, the previous certificate is kept and loading is tried
+// again on the next check. interval must be positive.
+func (r *CertificateReloader) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			reloaded, err := r.reload()
+			if err != nil {
+				r.logger.Sugar().Errorw("failed to reload certificate", "cert", r.certFile, "error", err)
+				continue
+			}
+			if reloaded {
+				r.logger.Sugar().Infow("reloaded certificate", "cert", r.certFile, "notAfter", r.notAfter())
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// reload loads the certificate again if its files changed since it was last
+// loaded, reporting whether it did.
+func (r *CertificateReloader) reload() (bool, error) {
+	stamp, err := fileStamp(r.certFile, r.keyFile)
+	if err != nil {
+		return false, err
+	}
+
+	r.mu.RLock()
+	unchanged := stamp == r.stamp
+	r.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return false, err
+	}
+	if cert.Leaf, err = x509.ParseCertificate(cert.Certificate[0]); err != nil {
+		return false, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cert = &cert
+	r.stamp = stamp
+
+	return true, nil
+}
+
+func (r *CertificateReloader) notAfter() time.Time {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.cert.Leaf.NotAfter
+}
+
+// fileStamp describes the current versions of files by their size and
+// modification time. Symbolic links are followed, so files swapped by
+// changing a link, as in mounted Kubernetes secrets, count as changed.
+func fileStamp(paths ...string) (string, error) {
+	var stamp string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return "", err
+		}
+		stamp += fmt.Sprintf("%v:%v:%v;", path, info.Size(), info.ModTime().UnixNano())
+	}
+
+	return stamp, nil
+}
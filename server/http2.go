@@ -0,0 +1,98 @@
+package server
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/net/http2"
+)
+
+// http2Preface is what clients start HTTP/2 connections with.
+const http2Preface = http2.ClientPreface
+
+// configureHTTP2 sets up the server connections speaking HTTP/2 are handed
+// over to. Their timeouts apply to every stream rather than every connection,
+// and errors are logged like the rest of the server's.
+func (s *Server) configureHTTP2() error {
+	s.http2Base = &http.Server{
+		ReadTimeout:  durationOr(s.ReadTimeout, DefaultReadTimeout),
+		WriteTimeout: durationOr(s.WriteTimeout, DefaultWriteTimeout),
+		IdleTimeout:  durationOr(s.IdleTimeout, DefaultIdleTimeout),
+		ErrorLog:     zap.NewStdLog(s.Logger),
+	}
+	s.http2 = &http2.Server{}
+
+	return http2.ConfigureServer(s.http2Base, s.http2)
+}
+
+// serveHTTP2 serves c over HTTP/2 until either side closes it. Whatever reader
+// has already read from c is served first.
+func (s *Server) serveHTTP2(c net.Conn, reader *bufio.Reader) {
+	s.setHTTP2(c)
+
+	// Deadlines are managed per stream from now on.
+	if err := c.SetDeadline(time.Time{}); err != nil {
+		s.Logger.Sugar().Errorf("failed to clear deadline: %v", err.Error())
+		return
+	}
+
+	// TLS connections are handed over as they are, since the HTTP/2 server
+	// checks their state. Nothing has been read from them yet.
+	conn := c
+	if reader.Buffered() > 0 {
+		conn = &bufferedConn{Conn: c, reader: reader}
+	}
+
+	s.http2.ServeConn(conn, &http2.ServeConnOpts{
+		BaseConfig: s.http2Base,
+		Handler:    s.http2Handler(c),
+	})
+}
+
+// http2Handler wraps the server's handler for the streams of an HTTP/2
+// connection, applying the same checks as HTTP/1 requests get.
+func (s *Server) http2Handler(c net.Conn) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.beginStream(c) {
+			defer s.endStream(c)
+		}
+
+		if res := validRequest(r); res != nil {
+			for k, v := range res.Header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(res.StatusCode)
+			return
+		}
+
+		s.Handler.ServeHTTP(w, r)
+	})
+}
+
+// hasHTTP2Preface reports whether a connection starts with the HTTP/2
+// connection preface. It only waits for more of it as long as what has
+// arrived matches, so HTTP/1 requests aren't held up.
+func hasHTTP2Preface(reader *bufio.Reader) bool {
+	for n := 1; n <= len(http2Preface); n++ {
+		b, err := reader.Peek(n)
+		if err != nil || b[n-1] != http2Preface[n-1] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// bufferedConn is a connection whose reads start with what has already been
+// buffered from it.
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
+}
@@ -1,18 +1,69 @@
 package server
 
-import "net/http"
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
 
+// bufferSize is how much of a response body is held back before the headers
+// are sent. Responses that fit are sent with a Content-Length, larger ones are
+// streamed with chunked transfer encoding, unless the handler set a
+// Content-Length itself.
+const bufferSize = 4 << 10
+
+// OSResponseWriter writes a response straight to the connection, implementing
+// [http.Flusher] and [http.Hijacker] on top of [http.ResponseWriter].
 type OSResponseWriter struct {
-	body       []byte
-	statusSet  bool
-	statusCode int
+	conn net.Conn
+	rw   *bufio.ReadWriter
+	req  *http.Request
+
 	header     http.Header
+	statusCode int
+
+	// close is whether the connection is closed after the response, which
+	// is announced in its headers.
+	close bool
+
+	// buf holds the start of the body until the headers are sent.
+	buf []byte
+
+	// committed is whether the headers have been sent.
+	committed bool
+
+	// chunked is whether the body is sent with chunked transfer encoding.
+	chunked bool
+
+	// contentLength is the length of the body announced in the headers, or
+	// -1 if it wasn't.
+	contentLength int64
+
+	// written is the number of body bytes sent so far.
+	written int64
+
+	hijacked bool
+
+	// onFlush is called whenever the handler flushes, to extend the write
+	// deadline of streamed responses.
+	onFlush func()
 }
 
-func NewOSResponseWriter() *OSResponseWriter {
+// NewOSResponseWriter creates a writer for the response to req, sent over
+// conn through rw. The connection is announced to be closed after the
+// response when close is true.
+func NewOSResponseWriter(conn net.Conn, rw *bufio.ReadWriter, req *http.Request, close bool) *OSResponseWriter {
 	return &OSResponseWriter{
-		statusSet: false,
-		header:    http.Header{},
+		conn:          conn,
+		rw:            rw,
+		req:           req,
+		header:        http.Header{},
+		close:         close,
+		contentLength: -1,
 	}
 }
 
@@ -20,17 +71,230 @@ func (w *OSResponseWriter) Header() http.Header {
 	return w.header
 }
 
+func (w *OSResponseWriter) WriteHeader(statusCode int) {
+	if w.statusCode != 0 || w.hijacked {
+		return
+	}
+
+	w.statusCode = statusCode
+}
+
 func (w *OSResponseWriter) Write(b []byte) (int, error) {
-	if !w.statusSet {
-		w.WriteHeader(200)
+	if w.hijacked {
+		return 0, http.ErrHijacked
+	}
+
+	w.WriteHeader(http.StatusOK)
+
+	if !w.bodyAllowed() {
+		return 0, http.ErrBodyNotAllowed
+	}
+
+	if !w.committed {
+		if len(w.buf)+len(b) <= bufferSize {
+			w.buf = append(w.buf, b...)
+			return len(b), nil
+		}
+
+		if err := w.commit(false); err != nil {
+			return 0, err
+		}
+	}
+
+	return w.writeBody(b)
+}
+
+// Flush sends the headers and everything written so far to the client.
+func (w *OSResponseWriter) Flush() {
+	if w.hijacked {
+		return
 	}
 
-	w.body = b
+	w.WriteHeader(http.StatusOK)
+
+	if !w.committed {
+		if err := w.commit(false); err != nil {
+			return
+		}
+	}
 
-	return 0, nil
+	if w.rw.Flush() == nil && w.onFlush != nil {
+		w.onFlush()
+	}
 }
 
-func (w *OSResponseWriter) WriteHeader(statusCode int) {
-	w.statusCode = statusCode
-	w.statusSet = true
+// Hijack hands the connection over to the caller, for example to speak
+// WebSocket after answering an upgrade request. The server no longer reads
+// from, writes to, or closes the connection, and its deadlines are cleared.
+func (w *OSResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if w.hijacked {
+		return nil, nil, http.ErrHijacked
+	}
+	if w.committed {
+		return nil, nil, errors.New("the response has already been sent")
+	}
+
+	if err := w.conn.SetDeadline(time.Time{}); err != nil {
+		return nil, nil, err
+	}
+
+	w.hijacked = true
+	return w.conn, w.rw, nil
+}
+
+// Hijacked reports whether the connection has been taken over by the handler.
+func (w *OSResponseWriter) Hijacked() bool {
+	return w.hijacked
+}
+
+// Committed reports whether the headers of the response have been sent.
+func (w *OSResponseWriter) Committed() bool {
+	return w.committed
+}
+
+// SetClose asks for the connection to be closed after the response. It only
+// has an effect until the headers have been sent.
+func (w *OSResponseWriter) SetClose() {
+	if !w.committed {
+		w.close = true
+	}
+}
+
+// Closing reports whether the connection must be closed after the response,
+// because it was announced, or the body sent doesn't match the announced
+// length.
+func (w *OSResponseWriter) Closing() bool {
+	return w.close || (w.contentLength >= 0 && !w.chunked && w.written != w.contentLength && w.bodyAllowed() && !w.isHead())
+}
+
+// Finish completes the response once the handler has returned, sending what
+// is still buffered. It doesn't flush the underlying writer.
+func (w *OSResponseWriter) Finish() error {
+	if w.hijacked {
+		return nil
+	}
+
+	w.WriteHeader(http.StatusOK)
+
+	if !w.committed {
+		if err := w.commit(true); err != nil {
+			return err
+		}
+	}
+
+	if w.chunked {
+		_, err := w.rw.WriteString("0\r\n\r\n")
+		return err
+	}
+
+	return nil
+}
+
+// commit sends the status line and headers, followed by the buffered start
+// of the body. When final is true, the buffer holds the whole body.
+func (w *OSResponseWriter) commit(final bool) error {
+	w.committed = true
+
+	h := w.header.Clone()
+	h.Del("Transfer-Encoding")
+
+	if w.bodyAllowed() || w.isHead() {
+		if cl := h.Get("Content-Length"); cl != "" {
+			n, err := strconv.ParseInt(cl, 10, 64)
+			if err != nil || n < 0 {
+				h.Del("Content-Length")
+			} else {
+				w.contentLength = n
+			}
+		}
+
+		switch {
+		case w.contentLength >= 0:
+		case final:
+			w.contentLength = int64(len(w.buf))
+			h.Set("Content-Length", strconv.Itoa(len(w.buf)))
+		case w.isHead():
+			// There is no body to frame, and its length is unknown.
+		default:
+			w.chunked = true
+			h.Set("Transfer-Encoding", "chunked")
+		}
+	} else {
+		h.Del("Content-Length")
+	}
+
+	if w.close {
+		h.Set("Connection", "close")
+	}
+
+	if _, err := fmt.Fprintf(w.rw, "HTTP/1.1 %d %s\r\n", w.statusCode, http.StatusText(w.statusCode)); err != nil {
+		return err
+	}
+	if err := h.Write(w.rw); err != nil {
+		return err
+	}
+	if _, err := w.rw.WriteString("\r\n"); err != nil {
+		return err
+	}
+
+	buf := w.buf
+	w.buf = nil
+	if len(buf) == 0 || !w.bodyAllowed() {
+		return nil
+	}
+
+	_, err := w.writeBody(buf)
+	return err
+}
+
+// writeBody sends part of the body, once the headers have been sent.
+func (w *OSResponseWriter) writeBody(b []byte) (int, error) {
+	if len(b) == 0 {
+		return 0, nil
+	}
+
+	if w.contentLength >= 0 && !w.chunked && w.written+int64(len(b)) > w.contentLength {
+		return 0, http.ErrContentLength
+	}
+
+	if w.isHead() {
+		w.written += int64(len(b))
+		return len(b), nil
+	}
+
+	if w.chunked {
+		if _, err := fmt.Fprintf(w.rw, "%x\r\n", len(b)); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := w.rw.Write(b)
+	w.written += int64(n)
+	if err != nil {
+		return n, err
+	}
+
+	if w.chunked {
+		if _, err := w.rw.WriteString("\r\n"); err != nil {
+			return n, err
+		}
+	}
+
+	return n, nil
+}
+
+func (w *OSResponseWriter) isHead() bool {
+	return w.req.Method == http.MethodHead
+}
+
+// bodyAllowed reports whether the response may have a body.
+func (w *OSResponseWriter) bodyAllowed() bool {
+	switch {
+	case w.statusCode >= 100 && w.statusCode < 200:
+		return false
+	case w.statusCode == http.StatusNoContent, w.statusCode == http.StatusNotModified:
+		return false
+	}
+
+	return true
 }
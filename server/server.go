@@ -2,14 +2,44 @@ package server
 
 import (
 	"bufio"
-	"bytes"
+	"context"
 	"crypto/tls"
+	"errors"
 	"io"
 	"net"
 	"net/http"
+	"sync"
 	"time"
 
 	"go.uber.org/zap"
+	"golang.org/x/net/http2"
+)
+
+const (
+	// DefaultReadTimeout bounds how long reading a request, body included,
+	// may take.
+	DefaultReadTimeout = 5 * time.Second
+
+	// DefaultWriteTimeout bounds how long handling a request and writing its
+	// response may take, from the moment the request has been read.
+	DefaultWriteTimeout = 30 * time.Second
+
+	// DefaultIdleTimeout is how long a connection is kept open waiting for
+	// the next request.
+	DefaultIdleTimeout = 60 * time.Second
+
+	// DefaultGracePeriod is how long requests in flight when shutting down
+	// may take to finish.
+	DefaultGracePeriod = 20 * time.Second
+
+	// acceptRetryDelay is how long accepting pauses after failing, so errors
+	// such as running out of file descriptors don't spin the loop.
+	acceptRetryDelay = 50 * time.Millisecond
+
+	// maxDrainBytes is how much of a request body left unread by the handler
+	// is discarded to reuse the connection. Connections with more left are
+	// closed instead.
+	maxDrainBytes = 256 << 10
 )
 
 type Server struct {
@@ -17,9 +47,59 @@ type Server struct {
 	Handler http.Handler
 	TLS     *tls.Config
 	Logger  *zap.Logger
+
+	// ReadTimeout, WriteTimeout and IdleTimeout apply to every request of a
+	// connection. Zero values use DefaultReadTimeout, DefaultWriteTimeout and
+	// DefaultIdleTimeout. Handlers streaming a response get a fresh write
+	// timeout every time they flush.
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	IdleTimeout  time.Duration
+
+	// GracePeriod is how long requests in flight when shutting down may take
+	// to finish. A zero value uses DefaultGracePeriod.
+	GracePeriod time.Duration
+
+	// H2C enables HTTP/2 over cleartext connections for clients that know
+	// the server speaks it, recognised by the connection preface they start
+	// with. Connections over TLS negotiate HTTP/2 with ALPN regardless.
+	H2C bool
+
+	mu sync.Mutex
+
+	// conns holds the state of every open connection.
+	conns map[net.Conn]*connState
+
+	// wg counts the goroutines serving connections, and the handlers of
+	// HTTP/2 requests, which may outlive theirs.
+	wg sync.WaitGroup
+
+	shuttingDown bool
+
+	// http2 serves the connections speaking HTTP/2, which are configured
+	// through http2Base.
+	http2     *http2.Server
+	http2Base *http.Server
+}
+
+// connState is what the server knows about an open connection.
+type connState struct {
+	// requests is the number of requests being served, which can only be
+	// more than one over HTTP/2.
+	requests int
+
+	// http2 is whether the connection speaks HTTP/2.
+	http2 bool
 }
 
-func (s *Server) Listen() error {
+// ShutdownTimedOut is returned by [Server.Serve] when requests were still in
+// flight at the end of the grace period, and had their connections closed.
+var ShutdownTimedOut = errors.New("requests were still in flight at the end of the grace period")
+
+// Listen opens a TCP listener on the server's address, over TLS when
+// configured, and serves connections from it like [Server.Serve]. It returns
+// an error if it can't listen at all.
+func (s *Server) Listen(ctx context.Context) error {
 	s.Logger = s.Logger.Named("server")
 	s.Logger.Sugar().Infow("opening tcp socket", "addr", s.Addr)
 
@@ -27,7 +107,12 @@ func (s *Server) Listen() error {
 	var err error
 
 	if s.TLS != nil {
-		lis, err = tls.Listen("tcp", s.Addr, s.TLS)
+		// Clients are offered HTTP/2 unless the configuration says otherwise.
+		config := s.TLS.Clone()
+		if len(config.NextProtos) == 0 {
+			config.NextProtos = []string{http2.NextProtoTLS, "http/1.1"}
+		}
+		lis, err = tls.Listen("tcp", s.Addr, config)
 	} else {
 		lis, err = net.Listen("tcp", s.Addr)
 	}
@@ -35,64 +120,359 @@ func (s *Server) Listen() error {
 	if err != nil {
 		return err
 	}
+
+	return s.Serve(ctx, lis)
+}
+
+// Serve serves connections accepted from lis until ctx is cancelled, then
+// shuts down: it stops accepting connections, closes idle ones, and waits for
+// requests in flight to finish within the grace period. It returns nil once
+// every connection has been closed, or [ShutdownTimedOut] if the grace period
+// ran out first.
+func (s *Server) Serve(ctx context.Context, lis net.Listener) error {
 	defer lis.Close()
 
+	if err := s.configureHTTP2(); err != nil {
+		return err
+	}
+
+	// Closing the listener is what interrupts Accept.
+	go func() {
+		<-ctx.Done()
+		lis.Close()
+	}()
+
 	for {
 		conn, err := lis.Accept()
 		if err != nil {
-			s.Logger.Error("failed to accept connection, closing...")
-			conn.Close()
+			if ctx.Err() != nil {
+				break
+			}
+			s.Logger.Sugar().Errorf("failed to accept connection: %v", err.Error())
+			time.Sleep(acceptRetryDelay)
 			continue
 		}
-		now := time.Now()
 
-		if err := conn.SetReadDeadline(now.Add(5 * time.Second)); err != nil {
-			s.Logger.Error("failed to set read deadline, closing...")
-			conn.Close()
-			continue
-		}
-		if err := conn.SetWriteDeadline(now.Add(30 * time.Second)); err != nil {
-			s.Logger.Error("failed to set write deadline, closing...")
+		s.track(conn)
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer s.untrack(conn)
+			s.handleConnection(conn)
+		}()
+	}
+
+	return s.shutdown()
+}
+
+// shutdown closes idle connections, and waits for the others to finish their
+// request within the grace period before closing them too.
+func (s *Server) shutdown() error {
+	s.mu.Lock()
+	s.shuttingDown = true
+	active := 0
+	for conn, st := range s.conns {
+		active += st.requests
+		if st.requests == 0 && !st.http2 {
 			conn.Close()
-			continue
 		}
+	}
+	s.mu.Unlock()
+
+	// HTTP/2 connections are told to go away instead, so clients don't send
+	// requests that would never be served. They close once their streams are
+	// done. Shutting down the base server is what signals them, as it has
+	// neither listeners nor connections of its own.
+	_ = s.http2Base.Shutdown(context.Background())
+
+	s.Logger.Sugar().Infow("shutting down", "inFlight", active)
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-time.After(durationOr(s.GracePeriod, DefaultGracePeriod)):
+	}
+
+	// Handlers still running are left behind, as nothing can stop them.
+	s.mu.Lock()
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+
+	return ShutdownTimedOut
+}
+
+func (s *Server) track(c net.Conn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conns == nil {
+		s.conns = map[net.Conn]*connState{}
+	}
+	s.conns[c] = &connState{}
+}
+
+func (s *Server) untrack(c net.Conn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.conns, c)
+}
+
+// beginRequest marks an HTTP/1 connection as serving a request. It returns
+// false if the request may not start because the server is shutting down.
+func (s *Server) beginRequest(c net.Conn) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.shuttingDown {
+		return false
+	}
+	s.conns[c].requests++
+
+	return true
+}
+
+// endRequest marks an HTTP/1 connection as done serving a request.
+func (s *Server) endRequest(c net.Conn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.conns[c].requests--
+}
+
+// beginStream marks an HTTP/2 connection as serving one more request, which
+// shutting down waits for like it waits for connections. Requests are served
+// even when shutting down, since they were sent before the client was told to
+// go away. It returns false if the connection has already been closed, as
+// stream handlers may outlive it when the client disconnects, in which case
+// there's nothing to mark.
+func (s *Server) beginStream(c net.Conn) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st, ok := s.conns[c]
+	if !ok {
+		return false
+	}
+	st.requests++
+
+	// The connection still counts in wg, so it can't be waited on yet.
+	s.wg.Add(1)
+
+	return true
+}
+
+// endStream marks an HTTP/2 connection as done serving a request begun with
+// beginStream.
+func (s *Server) endStream(c net.Conn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-		go s.handleConnection(conn)
+	if st, ok := s.conns[c]; ok {
+		st.requests--
 	}
+	s.wg.Done()
 }
 
+// setHTTP2 marks a connection as speaking HTTP/2.
+func (s *Server) setHTTP2(c net.Conn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.conns[c].http2 = true
+}
+
+func (s *Server) isShuttingDown() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.shuttingDown
+}
+
+// handleConnection serves requests read from c until the client closes it,
+// asks for it to be closed, or stays idle for too long. Pipelined requests are
+// served one after the other, and their responses written in the same order.
 func (s *Server) handleConnection(c net.Conn) {
-  defer c.Close()
+	// Hijacked connections belong to the handler that took them over.
+	hijacked := false
+	defer func() {
+		if !hijacked {
+			c.Close()
+		}
+	}()
+
 	reader := bufio.NewReader(c)
+	writer := bufio.NewWriter(c)
+	rw := bufio.NewReadWriter(reader, writer)
 
-	r, err := http.ReadRequest(reader)
-	if err != nil {
-		if err.Error() != "EOF" {
-			s.Logger.Sugar().Errorf("failed to read request: %v", err.Error())
+	// The protocol of TLS connections is known once the handshake is done,
+	// which has to happen within the read timeout.
+	if tc, ok := c.(*tls.Conn); ok {
+		if err := c.SetDeadline(time.Now().Add(durationOr(s.ReadTimeout, DefaultReadTimeout))); err != nil {
+			s.Logger.Sugar().Errorf("failed to set handshake deadline: %v", err.Error())
+			return
+		}
+		if err := tc.Handshake(); err != nil {
+			if !closedOrTimedOut(err) {
+				s.Logger.Sugar().Errorf("failed tls handshake: %v", err.Error())
+			}
+			return
+		}
+		if tc.ConnectionState().NegotiatedProtocol == http2.NextProtoTLS {
+			s.serveHTTP2(c, reader)
+			return
 		}
-		return
 	}
 
-	if res := validRequest(r); res != nil {
-		if err := res.Write(c); err != nil {
+	for first := true; ; first = false {
+		// The first request is expected right away, later ones may take until
+		// the idle timeout to start arriving. Until then the connection is
+		// idle, and closed right away when shutting down.
+		wait := durationOr(s.IdleTimeout, DefaultIdleTimeout)
+		if first {
+			wait = durationOr(s.ReadTimeout, DefaultReadTimeout)
+		}
+		if err := c.SetReadDeadline(time.Now().Add(wait)); err != nil {
+			s.Logger.Sugar().Errorf("failed to set idle deadline: %v", err.Error())
+			return
+		}
+		if _, err := reader.Peek(1); err != nil {
+			return
+		}
+
+		if first && s.H2C && hasHTTP2Preface(reader) {
+			s.serveHTTP2(c, reader)
+			return
+		}
+
+		if !s.beginRequest(c) {
+			return
+		}
+
+		if err := c.SetReadDeadline(time.Now().Add(durationOr(s.ReadTimeout, DefaultReadTimeout))); err != nil {
+			s.Logger.Sugar().Errorf("failed to set read deadline: %v", err.Error())
+			return
+		}
+
+		r, err := http.ReadRequest(reader)
+		if err != nil {
+			if !closedOrTimedOut(err) {
+				s.Logger.Sugar().Errorf("failed to read request: %v", err.Error())
+			}
+			return
+		}
+
+		if err := c.SetWriteDeadline(time.Now().Add(durationOr(s.WriteTimeout, DefaultWriteTimeout))); err != nil {
+			s.Logger.Sugar().Errorf("failed to set write deadline: %v", err.Error())
+			return
+		}
+
+		// ReadRequest leaves the address of the client empty, unlike net/http.
+		r.RemoteAddr = c.RemoteAddr().String()
+
+		var closing bool
+		closing, hijacked, err = s.serve(c, rw, r)
+		if hijacked {
+			return
+		}
+		if err != nil {
 			s.Logger.Sugar().Errorf("failed to write response: %v", err.Error())
 			return
 		}
-		return
+
+		// Responses to pipelined requests that have already arrived are
+		// written together.
+		if closing || reader.Buffered() == 0 {
+			if err := writer.Flush(); err != nil {
+				s.Logger.Sugar().Errorf("failed to write response: %v", err.Error())
+				return
+			}
+		}
+
+		if closing {
+			return
+		}
+		s.endRequest(c)
+
+		// Shutdown may have started while the request was in flight, after
+		// idle connections were closed.
+		if s.isShuttingDown() {
+			return
+		}
+	}
+}
+
+// serve handles a single request, writing its response to rw. It returns
+// whether the connection must be closed afterwards, because either side asked
+// for it, and whether the handler hijacked the connection.
+func (s *Server) serve(c net.Conn, rw *bufio.ReadWriter, r *http.Request) (closing bool, hijacked bool, err error) {
+	if res := validRequest(r); res != nil {
+		// The body of an invalid request may not even have been sent, so the
+		// connection can't be trusted to be at the start of the next one.
+		res.Request = r
+		res.Close = true
+		return true, false, res.Write(rw)
+	}
+
+	w := NewOSResponseWriter(c, rw, r, r.Close)
+
+	// Streamed responses may take longer than the write timeout in total, as
+	// long as they keep flushing.
+	w.onFlush = func() {
+		_ = c.SetWriteDeadline(time.Now().Add(durationOr(s.WriteTimeout, DefaultWriteTimeout)))
 	}
 
-	w := NewOSResponseWriter()
 	s.Handler.ServeHTTP(w, r)
+	if w.Hijacked() {
+		return false, true, nil
+	}
 
-	res := NewResponse()
-	res.StatusCode = w.statusCode
-	res.Header = w.header
-	res.Body = io.NopCloser(bytes.NewReader(w.body))
-	res = SetBody(res, w.body)
+	// Whether the server is shutting down is only known once the handler is
+	// done.
+	if headerHasToken(w.Header(), "Connection", "close") || s.isShuttingDown() {
+		w.SetClose()
+	}
 
-	err = res.Write(c)
-	if err != nil {
-		s.Logger.Sugar().Errorf("failed to write response: %v", err.Error())
-		return
+	// The next request starts where this one's body ends, so whatever the
+	// handler left unread has to go first. When there is too much of it, the
+	// connection is closed instead, which the response announces unless it has
+	// already been sent.
+	if !w.Closing() {
+		if n, _ := io.CopyN(io.Discard, r.Body, maxDrainBytes+1); n > maxDrainBytes {
+			w.SetClose()
+			closing = true
+		} else {
+			r.Body.Close()
+		}
 	}
+
+	if err := w.Finish(); err != nil {
+		return true, false, err
+	}
+
+	return closing || w.Closing(), false, nil
+}
+
+// durationOr returns d, or fallback if d is not set.
+func durationOr(d time.Duration, fallback time.Duration) time.Duration {
+	if d <= 0 {
+		return fallback
+	}
+	return d
+}
+
+// closedOrTimedOut reports whether err only means the connection was closed
+// by the client or went quiet, which is how idle connections normally end.
+func closedOrTimedOut(err error) bool {
+	var ne net.Error
+	return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, net.ErrClosed) || (errors.As(err, &ne) && ne.Timeout())
 }
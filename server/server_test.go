@@ -0,0 +1,208 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/net/http2"
+)
+
+// startServer serves handler on a local port until the returned cancel
+// function is called, sending what Serve returned on the channel.
+func startServer(t *testing.T, handler http.Handler, gracePeriod time.Duration) (string, context.CancelFunc, <-chan error) {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &Server{Handler: handler, Logger: zap.NewNop(), GracePeriod: gracePeriod, H2C: true}
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- s.Serve(ctx, lis)
+	}()
+
+	return lis.Addr().String(), cancel, done
+}
+
+func TestShutdownDrainsRequestsInFlight(t *testing.T) {
+	started := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(500 * time.Millisecond)
+		_, _ = io.WriteString(w, "done")
+	})
+
+	addr, cancel, done := startServer(t, handler, 5*time.Second)
+
+	type result struct {
+		res  *http.Response
+		body string
+		err  error
+	}
+	results := make(chan result, 1)
+	go func() {
+		// A client keeping connections alive, which must be told to close.
+		client := &http.Client{Transport: &http.Transport{}}
+		res, err := client.Get("http://" + addr + "/")
+		if err != nil {
+			results <- result{err: err}
+			return
+		}
+		defer res.Body.Close()
+		body, err := io.ReadAll(res.Body)
+		results <- result{res: res, body: string(body), err: err}
+	}()
+
+	<-started
+	shutdownAt := time.Now()
+	cancel()
+
+	r := <-results
+	if r.err != nil {
+		t.Fatalf("request in flight failed: %v", r.err)
+	}
+	if r.body != "done" {
+		t.Errorf("body = %q, want %q", r.body, "done")
+	}
+	if !r.res.Close {
+		t.Error("response to a request in flight when shutting down doesn't close the connection")
+	}
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Serve returned %v, want nil", err)
+		}
+		if elapsed := time.Since(shutdownAt); elapsed > 2*time.Second {
+			t.Errorf("shutting down took %v, want it to end with the request", elapsed)
+		}
+	case <-time.After(4 * time.Second):
+		t.Fatal("Serve didn't return after the request finished")
+	}
+
+	if c, err := net.DialTimeout("tcp", addr, time.Second); err == nil {
+		c.Close()
+		t.Error("new connections are accepted after shutting down")
+	}
+}
+
+func TestShutdownClosesIdleConnections(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "ok")
+	})
+
+	addr, cancel, done := startServer(t, handler, 5*time.Second)
+
+	client := &http.Client{Transport: &http.Transport{}}
+	res, err := client.Get("http://" + addr + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = io.Copy(io.Discard, res.Body)
+	res.Body.Close()
+
+	cancel()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Serve returned %v, want nil", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Serve waited for an idle connection")
+	}
+}
+
+func TestShutdownTimesOut(t *testing.T) {
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+
+	started := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	})
+
+	addr, cancel, done := startServer(t, handler, 200*time.Millisecond)
+
+	go func() {
+		res, err := http.Get("http://" + addr + "/")
+		if err == nil {
+			res.Body.Close()
+		}
+	}()
+
+	<-started
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, ShutdownTimedOut) {
+			t.Errorf("Serve returned %v, want %v", err, ShutdownTimedOut)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Serve didn't return at the end of the grace period")
+	}
+}
+
+func TestShutdownWaitsForHTTP2HandlersOutlivingTheirConnection(t *testing.T) {
+	started := make(chan struct{})
+	finished := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(500 * time.Millisecond)
+		_, _ = io.WriteString(w, "done")
+		close(finished)
+	})
+
+	addr, cancel, done := startServer(t, handler, 5*time.Second)
+
+	var conn net.Conn
+	client := &http.Client{Transport: &http2.Transport{
+		AllowHTTP: true,
+		DialTLS: func(network, addr string, _ *tls.Config) (net.Conn, error) {
+			c, err := net.Dial(network, addr)
+			conn = c
+			return c, err
+		},
+	}}
+	go func() {
+		res, err := client.Get("http://" + addr + "/")
+		if err == nil {
+			res.Body.Close()
+		}
+	}()
+
+	// The client disconnects while the request is in flight, which ends the
+	// connection before the handler.
+	<-started
+	conn.Close()
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Serve returned %v, want nil", err)
+		}
+		select {
+		case <-finished:
+		default:
+			t.Error("Serve returned before the handler finished")
+		}
+	case <-time.After(4 * time.Second):
+		t.Fatal("Serve didn't return after the handler finished")
+	}
+}
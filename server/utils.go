@@ -1,6 +1,9 @@
 package server
 
-import "net/http"
+import (
+	"net/http"
+	"strings"
+)
 
 func validRequest(req *http.Request) *http.Response {
 	res := NewResponse()
@@ -30,3 +33,17 @@ func validMethod(method string) bool {
 		method == http.MethodHead)
 	return isValid
 }
+
+// headerHasToken reports whether the comma-separated values of a header, such
+// as Connection, include token.
+func headerHasToken(h http.Header, key string, token string) bool {
+	for _, v := range h.Values(key) {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,271 @@
+// Package snapshot saves the content of every repository to a versioned file,
+// and restores in-memory repositories from it. It lets the memory storage
+// driver keep its data across restarts.
+package snapshot
+
+import (
+	"context"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/h4n-openschool/api/audit"
+	"github.com/h4n-openschool/api/inbox"
+	"github.com/h4n-openschool/api/models"
+	"github.com/h4n-openschool/api/outbox"
+	apiKeyRepos "github.com/h4n-openschool/api/repos/apikeys"
+	classRepos "github.com/h4n-openschool/api/repos/classes"
+	gradeRepos "github.com/h4n-openschool/api/repos/grades"
+	studentRepos "github.com/h4n-openschool/api/repos/students"
+	teacherRepos "github.com/h4n-openschool/api/repos/teachers"
+	tokenRepos "github.com/h4n-openschool/api/repos/tokens"
+	totpRepos "github.com/h4n-openschool/api/repos/totp"
+	"github.com/h4n-openschool/api/storage"
+	"github.com/h4n-openschool/api/utils"
+	"go.uber.org/zap"
+)
+
+// Version is the format version written to new snapshots. Bump it whenever
+// the layout of [Snapshot] or the models it holds changes incompatibly.
+const Version = 1
+
+// pageSize is the number of records read per repository call while taking a
+// snapshot.
+const pageSize = 100
+
+var (
+	UnsupportedVersion = errors.New("unsupported snapshot version")
+)
+
+// Snapshot is the content of a snapshot file. It is encoded with
+// [encoding/gob], which, unlike JSON, keeps fields such as
+// [models.Teacher.PasswordHash] that are hidden from API responses.
+type Snapshot struct {
+	// Version is the format version the snapshot was written with.
+	Version int
+
+	// TakenAt is the time the snapshot was taken.
+	TakenAt time.Time
+
+	Classes  []models.Class
+	Students []models.Student
+	Teachers []models.Teacher
+	Grades   []models.Grade
+
+	// GradeVersions holds the history of every grade.
+	GradeVersions []models.GradeVersion
+
+	// Outbox holds the events that had not been delivered to the message bus
+	// yet, so they are not lost across restarts.
+	Outbox []outbox.Message
+
+	// Totp holds the two-factor authentication settings of teachers, so a
+	// restart doesn't silently turn it off.
+	Totp []models.Totp
+
+	// ApiKeys holds the API keys of teachers, so scripts keep working across
+	// restarts.
+	ApiKeys []models.ApiKey
+
+	// Audit holds the audit log, which must never be lost.
+	Audit []audit.Record
+
+	// Tokens holds the issued tokens and revocations, so a restart neither
+	// ends every session nor lets revoked tokens be used again.
+	Tokens tokenRepos.State
+
+	// Inbox holds the ids of the applied commands, so commands the broker
+	// delivers again after a restart are not applied twice.
+	Inbox []string
+}
+
+// Take reads every record out of the repositories into a new snapshot. The
+// records are read consistently, so the snapshot never holds half of a change.
+func Take(repos *storage.Repositories) (*Snapshot, error) {
+	s := &Snapshot{Version: Version, TakenAt: time.Now().UTC()}
+
+	err := repos.ReadConsistently(func(tx *storage.Repositories) error {
+		return s.read(tx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// read reads every record out of the repositories into the snapshot.
+func (s *Snapshot) read(repos *storage.Repositories) error {
+	var err error
+	if s.Classes, err = readAll(repos.Classes.GetAll); err != nil {
+		return fmt.Errorf("failed to read classes: %w", err)
+	}
+	if s.Students, err = readAll(repos.Students.GetAll); err != nil {
+		return fmt.Errorf("failed to read students: %w", err)
+	}
+	if s.Teachers, err = readAll(repos.Teachers.GetAll); err != nil {
+		return fmt.Errorf("failed to read teachers: %w", err)
+	}
+
+	for _, class := range s.Classes {
+		classId := class.Id
+		grades, err := readAll(func(pq utils.PaginationQuery) ([]models.Grade, error) {
+			return repos.Grades.GetAll(classId, pq)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to read grades: %w", err)
+		}
+		s.Grades = append(s.Grades, grades...)
+	}
+
+	for _, grade := range s.Grades {
+		versions, err := repos.Grades.History(grade.Id)
+		if err != nil {
+			return fmt.Errorf("failed to read grade history: %w", err)
+		}
+		s.GradeVersions = append(s.GradeVersions, versions...)
+	}
+
+	if s.Outbox, err = repos.Outbox.All(); err != nil {
+		return fmt.Errorf("failed to read outbox: %w", err)
+	}
+	if s.Totp, err = repos.Totp.All(); err != nil {
+		return fmt.Errorf("failed to read two-factor authentication settings: %w", err)
+	}
+	if s.ApiKeys, err = repos.ApiKeys.All(); err != nil {
+		return fmt.Errorf("failed to read API keys: %w", err)
+	}
+	if s.Audit, err = repos.Audit.All(); err != nil {
+		return fmt.Errorf("failed to read audit log: %w", err)
+	}
+	if s.Tokens, err = repos.Tokens.State(); err != nil {
+		return fmt.Errorf("failed to read tokens: %w", err)
+	}
+	if s.Inbox, err = repos.Inbox.All(); err != nil {
+		return fmt.Errorf("failed to read inbox: %w", err)
+	}
+
+	return nil
+}
+
+// Load reads a snapshot from a file, rejecting versions it can't restore.
+func Load(path string) (*Snapshot, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var s Snapshot
+	if err := gob.NewDecoder(f).Decode(&s); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot %v: %w", path, err)
+	}
+
+	if s.Version != Version {
+		return nil, fmt.Errorf("%w: %v", UnsupportedVersion, s.Version)
+	}
+
+	return &s, nil
+}
+
+// Save writes the snapshot to a file. The file is replaced atomically, so a
+// crash while saving never leaves a half-written snapshot behind.
+func (s *Snapshot) Save(path string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := gob.NewEncoder(tmp).Encode(s); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// Repositories creates in-memory repositories holding the snapshot's records.
+func (s *Snapshot) Repositories() *storage.Repositories {
+	return &storage.Repositories{
+		Classes:  classRepos.NewInMemoryClassRepositoryFrom(s.Classes),
+		Students: studentRepos.NewInMemoryStudentRepositoryFrom(s.Students),
+		Teachers: teacherRepos.NewInMemoryTeacherRepositoryFrom(s.Teachers),
+		Grades:   gradeRepos.NewInMemoryGradeRepositoryFrom(s.Grades, s.GradeVersions),
+		Tokens:   tokenRepos.NewInMemoryTokenRepositoryFrom(s.Tokens),
+		Totp:     totpRepos.NewInMemoryTotpRepositoryFrom(s.Totp),
+		ApiKeys:  apiKeyRepos.NewInMemoryApiKeyRepositoryFrom(s.ApiKeys),
+		Outbox:   outbox.NewInMemoryStoreFrom(s.Outbox),
+		Inbox:    inbox.NewInMemoryStoreFrom(s.Inbox),
+		Audit:    audit.NewInMemoryStoreFrom(s.Audit),
+		Driver:   storage.DriverMemory,
+	}
+}
+
+// Run saves a snapshot of the repositories to path every interval, and once
+// more when ctx is cancelled. It blocks until the final snapshot is written.
+// interval must be positive.
+func Run(ctx context.Context, repos *storage.Repositories, path string, interval time.Duration, logger *zap.Logger) {
+	logger = logger.Named("snapshot")
+
+	save := func() {
+		s, err := Take(repos)
+		if err == nil {
+			err = s.Save(path)
+		}
+
+		if err != nil {
+			logger.Sugar().Errorw("failed to save snapshot", "path", path, "error", err)
+			return
+		}
+
+		logger.Sugar().Debugw("saved snapshot", "path", path)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			save()
+		case <-ctx.Done():
+			save()
+			return
+		}
+	}
+}
+
+// readAll pages through a repository's GetAll method until it runs dry.
+func readAll[T any](getAll func(pq utils.PaginationQuery) ([]T, error)) ([]T, error) {
+	var all []T
+
+	pq := utils.NewPaginationQuery()
+	pq.PerPage = pageSize
+
+	for {
+		items, err := getAll(pq)
+		if err != nil {
+			return nil, err
+		}
+
+		all = append(all, items...)
+		if len(items) < pq.PerPage {
+			return all, nil
+		}
+
+		pq.Page++
+	}
+}
@@ -0,0 +1,291 @@
+// Package storage selects and opens the data store that backs the repository
+// interfaces, based on the `storage.*` configuration keys.
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+
+	"github.com/h4n-openschool/api/audit"
+	"github.com/h4n-openschool/api/inbox"
+	"github.com/h4n-openschool/api/migrations"
+	"github.com/h4n-openschool/api/outbox"
+	apiKeyRepos "github.com/h4n-openschool/api/repos/apikeys"
+	classRepos "github.com/h4n-openschool/api/repos/classes"
+	gradeRepos "github.com/h4n-openschool/api/repos/grades"
+	studentRepos "github.com/h4n-openschool/api/repos/students"
+	teacherRepos "github.com/h4n-openschool/api/repos/teachers"
+	tokenRepos "github.com/h4n-openschool/api/repos/tokens"
+	totpRepos "github.com/h4n-openschool/api/repos/totp"
+	_ "github.com/lib/pq"
+	_ "modernc.org/sqlite"
+)
+
+const (
+	// DriverMemory keeps every record in memory, seeded with generated data.
+	DriverMemory = "memory"
+
+	// DriverPostgres stores records in a PostgreSQL database.
+	DriverPostgres = "postgres"
+
+	// DriverSqlite stores records in an embedded SQLite database file.
+	DriverSqlite = "sqlite"
+)
+
+var (
+	UnsupportedDriver = errors.New("unsupported storage driver")
+)
+
+// Config holds the `storage.*` configuration keys.
+type Config struct {
+	// Driver is the name of the storage driver, one of the Driver* constants.
+	Driver string
+
+	// DSN is the connection string passed to the SQL driver.
+	DSN string
+
+	// Path is the database file used by the SQLite driver.
+	Path string
+}
+
+// Repositories groups one implementation of every repository interface, all
+// backed by the same data store.
+type Repositories struct {
+	Classes  classRepos.ClassRepository
+	Students studentRepos.StudentRepository
+	Teachers teacherRepos.TeacherRepository
+	Grades   gradeRepos.GradeRepository
+
+	// Tokens holds refresh tokens and revoked access tokens.
+	Tokens tokenRepos.TokenRepository
+
+	// Totp holds the two-factor authentication settings of teachers.
+	Totp totpRepos.TotpRepository
+
+	// ApiKeys holds the API keys of teachers.
+	ApiKeys apiKeyRepos.ApiKeyRepository
+
+	// Outbox holds domain events until they are delivered to the message bus.
+	Outbox outbox.Store
+
+	// Inbox holds the ids of the commands applied from the message bus.
+	Inbox inbox.Store
+
+	// Audit holds the append-only log of changes made through the API.
+	Audit audit.Store
+
+	// DB is the underlying connection for SQL drivers, and nil otherwise.
+	DB *sql.DB
+
+	// Driver is the name of the storage driver backing the repositories.
+	Driver string
+
+	// mu keeps writes made through [Repositories.Atomically] out of
+	// [Repositories.ReadConsistently] with the memory driver, which has no
+	// transactions to isolate them.
+	mu sync.RWMutex
+}
+
+// Open creates the repositories for the configured driver. SQL drivers expect
+// their schema to have been created with the `migrate` command.
+func Open(cfg Config) (*Repositories, error) {
+	if cfg.Driver == "" || cfg.Driver == DriverMemory {
+		return NewInMemoryRepositories(), nil
+	}
+
+	db, err := OpenDB(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	repos := NewSqlRepositories(db)
+	repos.Driver = cfg.Driver
+
+	return repos, nil
+}
+
+// OpenDB connects to the database of a SQL storage driver.
+func OpenDB(cfg Config) (*sql.DB, error) {
+	var db *sql.DB
+	var err error
+
+	switch cfg.Driver {
+	case DriverPostgres:
+		db, err = sql.Open("postgres", cfg.DSN)
+	case DriverSqlite:
+		db, err = sql.Open("sqlite", sqliteDsn(cfg.Path))
+	default:
+		return nil, fmt.Errorf("%w: %v", UnsupportedDriver, cfg.Driver)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Driver == DriverSqlite {
+		// SQLite only allows a single writer, so serialize access through one
+		// connection rather than failing with SQLITE_BUSY under load.
+		db.SetMaxOpenConns(1)
+	}
+
+	if err := db.Ping(); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to connect to %v: %w", cfg.Driver, err)
+	}
+
+	return db, nil
+}
+
+// sqliteDsn builds the connection string for a SQLite database file, turning
+// on foreign key enforcement so enrolments and grades cannot point at classes
+// or students that no longer exist.
+func sqliteDsn(path string) string {
+	q := url.Values{}
+	q.Add("_pragma", "foreign_keys(1)")
+	q.Add("_pragma", "journal_mode(WAL)")
+	q.Add("_pragma", "busy_timeout(5000)")
+
+	return "file:" + path + "?" + q.Encode()
+}
+
+// NewSqlRepositories creates SQL-backed repositories sharing one connection.
+func NewSqlRepositories(db *sql.DB) *Repositories {
+	return &Repositories{
+		Classes:  classRepos.NewSqlClassRepository(db),
+		Students: studentRepos.NewSqlStudentRepository(db),
+		Teachers: teacherRepos.NewSqlTeacherRepository(db),
+		Grades:   gradeRepos.NewSqlGradeRepository(db),
+		Tokens:   tokenRepos.NewSqlTokenRepository(db),
+		Totp:     totpRepos.NewSqlTotpRepository(db),
+		ApiKeys:  apiKeyRepos.NewSqlApiKeyRepository(db),
+		Outbox:   outbox.NewSqlStore(db),
+		Inbox:    inbox.NewSqlStore(db),
+		Audit:    audit.NewSqlStore(db),
+		DB:       db,
+	}
+}
+
+// Atomically runs fn with repositories whose writes are committed together,
+// events added to the outbox and records appended to the audit log included,
+// or not at all when fn fails. This is what keeps an event or audit record
+// stored if and only if the write it describes is.
+//
+// With SQL drivers, fn must only use the repositories it is given, since using
+// the connection outside of the transaction could deadlock on SQLite. The
+// memory driver has no transactions, so fn gets the repositories as they are.
+func (r *Repositories) Atomically(fn func(tx *Repositories) error) error {
+	if r.DB == nil {
+		r.mu.RLock()
+		defer r.mu.RUnlock()
+
+		return fn(r)
+	}
+
+	return r.inTx(nil, fn)
+}
+
+// ReadConsistently runs fn with repositories that all read the same state,
+// unaffected by writes made meanwhile, such as to copy every record at once.
+//
+// With SQL drivers fn runs in a read-only transaction, and like with
+// [Repositories.Atomically] must only use the repositories it is given. The
+// memory driver instead keeps writes made through [Repositories.Atomically]
+// waiting until fn returns.
+func (r *Repositories) ReadConsistently(fn func(tx *Repositories) error) error {
+	if r.DB == nil {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		return fn(r)
+	}
+
+	return r.inTx(&sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}, fn)
+}
+
+// inTx runs fn with repositories bound to a new transaction, committing it if
+// fn succeeds.
+func (r *Repositories) inTx(opts *sql.TxOptions, fn func(tx *Repositories) error) error {
+	tx, err := r.DB.BeginTx(context.Background(), opts)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	err = fn(&Repositories{
+		Classes:  classRepos.NewSqlClassRepository(tx),
+		Students: studentRepos.NewSqlStudentRepository(tx),
+		Teachers: teacherRepos.NewSqlTeacherRepository(tx),
+		Grades:   gradeRepos.NewSqlGradeRepository(tx),
+		Tokens:   tokenRepos.NewSqlTokenRepository(tx),
+		Totp:     totpRepos.NewSqlTotpRepository(tx),
+		ApiKeys:  apiKeyRepos.NewSqlApiKeyRepository(tx),
+		Outbox:   outbox.NewSqlStore(tx),
+		Inbox:    inbox.NewSqlStore(tx),
+		Audit:    audit.NewSqlStore(tx),
+		Driver:   r.Driver,
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// NewInMemoryRepositories creates in-memory repositories filled with
+// generated records.
+func NewInMemoryRepositories() *Repositories {
+	// Instantiate a new in-memory Teacher repository, generating 10 records.
+	tr := teacherRepos.NewInMemoryTeacherRepository(10)
+
+	// Instantiate a new in-memory Class repository, generating 10 records
+	// taught by the teachers above.
+	cr := classRepos.NewInMemoryClassRepository(tr, 10)
+
+	// Instantiate a new in-memory Student repository, generating 30 records
+	// per class.
+	sr := studentRepos.NewInMemoryStudentRepository(cr, 30)
+
+	// Instantiate a new in-memory Grade repository, generating 1 record per student.
+	gr := gradeRepos.NewInMemoryGradeRepository(cr)
+
+	return &Repositories{
+		Classes:  cr,
+		Students: sr,
+		Teachers: tr,
+		Grades:   gr,
+		Tokens:   tokenRepos.NewInMemoryTokenRepository(),
+		Totp:     totpRepos.NewInMemoryTotpRepository(),
+		ApiKeys:  apiKeyRepos.NewInMemoryApiKeyRepository(),
+		Outbox:   outbox.NewInMemoryStore(),
+		Inbox:    inbox.NewInMemoryStore(),
+		Audit:    audit.NewInMemoryStore(),
+		Driver:   DriverMemory,
+	}
+}
+
+// PendingMigrations returns the schema migrations that have not been applied
+// to the database yet. It is always empty for the memory driver.
+func (r *Repositories) PendingMigrations() ([]migrations.Migration, error) {
+	if r.DB == nil {
+		return nil, nil
+	}
+
+	m, err := migrations.NewMigrator(r.DB, r.Driver)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.Pending()
+}
+
+// Close releases the underlying connection, if any.
+func (r *Repositories) Close() error {
+	if r.DB == nil {
+		return nil
+	}
+
+	return r.DB.Close()
+}
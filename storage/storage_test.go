@@ -0,0 +1,15 @@
+package storage_test
+
+import (
+	"testing"
+
+	"github.com/h4n-openschool/api/repos/repostest"
+)
+
+func TestSqliteForeignKeys(t *testing.T) {
+	repostest.TestForeignKeys(t, repostest.Sqlite)
+}
+
+func TestPostgresForeignKeys(t *testing.T) {
+	repostest.TestForeignKeys(t, repostest.Postgres)
+}
@@ -27,6 +27,11 @@ func ErrorHandler(logger *zap.Logger) gin.HandlerFunc {
           Message: message,
         }
 
+        // Handlers attach a reason for clients to act on as the error's meta.
+        if reason, ok := err.Meta.(string); ok {
+          e.Reason = &reason
+        }
+
         c.JSON(status, e)
         c.Abort()
       //}
@@ -2,42 +2,144 @@ package utils
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v4"
 )
 
-var (
-  JwtSigningKey = "lol u thought this would be secure"
+// The audiences tokens are issued for. Only tokens for [AudienceServer] are
+// accepted by [AuthenticateMiddleware], so other tokens signed with the same
+// keys can't be used to call the API.
+const (
+	// AudienceServer is in the `aud` claim of access tokens.
+	AudienceServer = "server"
+
+	// AudienceInvitation is the `aud` claim of invitation tokens, which can
+	// only activate an account.
+	AudienceInvitation = "invitation"
+
+	// AudienceLoginChallenge is the `aud` claim of the tokens the second step
+	// of a login with two-factor authentication is made with.
+	AudienceLoginChallenge = "login-challenge"
+
+	// AudienceOidcState is the `aud` claim of the cookie holding the state of
+	// a login with an identity provider.
+	AudienceOidcState = "oidc-state"
 )
 
 type UserClaims struct {
 	jwt.RegisteredClaims
+
+	// Role is the role of the user when the token was issued. Authorization
+	// decisions use the user's current role instead, so that changing a role
+	// takes effect immediately.
+	Role string `json:"role,omitempty"`
+
+	// SessionId is the family of the refresh tokens issued alongside the
+	// token. Revoking the family revokes the token too.
+	SessionId string `json:"sid,omitempty"`
+}
+
+// RevocationList tells whether an access token has been revoked before it
+// expired.
+type RevocationList interface {
+	// IsRevoked reports whether the token with the given `jti` claim, or the
+	// session with the given `sid` claim, has been revoked.
+	IsRevoked(tokenId string, sessionId string) (bool, error)
+}
+
+// ApiKeyStore finds the API keys requests are made with.
+type ApiKeyStore interface {
+	// LookupApiKey returns the id, owner and scopes of the unrevoked key with
+	// the given hash. The ids are empty if there is no such key.
+	LookupApiKey(hash string) (keyId string, userId string, scopes []string, err error)
+}
+
+// AuthenticateMiddleware checks the credentials of requests that carry them,
+// and records who the request is from. Bearer tokens are checked against the
+// keys and the revocation list, and API keys against the key store.
+func AuthenticateMiddleware(keys *KeySet, revoked RevocationList, apiKeys ApiKeyStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authenticate(c, keys, revoked, apiKeys)
+	}
+}
+
+func authenticate(c *gin.Context, keys *KeySet, revoked RevocationList, apiKeys ApiKeyStore) {
+	token := c.GetHeader("Authorization")
+
+	if token != "" {
+		parts := strings.SplitN(token, " ", 2)
+		if len(parts) != 2 {
+			_ = c.AbortWithError(401, errors.New("Invalid token type"))
+			return
+		}
+
+		switch parts[0] {
+		case "Bearer":
+			authenticateBearer(c, parts[1], keys, revoked)
+		case "ApiKey":
+			authenticateApiKey(c, parts[1], apiKeys)
+		default:
+			_ = c.AbortWithError(401, errors.New("Invalid token type"))
+		}
+
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	c.Next()
 }
 
-func AuthenticateMiddleware(c *gin.Context) {
-  token := c.GetHeader("Authorization")
+func authenticateBearer(c *gin.Context, token string, keys *KeySet, revoked RevocationList) {
+	claims := UserClaims{}
+	t, err := jwt.ParseWithClaims(token, &claims, keys.Keyfunc)
+
+	if err != nil {
+		_ = c.Error(gin.Error{Err: err, Type: gin.ErrorTypePrivate})
+		_ = c.AbortWithError(401, errors.New("you are unauthenticated"))
+		return
+	}
 
-  if token != "" {
-    parts := strings.Split(token, " ")
-    if parts[0] != "Bearer" {
-      _ = c.AbortWithError(401, errors.New("Invalid token type"))
-    }
+	if !claims.VerifyAudience(AudienceServer, true) {
+		_ = c.AbortWithError(401, errors.New("you are unauthenticated"))
+		return
+	}
 
-    claims := UserClaims{}
-    t, err := jwt.ParseWithClaims(parts[1], &claims, func (t *jwt.Token) (interface{}, error) {
-      return []byte(JwtSigningKey), nil
-    })
+	isRevoked, err := revoked.IsRevoked(claims.ID, claims.SessionId)
+	if err != nil {
+		_ = c.AbortWithError(500, fmt.Errorf("failed to check token revocation: %w", err))
+		return
+	}
+	if isRevoked {
+		_ = c.AbortWithError(401, errors.New("your token has been revoked"))
+		return
+	}
+
+	c.Set("auth.token", t)
+	c.Set("auth.claims", &claims)
+	c.Set("auth.userId", claims.Subject)
+	c.Set("auth.role", claims.Role)
+}
 
-    if err != nil {
-      _ = c.Error(gin.Error{Err: err, Type: gin.ErrorTypePrivate})
-      _ = c.AbortWithError(401, errors.New("you are unauthenticated"))
-    }
+// authenticateApiKey records the owner of an API key. The key's scopes are
+// recorded as `auth.scopes`, which limit the operations the request may
+// perform on top of the owner's role.
+func authenticateApiKey(c *gin.Context, key string, apiKeys ApiKeyStore) {
+	keyId, userId, scopes, err := apiKeys.LookupApiKey(HashToken(key))
+	if err != nil {
+		_ = c.AbortWithError(500, fmt.Errorf("failed to check API key: %w", err))
+		return
+	}
 
-    c.Set("auth.token", t)
-    c.Set("auth.userId", claims.Subject)
-  }
+	if keyId == "" {
+		_ = c.AbortWithError(401, errors.New("you are unauthenticated"))
+		return
+	}
 
-  c.Next()
+	c.Set("auth.apiKeyId", keyId)
+	c.Set("auth.userId", userId)
+	c.Set("auth.scopes", scopes)
 }
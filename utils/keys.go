@@ -0,0 +1,258 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/h4n-openschool/api/api"
+)
+
+// The signing algorithms supported for tokens.
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+var (
+	NoSigningKey        = errors.New("no signing key configured")
+	UnknownKeyId        = errors.New("unknown key id")
+	UnexpectedAlgorithm = errors.New("unexpected signing algorithm")
+)
+
+// KeyConfig describes a single token key, as read from the configuration.
+type KeyConfig struct {
+	// Id is the key id put in the `kid` header of tokens signed with the key.
+	Id string `mapstructure:"id"`
+
+	// Algorithm is one of HS256, RS256 or EdDSA.
+	Algorithm string `mapstructure:"algorithm"`
+
+	// Secret is the shared secret of an HS256 key.
+	Secret string `mapstructure:"secret"`
+
+	// PrivateKey is the path to the PEM-encoded private key of an RS256 or
+	// EdDSA key.
+	PrivateKey string `mapstructure:"private-key"`
+
+	// PublicKey is the path to the PEM-encoded public key of an RS256 or EdDSA
+	// key that is only used to check tokens, such as a key rotated out.
+	PublicKey string `mapstructure:"public-key"`
+}
+
+// Key is a loaded token key.
+type Key struct {
+	Id     string
+	Method jwt.SigningMethod
+
+	// signing is what tokens are signed with. It is nil for keys that can
+	// only check tokens.
+	signing any
+
+	// verifying is what token signatures are checked with.
+	verifying any
+}
+
+// CanSign reports whether the key holds the private part needed to sign.
+func (k *Key) CanSign() bool {
+	return k.signing != nil
+}
+
+// LoadKey loads the key described by cfg.
+func LoadKey(cfg KeyConfig) (*Key, error) {
+	if cfg.Id == "" {
+		return nil, errors.New("token keys need an id")
+	}
+
+	key := &Key{Id: cfg.Id}
+
+	switch cfg.Algorithm {
+	case AlgorithmHS256:
+		if cfg.Secret == "" {
+			return nil, fmt.Errorf("key %v: HS256 keys need a secret", cfg.Id)
+		}
+		key.Method = jwt.SigningMethodHS256
+		key.signing = []byte(cfg.Secret)
+		key.verifying = key.signing
+
+	case AlgorithmRS256:
+		key.Method = jwt.SigningMethodRS256
+		if err := key.loadPem(cfg, func(b []byte) (any, error) {
+			k, err := jwt.ParseRSAPrivateKeyFromPEM(b)
+			return k, err
+		}, func(b []byte) (any, error) {
+			k, err := jwt.ParseRSAPublicKeyFromPEM(b)
+			return k, err
+		}); err != nil {
+			return nil, err
+		}
+
+	case AlgorithmEdDSA:
+		key.Method = jwt.SigningMethodEdDSA
+		if err := key.loadPem(cfg, func(b []byte) (any, error) {
+			return jwt.ParseEdPrivateKeyFromPEM(b)
+		}, func(b []byte) (any, error) {
+			return jwt.ParseEdPublicKeyFromPEM(b)
+		}); err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, fmt.Errorf("key %v: unsupported algorithm %q", cfg.Id, cfg.Algorithm)
+	}
+
+	return key, nil
+}
+
+// loadPem reads the private key file of cfg if there is one, or else its
+// public key file.
+func (k *Key) loadPem(cfg KeyConfig, parsePrivate func([]byte) (any, error), parsePublic func([]byte) (any, error)) error {
+	path := cfg.PrivateKey
+	if path == "" {
+		path = cfg.PublicKey
+	}
+	if path == "" {
+		return fmt.Errorf("key %v: %v keys need a private-key or public-key file", cfg.Id, cfg.Algorithm)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("key %v: %w", cfg.Id, err)
+	}
+
+	if cfg.PrivateKey == "" {
+		if k.verifying, err = parsePublic(b); err != nil {
+			return fmt.Errorf("key %v: %w", cfg.Id, err)
+		}
+		return nil
+	}
+
+	if k.signing, err = parsePrivate(b); err != nil {
+		return fmt.Errorf("key %v: %w", cfg.Id, err)
+	}
+
+	signer, ok := k.signing.(crypto.Signer)
+	if !ok {
+		return fmt.Errorf("key %v: unsupported private key", cfg.Id)
+	}
+	k.verifying = signer.Public()
+
+	return nil
+}
+
+// KeySet holds the keys tokens are signed and checked with. The first key
+// signs new tokens, while every key checks tokens carrying its id, so keys can
+// be rotated without logging everybody out: add the new key in front, and
+// drop the old one once the tokens it signed have expired.
+type KeySet struct {
+	keys []*Key
+}
+
+// NewKeySet creates a new instance of [KeySet]. The first key must be able to
+// sign.
+func NewKeySet(keys ...*Key) (*KeySet, error) {
+	if len(keys) == 0 || !keys[0].CanSign() {
+		return nil, NoSigningKey
+	}
+
+	seen := map[string]bool{}
+	for _, k := range keys {
+		if seen[k.Id] {
+			return nil, fmt.Errorf("duplicate key id %v", k.Id)
+		}
+		seen[k.Id] = true
+	}
+
+	return &KeySet{keys: keys}, nil
+}
+
+// NewRandomKeySet creates a key set holding a single, randomly generated
+// HS256 key. Tokens signed with it stop working once the process exits.
+func NewRandomKeySet() (*KeySet, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+
+	return NewKeySet(&Key{
+		Id:        "random",
+		Method:    jwt.SigningMethodHS256,
+		signing:   secret,
+		verifying: secret,
+	})
+}
+
+// Sign creates a token holding claims, signed with the current key.
+func (ks *KeySet) Sign(claims jwt.Claims) (string, error) {
+	key := ks.keys[0]
+
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.Id
+
+	return token.SignedString(key.signing)
+}
+
+// Keyfunc finds the key a token was signed with by its `kid` header. Tokens
+// without one are checked with the current key. The token's algorithm must
+// match the key's, so a public key can never be used as an HMAC secret.
+func (ks *KeySet) Keyfunc(t *jwt.Token) (any, error) {
+	key := ks.keys[0]
+	if kid, ok := t.Header["kid"].(string); ok {
+		if key = ks.find(kid); key == nil {
+			return nil, fmt.Errorf("%w: %v", UnknownKeyId, kid)
+		}
+	}
+
+	if t.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("%w: %v", UnexpectedAlgorithm, t.Method.Alg())
+	}
+
+	return key.verifying, nil
+}
+
+func (ks *KeySet) find(id string) *Key {
+	for _, k := range ks.keys {
+		if k.Id == id {
+			return k
+		}
+	}
+
+	return nil
+}
+
+// Jwks returns the public keys of the set. HS256 keys are shared secrets, so
+// they are never included.
+func (ks *KeySet) Jwks() []api.Jwk {
+	jwks := []api.Jwk{}
+
+	for _, k := range ks.keys {
+		jwk := api.Jwk{Kid: k.Id, Use: "sig", Alg: k.Method.Alg()}
+
+		switch pub := k.verifying.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			n := base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			e := base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+			jwk.N, jwk.E = &n, &e
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			crv := "Ed25519"
+			x := base64.RawURLEncoding.EncodeToString(pub)
+			jwk.Crv, jwk.X = &crv, &x
+		default:
+			continue
+		}
+
+		jwks = append(jwks, jwk)
+	}
+
+	return jwks
+}
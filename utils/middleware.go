@@ -12,7 +12,7 @@ import (
 	"go.uber.org/zap"
 )
 
-func ApplyMiddleware(e *gin.Engine, logger *zap.Logger) *gin.Engine {
+func ApplyMiddleware(e *gin.Engine, logger *zap.Logger, keys *KeySet, revoked RevocationList, apiKeys ApiKeyStore) *gin.Engine {
 	e = applyCorsMiddleware(e)
 	e = applyValidationMiddleware(e)
 
@@ -24,7 +24,7 @@ func ApplyMiddleware(e *gin.Engine, logger *zap.Logger) *gin.Engine {
 	e.Use(ginzap.RecoveryWithZap(logger, true))
 
   // Configure authentication middleware (no authorization done here)
-  e.Use(AuthenticateMiddleware)
+  e.Use(AuthenticateMiddleware(keys, revoked, apiKeys))
 
 	return e
 }
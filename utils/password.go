@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// PasswordCost is the bcrypt cost passwords are hashed with.
+const PasswordCost = bcrypt.DefaultCost
+
+// MaxPasswordBytes is the length of the longest password bcrypt can hash.
+// The API limits passwords to 72 characters, which is more than 72 bytes
+// when some of them take several bytes in UTF-8.
+const MaxPasswordBytes = 72
+
+// HashPassword hashes a password for storage. Every password hash in the
+// application is created here, so the algorithm and cost only live in one
+// place.
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), PasswordCost)
+	if err != nil {
+		return "", err
+	}
+
+	return string(hash), nil
+}
+
+// CheckPassword reports whether password matches a hash created by
+// [HashPassword]. An empty hash, as held by accounts that never had a
+// password set, matches nothing.
+func CheckPassword(hash string, password string) bool {
+	if hash == "" {
+		return false
+	}
+
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+// HashToken returns the hash random tokens, such as refresh tokens and API
+// keys, are stored and looked up by. Unlike passwords, they are random enough
+// that a fast, unsalted hash suffices.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// SqlConn is satisfied by both [sql.DB] and [sql.Tx], so SQL repositories can
+// run their queries in a transaction they are handed.
+type SqlConn interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// SqlTx is a transaction begun with [BeginSql].
+type SqlTx interface {
+	SqlConn
+	Commit() error
+	Rollback() error
+}
+
+// BeginSql begins a transaction on conn. When conn is a transaction already,
+// the queries run in it instead, and committing or rolling it back is left to
+// whoever began it.
+func BeginSql(conn SqlConn) (SqlTx, error) {
+	switch c := conn.(type) {
+	case *sql.DB:
+		return c.Begin()
+	case *sql.Tx:
+		return nestedTx{c}, nil
+	}
+
+	return nil, fmt.Errorf("cannot begin a transaction on %T", conn)
+}
+
+// nestedTx runs in a transaction owned by someone else.
+type nestedTx struct {
+	*sql.Tx
+}
+
+func (nestedTx) Commit() error {
+	return nil
+}
+
+func (nestedTx) Rollback() error {
+	return nil
+}